@@ -111,8 +111,17 @@ func main() {
 	router.UserProgressRoute(e.Group("/user-progress"))
 	router.TemplatePathRoute(e.Group("/template-path"))
 	router.QuizRoute(e.Group("/quiz"))
+	router.QuestionBankRoute(e.Group("/question-bank"))
 	router.SkillKeywordRoute(e.Group("/skill-keyword"))
 	router.AppFeedbackRoute(e.Group("/app-feedback"))
+	router.ScormRoute(e.Group("/scorm"))
+	router.XapiRoute(e.Group("/xapi"))
+	router.NotificationRoute(e.Group("/notification"))
+	router.RecertificationRoute(e.Group("/recertification"))
+	router.PathAssignmentRuleRoute(e.Group("/path-assignment-rule"))
+
+	jobScheduler := router.NewScheduler(e.Logger)
+	jobScheduler.Start()
 
 	go func() {
 		if err := e.Start(":8080"); err != nil {
@@ -120,9 +129,10 @@ func main() {
 		}
 	}()
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
+	jobScheduler.Stop()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := e.Shutdown(ctx); err != nil {
@@ -1,21 +1,36 @@
 package router
 
 import (
+	"os"
+	"time"
+
 	af "orientation-training-api/internal/domains/appfeedback"
 	"orientation-training-api/internal/domains/auth"
+	cpr "orientation-training-api/internal/domains/courseprerequisite"
 	c "orientation-training-api/internal/domains/courses"
 	cskw "orientation-training-api/internal/domains/courseskillkeyword"
+	"orientation-training-api/internal/domains/enrollment"
 	lec "orientation-training-api/internal/domains/lectures"
 	mdi "orientation-training-api/internal/domains/moduleitem"
+	mic "orientation-training-api/internal/domains/moduleitemcompletion"
 	md "orientation-training-api/internal/domains/modules"
+	noti "orientation-training-api/internal/domains/notification"
+	par "orientation-training-api/internal/domains/pathassignmentrule"
+	"orientation-training-api/internal/domains/progression"
+	qb "orientation-training-api/internal/domains/questionbanks"
 	quiz "orientation-training-api/internal/domains/quizzes"
+	recert "orientation-training-api/internal/domains/recertification"
+	sc "orientation-training-api/internal/domains/scorm"
 	skey "orientation-training-api/internal/domains/skillkeyword"
 	tp "orientation-training-api/internal/domains/templatepaths"
 	uc "orientation-training-api/internal/domains/usercourse"
 	up "orientation-training-api/internal/domains/userprogress"
 	u "orientation-training-api/internal/domains/users"
+	xapi "orientation-training-api/internal/domains/xapi"
 
 	gc "orientation-training-api/internal/platform/cloud"
+	"orientation-training-api/internal/platform/db"
+	"orientation-training-api/internal/platform/scheduler"
 	"orientation-training-api/internal/platform/utils"
 
 	"github.com/labstack/echo/v4"
@@ -33,8 +48,19 @@ type AppRouter struct {
 	upCtr           *up.UserProgressController
 	templatePathCtr *tp.TemplatePathController
 	quizCtr         *quiz.QuizController
+	questionBankCtr *qb.QuestionBankController
 	sKeyCtr         *skey.SkillKeywordController
 	appFeedbackCtr  *af.AppFeedbackController
+	scormCtr        *sc.ScormController
+	xapiCtr         *xapi.XapiController
+	notificationCtr *noti.NotificationController
+	recertCtr       *recert.RecertificationController
+	pathRuleCtr     *par.PathAssignmentRuleController
+
+	overdueJob *up.OverdueJob
+	recertJob  *recert.RecertificationJob
+	releaseJob *progression.ReleaseJob
+	attemptJob *quiz.AttemptExpiryJob
 
 	userMw *u.UserMiddleware
 	gcs    *gc.GcsStorage
@@ -49,23 +75,45 @@ func NewAppRouter(logger echo.Logger) (r *AppRouter) {
 	upRepo := up.NewPgUserProgressRepository(logger)
 	templatePathRepo := tp.NewPgTemplatePathRepository(logger)
 	quizRepo := quiz.NewPgQuizRepository(logger)
+	questionBankRepo := qb.NewPgQuestionBankRepository(logger)
 	skillKeywordRepo := skey.NewPgSkillKeywordRepository(logger)
 	cskwRepo := cskw.NewPgCourseSkillKeywordRepository(logger)
 	appFeedbackRepo := af.NewPgAppFeedbackRepository(logger)
+	scormRepo := sc.NewPgScormRepository(logger)
+	xapiRepo := xapi.NewPgXapiRepository(logger)
+	prerequisiteRepo := cpr.NewPgCoursePrerequisiteRepository(logger)
+	notificationRepo := noti.NewPgNotificationRepository(logger)
+	recertRepo := recert.NewPgRecertificationRepository(logger)
+	pathRuleRepo := par.NewPgPathAssignmentRuleRepository(logger)
+	completionRepo := mic.NewPgModuleItemCompletionRepository(logger)
+
+	progressionService := progression.NewProgressionService(logger, upRepo, moduleRepo, moduleItemRepo, quizRepo, scormRepo, completionRepo, courseRepo, userRepo)
+	enrollmentService := enrollment.NewEnrollmentService(logger, upRepo, prerequisiteRepo, userRepo, templatePathRepo, pathRuleRepo)
 
 	gcsStorage := gc.NewGcsStorage(logger)
 	r = &AppRouter{
 		authCtr:         auth.NewAuthController(logger, userRepo),
-		userCtr:         u.NewUserController(logger, userRepo, upRepo, courseRepo, moduleRepo, moduleItemRepo, quizRepo, cskwRepo, gcsStorage),
-		courseCtr:       c.NewCourseController(logger, courseRepo, ucRepo, upRepo, moduleRepo, moduleItemRepo, userRepo, cskwRepo, gcsStorage),
+		userCtr:         u.NewUserController(logger, userRepo, upRepo, courseRepo, moduleRepo, moduleItemRepo, quizRepo, cskwRepo, enrollmentService, progressionService, gcsStorage),
+		courseCtr:       c.NewCourseController(logger, courseRepo, ucRepo, upRepo, moduleRepo, moduleItemRepo, userRepo, cskwRepo, prerequisiteRepo, gcsStorage),
 		moduleCtr:       md.NewModuleController(logger, moduleRepo, moduleItemRepo, courseRepo),
-		moduleItemCtr:   mdi.NewModuleItemController(logger, moduleItemRepo, quizRepo, gcsStorage),
-		lectureCtr:      lec.NewLectureController(logger, moduleRepo, moduleItemRepo, courseRepo, upRepo, quizRepo, gcsStorage),
-		upCtr:           up.NewUserProgressController(logger, upRepo, moduleRepo, moduleItemRepo, userRepo),
+		moduleItemCtr:   mdi.NewModuleItemController(logger, moduleItemRepo, quizRepo, scormRepo, gcsStorage),
+		lectureCtr:      lec.NewLectureController(logger, moduleRepo, moduleItemRepo, courseRepo, upRepo, quizRepo, scormRepo, xapiRepo, prerequisiteRepo, progressionService, gcsStorage),
+		upCtr:           up.NewUserProgressController(logger, upRepo, moduleRepo, moduleItemRepo, userRepo, xapiRepo, prerequisiteRepo, templatePathRepo, enrollmentService, progressionService),
 		templatePathCtr: tp.NewTemplatePathController(logger, templatePathRepo, courseRepo),
-		quizCtr:         quiz.NewQuizController(logger, quizRepo),
+		quizCtr:         quiz.NewQuizController(logger, quizRepo, xapiRepo, questionBankRepo, progressionService),
+		questionBankCtr: qb.NewQuestionBankController(logger, questionBankRepo, quizRepo, skillKeywordRepo),
 		sKeyCtr:         skey.NewSkillKeywordController(logger, skillKeywordRepo),
 		appFeedbackCtr:  af.NewAppFeedbackController(logger, appFeedbackRepo),
+		scormCtr:        sc.NewScormController(logger, scormRepo, progressionService, xapiRepo, gcsStorage),
+		xapiCtr:         xapi.NewXapiController(logger, xapiRepo),
+		notificationCtr: noti.NewNotificationController(logger, notificationRepo),
+		recertCtr:       recert.NewRecertificationController(logger, recertRepo, courseRepo, upRepo),
+		pathRuleCtr:     par.NewPathAssignmentRuleController(logger, pathRuleRepo, templatePathRepo, userRepo),
+
+		overdueJob: up.NewOverdueJob(logger, upRepo, notificationRepo, userRepo),
+		recertJob:  recert.NewRecertificationJob(logger, recertRepo, progressionService, notificationRepo),
+		releaseJob: progression.NewReleaseJob(logger, moduleRepo, courseRepo, upRepo, userRepo, notificationRepo),
+		attemptJob: quiz.NewAttemptExpiryJob(logger, quizRepo, xapiRepo),
 
 		userMw: u.NewUserMiddleware(logger, userRepo),
 	}
@@ -125,6 +173,8 @@ func (r *AppRouter) ModuleRoute(g *echo.Group) {
 	g.POST("/get-module-list", r.moduleCtr.GetModuleList, isLoggedIn, r.userMw.InitUserProfile)
 	g.POST("/get-module-details", r.moduleCtr.GetModuleDetails, isLoggedIn, r.userMw.InitUserProfile)
 	g.POST("/add-module", r.moduleCtr.AddModule, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/update-module-navigation", r.moduleCtr.UpdateModuleNavigation, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/update-module-release", r.moduleCtr.UpdateModuleRelease, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 	g.POST("/delete-module", r.moduleCtr.DeleteModule, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 }
 
@@ -160,11 +210,17 @@ func (r *AppRouter) UserProgressRoute(g *echo.Group) {
 	g.POST("/get-user-progress", r.upCtr.GetAllUserProgressByUserID, isLoggedIn, r.userMw.InitUserProfile)
 
 	g.POST("/update-user-progress", r.upCtr.UpdateUserProgress, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/view-item", r.upCtr.ViewItem, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/heartbeat", r.upCtr.RecordHeartbeat, isLoggedIn, r.userMw.InitUserProfile)
 	g.POST("/add-user-progress", r.upCtr.AddUserProgress, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 	g.POST("/list-trainee-by-course", r.upCtr.GetListTraineeByCourseID, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 	g.POST("/add-list-trainee-to-course", r.upCtr.AddListTraineeToCourse, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 
 	g.POST("/review-progress", r.upCtr.ReviewProgress, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/reset-progress", r.upCtr.ResetProgress, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/reopen-progress", r.upCtr.ReopenProgress, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/unassign-course", r.upCtr.UnassignCourse, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/transfer-progress", r.upCtr.TransferProgress, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 }
 
 func (r *AppRouter) TemplatePathRoute(g *echo.Group) {
@@ -178,6 +234,9 @@ func (r *AppRouter) TemplatePathRoute(g *echo.Group) {
 	g.POST("/create-template-path", r.templatePathCtr.CreateTemplatePath, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 	g.POST("/update-template-path", r.templatePathCtr.UpdateTemplatePath, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 	g.POST("/delete-template-path", r.templatePathCtr.DeleteTemplatePath, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/assign-template-path", r.upCtr.AssignTemplatePath, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/get-path-progress", r.upCtr.GetTemplatePathProgress, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/choose-elective", r.upCtr.ChooseElective, isLoggedIn, r.userMw.InitUserProfile)
 }
 
 func (r *AppRouter) QuizRoute(g *echo.Group) {
@@ -193,7 +252,17 @@ func (r *AppRouter) QuizRoute(g *echo.Group) {
 	g.POST("/details", r.quizCtr.GetQuizDetail, isLoggedIn, r.userMw.InitUserProfile)
 
 	g.POST("/question/create", r.quizCtr.CreateQuizQuestion, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
-
+	g.POST("/question/update", r.quizCtr.UpdateQuizQuestion, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/question/delete", r.quizCtr.DeleteQuizQuestion, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/question/reorder", r.quizCtr.ReorderQuizQuestions, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/answer/update", r.quizCtr.UpdateQuizAnswer, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/answer/delete", r.quizCtr.DeleteQuizAnswer, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/answer/reorder", r.quizCtr.ReorderQuizAnswers, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/draw-rules/save", r.quizCtr.SaveQuizDrawRules, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/import", r.quizCtr.ImportQuiz, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/export", r.quizCtr.ExportQuiz, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+
+	g.POST("/start", r.quizCtr.StartQuiz, isLoggedIn, r.userMw.InitUserProfile)
 	g.POST("/submit-full", r.quizCtr.SubmitFullQuiz, isLoggedIn, r.userMw.InitUserProfile)
 	g.POST("/result", r.quizCtr.GetQuizResults, isLoggedIn, r.userMw.InitUserProfile)
 
@@ -201,6 +270,20 @@ func (r *AppRouter) QuizRoute(g *echo.Group) {
 	g.POST("/review-essay", r.quizCtr.ReviewEssaySubmission, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
 }
 
+func (r *AppRouter) QuestionBankRoute(g *echo.Group) {
+	keyTokenAuth := utils.GetKeyToken()
+	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: []byte(keyTokenAuth),
+	})
+
+	g.POST("/list", r.questionBankCtr.GetQuestionBankList, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/details", r.questionBankCtr.GetQuestionBankDetail, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/save", r.questionBankCtr.SaveQuestionBank, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/delete", r.questionBankCtr.DeleteQuestionBank, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/question/save", r.questionBankCtr.SaveBankQuestion, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/question/delete", r.questionBankCtr.DeleteBankQuestion, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+}
+
 func (r *AppRouter) SkillKeywordRoute(g *echo.Group) {
 	keyTokenAuth := utils.GetKeyToken()
 	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
@@ -225,3 +308,93 @@ func (r *AppRouter) AppFeedbackRoute(g *echo.Group) {
 
 	g.GET("/list-top", r.appFeedbackCtr.GetTopAppFeedback)
 }
+
+func (r *AppRouter) ScormRoute(g *echo.Group) {
+	keyTokenAuth := utils.GetKeyToken()
+	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: []byte(keyTokenAuth),
+	})
+
+	g.POST("/get-runtime", r.scormCtr.GetScormRuntime, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/commit-runtime", r.scormCtr.CommitScormRuntime, isLoggedIn, r.userMw.InitUserProfile)
+	g.GET("/content/:module_item_id/:token/*", r.scormCtr.GetScormContent)
+}
+
+func (r *AppRouter) XapiRoute(g *echo.Group) {
+	keyTokenAuth := utils.GetKeyToken()
+	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: []byte(keyTokenAuth),
+	})
+
+	g.GET("/statements", r.xapiCtr.GetStatements, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/statements", r.xapiCtr.SaveStatements, isLoggedIn, r.userMw.InitUserProfile)
+}
+
+func (r *AppRouter) NotificationRoute(g *echo.Group) {
+	keyTokenAuth := utils.GetKeyToken()
+	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: []byte(keyTokenAuth),
+	})
+
+	g.POST("/get-notifications", r.notificationCtr.GetNotifications, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/mark-read", r.notificationCtr.MarkNotificationsRead, isLoggedIn, r.userMw.InitUserProfile)
+}
+
+func (r *AppRouter) RecertificationRoute(g *echo.Group) {
+	keyTokenAuth := utils.GetKeyToken()
+	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: []byte(keyTokenAuth),
+	})
+
+	g.POST("/get-policy", r.recertCtr.GetPolicy, isLoggedIn, r.userMw.InitUserProfile)
+	g.POST("/save-policy", r.recertCtr.SavePolicy, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/delete-policy", r.recertCtr.DeletePolicy, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/get-progress-history", r.recertCtr.GetProgressHistory, isLoggedIn, r.userMw.InitUserProfile)
+}
+
+func (r *AppRouter) PathAssignmentRuleRoute(g *echo.Group) {
+	keyTokenAuth := utils.GetKeyToken()
+	isLoggedIn := middleware.JWTWithConfig(middleware.JWTConfig{
+		SigningKey: []byte(keyTokenAuth),
+	})
+
+	g.POST("/get-rules", r.pathRuleCtr.GetRules, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/save-rule", r.pathRuleCtr.SaveRule, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/delete-rule", r.pathRuleCtr.DeleteRule, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+	g.POST("/preview-rule", r.pathRuleCtr.PreviewRule, isLoggedIn, r.userMw.InitUserProfile, r.userMw.CheckManager)
+}
+
+// NewScheduler registers the background jobs, OVERDUE_CHECK_INTERVAL, RECERTIFICATION_CHECK_INTERVAL,
+// MODULE_RELEASE_CHECK_INTERVAL and QUIZ_ATTEMPT_CHECK_INTERVAL override the default hourly checks
+func (r *AppRouter) NewScheduler(logger echo.Logger) *scheduler.Scheduler {
+	s := scheduler.NewScheduler(logger, db.Init(logger))
+	s.AddJob(scheduler.Job{
+		Name:     "recertification-cycles",
+		Interval: jobInterval("RECERTIFICATION_CHECK_INTERVAL"),
+		Run:      r.recertJob.Run,
+	})
+	s.AddJob(scheduler.Job{
+		Name:     "overdue-enrollments",
+		Interval: jobInterval("OVERDUE_CHECK_INTERVAL"),
+		Run:      r.overdueJob.Run,
+	})
+	s.AddJob(scheduler.Job{
+		Name:     "module-releases",
+		Interval: jobInterval("MODULE_RELEASE_CHECK_INTERVAL"),
+		Run:      r.releaseJob.Run,
+	})
+	s.AddJob(scheduler.Job{
+		Name:     "quiz-attempt-expiry",
+		Interval: jobInterval("QUIZ_ATTEMPT_CHECK_INTERVAL"),
+		Run:      r.attemptJob.Run,
+	})
+
+	return s
+}
+
+func jobInterval(envName string) time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv(envName)); err == nil && interval > 0 {
+		return interval
+	}
+	return time.Hour
+}
@@ -11,6 +11,7 @@ const (
 	ThumbnailFolderGCS          = "images/thumbnail/"
 	FileFolderGCS               = "files/"
 	VideoFolderGCS              = "videos/"
+	ScormFolderGCS              = "scorm/"
 
 	// Course category
 	Onboarding = 1
@@ -27,6 +28,11 @@ const (
 	// Quiz question types
 	QuestionTypeMultipleChoice = 1
 	QuestionTypeEssay          = 2
+	QuestionTypeTrueFalse      = 3
+	QuestionTypeShortAnswer    = 4
+	QuestionTypeMatching       = 5
+	QuestionTypeOrdering       = 6
+	QuestionTypeNumeric        = 7
 )
 
 var CourseCategoryList = map[int]string{
@@ -46,6 +52,11 @@ var DifficultyLabels = map[int]string{
 var QuestionTypeLabels = map[int]string{
 	QuestionTypeMultipleChoice: "Multiple Choice",
 	QuestionTypeEssay:          "Essay",
+	QuestionTypeTrueFalse:      "True/False",
+	QuestionTypeShortAnswer:    "Short Answer",
+	QuestionTypeMatching:       "Matching",
+	QuestionTypeOrdering:       "Ordering",
+	QuestionTypeNumeric:        "Numeric",
 }
 
 // AllowFormatImageList format image allow
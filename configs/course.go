@@ -0,0 +1,25 @@
+package configs
+
+// Navigation modes of a course
+// sequential unlocks the items of the whole course in order, sequential_by_module opens every module
+// with its items in order and free opens every item. A module can override the order of its own items.
+const (
+	NavigationSequential         = "sequential"
+	NavigationFree               = "free"
+	NavigationSequentialByModule = "sequential_by_module"
+)
+
+var CourseNavigationModes = []string{NavigationSequential, NavigationFree, NavigationSequentialByModule}
+
+var ModuleNavigationModes = []string{NavigationSequential, NavigationFree}
+
+// Release rules of a module
+// A module opens on its release date, or release_offset_days after the enrollment or the company joined date
+// of the trainee: a module opening on day 3 of employment has an offset of 2 days after the joined date.
+const (
+	ReleaseTypeAbsoluteDate    = "absolute_date"
+	ReleaseTypeAfterEnrollment = "after_enrollment"
+	ReleaseTypeAfterJoinedDate = "after_joined_date"
+)
+
+var ModuleReleaseTypes = []string{ReleaseTypeAbsoluteDate, ReleaseTypeAfterEnrollment, ReleaseTypeAfterJoinedDate}
@@ -0,0 +1,15 @@
+package configs
+
+// Notification types
+const (
+	NotificationTypeCourseOverdue        = "course_overdue"
+	NotificationTypeTraineeCourseOverdue = "trainee_course_overdue"
+	NotificationTypeRecertificationDue   = "recertification_due"
+	NotificationTypeModuleReleased       = "module_released"
+)
+
+// Due date anchors of an enrollment
+const (
+	DueRelativeToAssignment = "assignment"
+	DueRelativeToJoinedDate = "company_joined_date"
+)
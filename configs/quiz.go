@@ -4,3 +4,66 @@ const (
 	QuesMultipleChoice = 1
 	QuesEssay          = 2
 )
+
+// Quiz types of the lecture payload, a mixed quiz combines essays with auto-graded questions
+const (
+	QuizTypeMultipleChoice = "multiple_choice"
+	QuizTypeEssay          = "essay"
+	QuizTypeMixed          = "mixed"
+)
+
+// Formats of the quiz import and export files
+const (
+	QuizFormatGIFT = "gift"
+	QuizFormatQTI  = "qti"
+	QuizFormatCSV  = "csv"
+)
+
+var QuizFormats = []string{QuizFormatGIFT, QuizFormatQTI, QuizFormatCSV}
+
+// QuizPackageMaxSize is the maximum uncompressed size in bytes of an imported QTI package
+const QuizPackageMaxSize = 20 << 20
+
+// Pass mark types of a quiz, the pass mark is a percentage of the total score or a score
+const (
+	PassMarkPercentage = "percentage"
+	PassMarkScore      = "score"
+)
+
+var PassMarkTypes = []string{PassMarkPercentage, PassMarkScore}
+
+// DefaultPassMark is the pass mark in percent of a quiz created without one
+const DefaultPassMark = 70
+
+// Quiz attempt statuses, an attempt is open until it is submitted or its time limit is over
+const (
+	AttemptStatusInProgress = "in_progress"
+	AttemptStatusSubmitted  = "submitted"
+	AttemptStatusExpired    = "expired"
+)
+
+// QuizSubmitGracePeriod is the number of seconds a submission is still accepted after the deadline
+// of an attempt, it absorbs the network latency of a submit sent right before the deadline
+const QuizSubmitGracePeriod = 30
+
+// Score policies of a quiz, the attempt score that counts for the quiz
+const (
+	ScorePolicyHighest = "highest"
+	ScorePolicyLatest  = "latest"
+	ScorePolicyAverage = "average"
+)
+
+var ScorePolicies = []string{ScorePolicyHighest, ScorePolicyLatest, ScorePolicyAverage}
+
+// Scoring strategies of a multiple choice question: all or nothing, the share of the correct options selected,
+// or the correct options selected minus the wrong ones with a floor at zero
+const (
+	ScoringAllOrNothing    = "all_or_nothing"
+	ScoringProportional    = "proportional"
+	ScoringRightMinusWrong = "right_minus_wrong"
+)
+
+var ScoringStrategies = []string{ScoringAllOrNothing, ScoringProportional, ScoringRightMinusWrong}
+
+// OptionKeyMax is the largest option key of the matches of a matching question and the items of an ordering question
+const OptionKeyMax = 1000000000
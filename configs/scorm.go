@@ -0,0 +1,25 @@
+package configs
+
+// SCORM package versions
+const (
+	ScormVersion12   = "1.2"
+	ScormVersion2004 = "2004"
+)
+
+// ScormMaxPackageSize is the maximum total uncompressed size of a SCORM package (200MB)
+const ScormMaxPackageSize = 200 << 20
+
+// SCORM 1.2 lesson_status values
+const (
+	ScormStatusPassed        = "passed"
+	ScormStatusCompleted     = "completed"
+	ScormStatusFailed        = "failed"
+	ScormStatusIncomplete    = "incomplete"
+	ScormStatusBrowsed       = "browsed"
+	ScormStatusNotAttempted  = "not attempted"
+	ScormStatusUnknown       = "unknown"
+	ScormSuccessStatusPassed = "passed"
+)
+
+// ScormContentTokenTTL is how long in hours the launch URL of a SCORM package gives access to its files
+const ScormContentTokenTTL = 12
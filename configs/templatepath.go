@@ -0,0 +1,7 @@
+package configs
+
+// Stage types of a template path
+const (
+	StageTypeRequired = "required"
+	StageTypeElective = "elective"
+)
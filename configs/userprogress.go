@@ -5,3 +5,29 @@ const (
 	InProgress  = 1
 	Completed   = 2
 )
+
+// Status of a module item for a user
+const (
+	ItemStatusInProgress = "in_progress"
+	ItemStatusCompleted  = "completed"
+)
+
+// Lecture heartbeats sent by the player, in seconds
+// A gap longer than HeartbeatMaxInterval is not counted as time spent, the player was closed or paused.
+// Playback moving faster than HeartbeatMaxPlaybackRate times the wall clock is flagged as a speed-up.
+// A file or slide lecture left on the same page or slide longer than HeartbeatMaxIdleTime is no longer counted.
+const (
+	HeartbeatMaxInterval       = 60
+	HeartbeatMaxPlaybackRate   = 2.0
+	HeartbeatPositionTolerance = 2.0
+	HeartbeatMaxIdleTime       = 300
+)
+
+// Reasons a cycle of an enrollment was archived in its history
+const (
+	HistoryReasonRecertification = "recertification"
+	HistoryReasonReset           = "reset"
+	HistoryReasonReopen          = "reopen"
+	HistoryReasonUnassign        = "unassign"
+	HistoryReasonTransfer        = "transfer"
+)
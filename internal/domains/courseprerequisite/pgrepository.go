@@ -0,0 +1,111 @@
+package courseprerequisite
+
+import (
+	cm "orientation-training-api/internal/common"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type PgCoursePrerequisiteRepository struct {
+	cm.AppRepository
+}
+
+func NewPgCoursePrerequisiteRepository(logger echo.Logger) (repo *PgCoursePrerequisiteRepository) {
+	repo = &PgCoursePrerequisiteRepository{}
+	repo.Init(logger)
+	return
+}
+
+// GetPrerequisitesByCourseID : get the courses that must be completed before a course
+func (repo *PgCoursePrerequisiteRepository) GetPrerequisitesByCourseID(courseID int) ([]m.Course, error) {
+	var courses []m.Course
+
+	_, err := repo.DB.Query(&courses, `
+		SELECT c.*
+		FROM courses AS c
+		JOIN course_prerequisites AS cp ON c.id = cp.prerequisite_course_id
+		WHERE cp.course_id = ?
+		AND c.deleted_at IS NULL
+		AND cp.deleted_at IS NULL
+		ORDER BY c.id ASC
+	`, courseID)
+
+	if err != nil {
+		repo.Logger.Errorf("Failed to get prerequisites for course ID %d: %v", courseID, err)
+	}
+
+	return courses, err
+}
+
+// GetAllCoursePrerequisites : get every prerequisite edge between active courses
+func (repo *PgCoursePrerequisiteRepository) GetAllCoursePrerequisites() ([]m.CoursePrerequisite, error) {
+	var coursePrerequisites []m.CoursePrerequisite
+
+	_, err := repo.DB.Query(&coursePrerequisites, `
+		SELECT cp.*
+		FROM course_prerequisites AS cp
+		JOIN courses AS c ON c.id = cp.course_id AND c.deleted_at IS NULL
+		JOIN courses AS p ON p.id = cp.prerequisite_course_id AND p.deleted_at IS NULL
+		WHERE cp.deleted_at IS NULL
+	`)
+
+	if err != nil {
+		repo.Logger.Errorf("Failed to get course prerequisites: %v", err)
+	}
+
+	return coursePrerequisites, err
+}
+
+// ReplaceCoursePrerequisites : replace the prerequisites of a course
+func (repo *PgCoursePrerequisiteRepository) ReplaceCoursePrerequisites(courseID int, prerequisiteIDs []int) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if _, err := tx.Exec("DELETE FROM course_prerequisites WHERE course_id = ?", courseID); err != nil {
+			repo.Logger.Errorf("Error deleting prerequisites for course %d: %v", courseID, err)
+			return err
+		}
+
+		for _, prerequisiteID := range prerequisiteIDs {
+			coursePrerequisite := m.CoursePrerequisite{
+				CourseID:             courseID,
+				PrerequisiteCourseID: prerequisiteID,
+			}
+
+			if err := tx.Insert(&coursePrerequisite); err != nil {
+				repo.Logger.Errorf("Error inserting prerequisite %d for course %d: %v", prerequisiteID, courseID, err)
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetMissingPrerequisites : get the prerequisites of a course the user has not completed yet
+func (repo *PgCoursePrerequisiteRepository) GetMissingPrerequisites(userID int, courseID int) ([]m.Course, error) {
+	var courses []m.Course
+
+	_, err := repo.DB.Query(&courses, `
+		SELECT c.*
+		FROM courses AS c
+		JOIN course_prerequisites AS cp ON c.id = cp.prerequisite_course_id
+		WHERE cp.course_id = ?
+		AND c.deleted_at IS NULL
+		AND cp.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM user_progresses AS up
+			WHERE up.user_id = ?
+			AND up.course_id = c.id
+			AND up.completed = TRUE
+			AND up.deleted_at IS NULL
+		)
+		ORDER BY c.id ASC
+	`, courseID, userID)
+
+	if err != nil {
+		repo.Logger.Errorf("Failed to get missing prerequisites of course %d for user %d: %v", courseID, userID, err)
+	}
+
+	return courses, err
+}
@@ -30,6 +30,7 @@ type CourseController struct {
 	ModuleItemRepo         rp.ModuleItemRepository
 	UserRepo               rp.UserRepository
 	CourseSkillKeywordRepo rp.CourseSkillKeywordRepository
+	CoursePrerequisiteRepo rp.CoursePrerequisiteRepository
 	cloud                  gc.StorageUtility
 }
 
@@ -42,6 +43,7 @@ func NewCourseController(
 	moduleItemRepo rp.ModuleItemRepository,
 	userRepo rp.UserRepository,
 	courseSkillKeywordRepo rp.CourseSkillKeywordRepository,
+	coursePrerequisiteRepo rp.CoursePrerequisiteRepository,
 	cloud gc.StorageUtility,
 ) (ctr *CourseController) {
 	ctr = &CourseController{
@@ -53,6 +55,7 @@ func NewCourseController(
 		moduleItemRepo,
 		userRepo,
 		courseSkillKeywordRepo,
+		coursePrerequisiteRepo,
 		cloud,
 	}
 	ctr.Init(logger)
@@ -203,6 +206,29 @@ func (ctr *CourseController) AddCourse(c echo.Context) error {
 		})
 	}
 
+	if createCourseParams.NavigationMode != "" {
+		if _, ok := utils.FindStringInArray(cf.CourseNavigationModes, createCourseParams.NavigationMode); !ok {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "navigation_mode must be sequential, free or sequential_by_module",
+			})
+		}
+	}
+
+	if message, err := ctr.validatePrerequisites(0, createCourseParams.PrerequisiteIDs); err != nil || message != "" {
+		if err != nil {
+			ctr.Logger.Errorf("Failed to validate prerequisites: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
 	if createCourseParams.Thumbnail != "" {
 		parts := strings.SplitN(createCourseParams.Thumbnail, ",", 2)
 		if len(parts) != 2 {
@@ -258,6 +284,17 @@ func (ctr *CourseController) AddCourse(c echo.Context) error {
 		})
 	}
 
+	if len(createCourseParams.PrerequisiteIDs) > 0 {
+		if err := ctr.CoursePrerequisiteRepo.ReplaceCoursePrerequisites(course.ID, createCourseParams.PrerequisiteIDs); err != nil {
+			ctr.Logger.Errorf("Failed to save prerequisites for course %d: %v", course.ID, err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Course created but prerequisites could not be saved",
+				Data:    course,
+			})
+		}
+	}
+
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
 		Message: "Course Created Successfully",
@@ -421,23 +458,42 @@ func (ctr *CourseController) GetCourseDetail(c echo.Context) error {
 		}
 
 		moduleData := map[string]interface{}{
-			"id":           module.ID,
-			"title":        module.Title,
-			"position":     module.Position,
-			"duration":     module.Duration,
-			"module_items": itemsList,
+			"id":                  module.ID,
+			"title":               module.Title,
+			"position":            module.Position,
+			"duration":            module.Duration,
+			"module_items":        itemsList,
+			"navigation_mode":     module.NavigationMode,
+			"release_type":        module.ReleaseType,
+			"release_date":        utils.FormatDueDate(module.ReleaseDate),
+			"release_offset_days": module.ReleaseOffsetDays,
 		}
 
 		moduleList = append(moduleList, moduleData)
 	}
 
+	prerequisites, err := ctr.CoursePrerequisiteRepo.GetPrerequisitesByCourseID(course.ID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch prerequisites: %v", err)
+	}
+
+	prerequisiteList := []resp.PrerequisiteCourseResponse{}
+	for _, prerequisite := range prerequisites {
+		prerequisiteList = append(prerequisiteList, resp.PrerequisiteCourseResponse{
+			CourseID: prerequisite.ID,
+			Title:    prerequisite.Title,
+		})
+	}
+
 	courseDetail := map[string]interface{}{
-		"course_id":   course.ID,
-		"title":       course.Title,
-		"description": course.Description,
-		"category":    course.Category,
-		"duration":    course.Duration,
-		"modules":     moduleList,
+		"course_id":       course.ID,
+		"title":           course.Title,
+		"description":     course.Description,
+		"category":        course.Category,
+		"duration":        course.Duration,
+		"modules":         moduleList,
+		"prerequisites":   prerequisiteList,
+		"navigation_mode": course.NavigationMode,
 	}
 
 	return c.JSON(http.StatusOK, cf.JsonResponse{
@@ -481,6 +537,32 @@ func (ctr *CourseController) UpdateCourse(c echo.Context) error {
 		})
 	}
 
+	if updateCourseParams.NavigationMode != "" {
+		if _, ok := utils.FindStringInArray(cf.CourseNavigationModes, updateCourseParams.NavigationMode); !ok {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "navigation_mode must be sequential, free or sequential_by_module",
+			})
+		}
+	}
+
+	if updateCourseParams.PrerequisiteIDs != nil {
+		message, err := ctr.validatePrerequisites(updateCourseParams.ID, updateCourseParams.PrerequisiteIDs)
+		if err != nil {
+			ctr.Logger.Errorf("Failed to validate prerequisites: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+		if message != "" {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: message,
+			})
+		}
+	}
+
 	if updateCourseParams.Thumbnail != "" && !strings.HasPrefix(updateCourseParams.Thumbnail, "course_thumbnails/") {
 		parts := strings.SplitN(updateCourseParams.Thumbnail, ",", 2)
 		if len(parts) != 2 {
@@ -540,6 +622,16 @@ func (ctr *CourseController) UpdateCourse(c echo.Context) error {
 			Message: "Unable to update course",
 		})
 	}
+
+	if updateCourseParams.PrerequisiteIDs != nil {
+		if err := ctr.CoursePrerequisiteRepo.ReplaceCoursePrerequisites(updateCourseParams.ID, updateCourseParams.PrerequisiteIDs); err != nil {
+			ctr.Logger.Errorf("Error updating prerequisites: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Unable to update course prerequisites",
+			})
+		}
+	}
 	updatedCourse, err := ctr.CourseRepo.GetCourseByID(updateCourseParams.ID)
 	if err != nil {
 		ctr.Logger.Warnf("Unable to get course information after update: %v", err)
@@ -581,3 +673,53 @@ func (ctr *CourseController) UpdateCourse(c echo.Context) error {
 		Data:    courseResponse,
 	})
 }
+
+// validatePrerequisites checks that prerequisites exist and do not create a cycle in the prerequisite graph
+// Params: courseID (0 for a new course), prerequisiteIDs
+// Returns: validation message (empty when valid), error
+func (ctr *CourseController) validatePrerequisites(courseID int, prerequisiteIDs []int) (string, error) {
+	if len(prerequisiteIDs) == 0 {
+		return "", nil
+	}
+
+	seen := make(map[int]bool)
+	for _, prerequisiteID := range prerequisiteIDs {
+		if prerequisiteID == courseID {
+			return "A course cannot be its own prerequisite", nil
+		}
+		if seen[prerequisiteID] {
+			return fmt.Sprintf("Prerequisite course %d is duplicated", prerequisiteID), nil
+		}
+		seen[prerequisiteID] = true
+
+		if _, err := ctr.CourseRepo.GetCourseByID(prerequisiteID); err != nil {
+			if err.Error() == pg.ErrNoRows.Error() {
+				return fmt.Sprintf("Prerequisite course %d not found", prerequisiteID), nil
+			}
+			return "", err
+		}
+	}
+
+	if courseID == 0 {
+		return "", nil
+	}
+
+	coursePrerequisites, err := ctr.CoursePrerequisiteRepo.GetAllCoursePrerequisites()
+	if err != nil {
+		return "", err
+	}
+
+	graph := make(map[int][]int)
+	for _, coursePrerequisite := range coursePrerequisites {
+		if coursePrerequisite.CourseID == courseID {
+			continue
+		}
+		graph[coursePrerequisite.CourseID] = append(graph[coursePrerequisite.CourseID], coursePrerequisite.PrerequisiteCourseID)
+	}
+
+	if utils.CreatesCycle(graph, courseID, prerequisiteIDs) {
+		return "Prerequisites would create a cycle between courses", nil
+	}
+
+	return "", nil
+}
@@ -73,6 +73,7 @@ func (repo *PgCourseRepository) SaveCourse(createCourseParams *param.CreateCours
 			createCourseParams.Thumbnail,
 			createCourseParams.Category,
 			createCourseParams.CreatedBy,
+			createCourseParams.NavigationMode,
 		)
 		if transErr != nil {
 			repo.Logger.Error(transErr)
@@ -96,15 +97,16 @@ func (repo *PgCourseRepository) SaveCourse(createCourseParams *param.CreateCours
 }
 
 // InsertCourseWithTx : insert data to courses
-// Params : pg.Tx, title, description, thumbnail
+// Params : pg.Tx, title, description, thumbnail, category, createdBy, navigationMode
 // Returns : return course object , error
-func (repo *PgCourseRepository) InsertCourseWithTx(tx *pg.Tx, title string, description string, thumbnail string, category string, createdBy int) (m.Course, error) {
+func (repo *PgCourseRepository) InsertCourseWithTx(tx *pg.Tx, title string, description string, thumbnail string, category string, createdBy int, navigationMode string) (m.Course, error) {
 	course := m.Course{
-		Title:       title,
-		Description: description,
-		Thumbnail:   thumbnail,
-		Category:    category,
-		CreatedBy:   createdBy,
+		Title:          title,
+		Description:    description,
+		Thumbnail:      thumbnail,
+		Category:       category,
+		CreatedBy:      createdBy,
+		NavigationMode: navigationMode,
 	}
 	err := tx.Insert(&course)
 	return course, err
@@ -133,6 +135,9 @@ func (repo *PgCourseRepository) UpdateCourse(courseParams *param.UpdateCoursePar
 		if courseParams.Category != "" {
 			updateQuery = updateQuery.Set("category = ?", courseParams.Category)
 		}
+		if courseParams.NavigationMode != "" {
+			updateQuery = updateQuery.Set("navigation_mode = ?", courseParams.NavigationMode)
+		}
 
 		if _, transErr = updateQuery.Update(); transErr != nil {
 			repo.Logger.Error()
@@ -0,0 +1,143 @@
+package enrollment
+
+import (
+	cf "orientation-training-api/configs"
+	"orientation-training-api/internal/interfaces/response"
+	m "orientation-training-api/internal/models"
+	"sort"
+)
+
+// PathStages returns the stages of a path in order with their courses in path order,
+// a path loaded without stages is a single required stage
+func PathStages(templatePath m.TemplatePath) []m.TemplatePathStage {
+	pathCourses := append([]m.TemplatePathCourse{}, templatePath.PathCourses...)
+	sort.SliceStable(pathCourses, func(i, j int) bool {
+		return pathCourses[i].Position < pathCourses[j].Position
+	})
+
+	if len(templatePath.Stages) == 0 {
+		stage := m.TemplatePathStage{
+			Name:      "Stage 1",
+			Position:  1,
+			StageType: cf.StageTypeRequired,
+			CourseIds: []int{},
+		}
+		for _, pathCourse := range pathCourses {
+			stage.CourseIds = append(stage.CourseIds, pathCourse.CourseID)
+		}
+
+		return []m.TemplatePathStage{stage}
+	}
+
+	stages := append([]m.TemplatePathStage{}, templatePath.Stages...)
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].Position < stages[j].Position
+	})
+
+	stageIndexes := make(map[int]int)
+	for i := range stages {
+		stages[i].CourseIds = []int{}
+		stageIndexes[stages[i].ID] = i
+	}
+
+	for _, pathCourse := range pathCourses {
+		if i, ok := stageIndexes[pathCourse.StageID]; ok {
+			stages[i].CourseIds = append(stages[i].CourseIds, pathCourse.CourseID)
+		}
+	}
+
+	return stages
+}
+
+// PathRequiredCourseIDs returns the courses of the required stages in path order
+func PathRequiredCourseIDs(templatePath m.TemplatePath) []int {
+	courseIDs := []int{}
+	for _, stage := range PathStages(templatePath) {
+		if stage.StageType == cf.StageTypeRequired {
+			courseIDs = append(courseIDs, stage.CourseIds...)
+		}
+	}
+
+	return courseIDs
+}
+
+// PathCoursePositions maps the courses of a path to their position in the path
+func PathCoursePositions(templatePath m.TemplatePath) map[int]int {
+	positions := make(map[int]int)
+	for _, pathCourse := range templatePath.PathCourses {
+		positions[pathCourse.CourseID] = pathCourse.Position
+	}
+
+	return positions
+}
+
+// EvaluatePathStages computes the completion of every stage of a path, a course of the path
+// counts as completed even when it was assigned separately
+func EvaluatePathStages(templatePath m.TemplatePath, userProgresses []m.UserProgress) []response.PathStageProgress {
+	enrolledCourses := make(map[int]bool)
+	completedCourses := make(map[int]bool)
+	for _, progress := range userProgresses {
+		enrolledCourses[progress.CourseID] = true
+		if progress.Completed {
+			completedCourses[progress.CourseID] = true
+		}
+	}
+
+	positions := PathCoursePositions(templatePath)
+	stagesProgress := []response.PathStageProgress{}
+
+	for _, stage := range PathStages(templatePath) {
+		stageProgress := response.PathStageProgress{
+			StageID:            stage.ID,
+			Name:               stage.Name,
+			Position:           stage.Position,
+			StageType:          stage.StageType,
+			RequiredCount:      len(stage.CourseIds),
+			RemainingElectives: []int{},
+			Courses:            []response.PathStageCourse{},
+		}
+
+		// Courses removed from the path can leave an elective stage with fewer courses than required
+		if stage.StageType == cf.StageTypeElective && stage.RequiredCount < stageProgress.RequiredCount {
+			stageProgress.RequiredCount = stage.RequiredCount
+		}
+
+		enrolledCount := 0
+		for _, courseID := range stage.CourseIds {
+			stageCourse := response.PathStageCourse{
+				CourseID:  courseID,
+				Position:  positions[courseID],
+				Enrolled:  enrolledCourses[courseID],
+				Completed: completedCourses[courseID],
+			}
+
+			if stageCourse.Enrolled {
+				enrolledCount++
+			}
+
+			if stageCourse.Completed {
+				stageProgress.CompletedCount++
+			}
+
+			stageProgress.Courses = append(stageProgress.Courses, stageCourse)
+		}
+
+		if stageProgress.CompletedCount > stageProgress.RequiredCount {
+			stageProgress.CompletedCount = stageProgress.RequiredCount
+		}
+		stageProgress.Completed = stageProgress.CompletedCount == stageProgress.RequiredCount
+
+		if stage.StageType == cf.StageTypeElective && enrolledCount < stageProgress.RequiredCount {
+			stageProgress.ElectivesToChoose = stageProgress.RequiredCount - enrolledCount
+			for _, stageCourse := range stageProgress.Courses {
+				if !stageCourse.Enrolled {
+					stageProgress.RemainingElectives = append(stageProgress.RemainingElectives, stageCourse.CourseID)
+				}
+			}
+		}
+
+		stagesProgress = append(stagesProgress, stageProgress)
+	}
+
+	return stagesProgress
+}
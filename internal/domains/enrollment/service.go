@@ -0,0 +1,377 @@
+package enrollment
+
+import (
+	"errors"
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	"orientation-training-api/internal/interfaces/response"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Service creates enrollments for the controllers and the background jobs,
+// checking prerequisites and resolving due dates the same way everywhere
+type Service struct {
+	Logger           echo.Logger
+	UserProgressRepo rp.UserProgressRepository
+	PrerequisiteRepo rp.CoursePrerequisiteRepository
+	UserRepo         rp.UserRepository
+	TemplatePathRepo rp.TemplatePathRepository
+	PathRuleRepo     rp.PathAssignmentRuleRepository
+}
+
+func NewEnrollmentService(logger echo.Logger, userProgressRepo rp.UserProgressRepository, prerequisiteRepo rp.CoursePrerequisiteRepository, userRepo rp.UserRepository, templatePathRepo rp.TemplatePathRepository, pathRuleRepo rp.PathAssignmentRuleRepository) *Service {
+	return &Service{logger, userProgressRepo, prerequisiteRepo, userRepo, templatePathRepo, pathRuleRepo}
+}
+
+// RuleMatchesUser checks the department (case insensitive) and the role of a rule, an empty condition matches
+// any value of its field. A rule without any condition matches nobody, saving such a rule is rejected.
+func RuleMatchesUser(rule m.PathAssignmentRule, user m.User) bool {
+	if rule.Department == "" && rule.RoleID == 0 {
+		return false
+	}
+
+	if rule.Department != "" && !strings.EqualFold(strings.TrimSpace(rule.Department), strings.TrimSpace(user.UserProfile.Department)) {
+		return false
+	}
+
+	return rule.RoleID == 0 || rule.RoleID == user.RoleID
+}
+
+// ApplyPathRules assigns the template paths of every active rule matching the user,
+// courses the user is already enrolled in are left untouched
+func (service *Service) ApplyPathRules(user m.User, assignedBy int) []response.PathAssignmentResponse {
+	assignments := []response.PathAssignmentResponse{}
+
+	rules, err := service.PathRuleRepo.GetActiveRules()
+	if err != nil {
+		service.Logger.Errorf("Failed to fetch path assignment rules for user %d: %v", user.ID, err)
+		return assignments
+	}
+
+	for _, rule := range rules {
+		if !RuleMatchesUser(rule, user) {
+			continue
+		}
+
+		templatePath, err := service.TemplatePathRepo.GetTemplatePathByID(rule.TemplatePathID)
+		if err != nil {
+			service.Logger.Errorf("Failed to fetch template path %d of rule %d: %v", rule.TemplatePathID, rule.ID, err)
+			continue
+		}
+
+		dueDateParams := param.DueDateParams{
+			DueInDays:     rule.DueInDays,
+			DueRelativeTo: cf.DueRelativeToAssignment,
+		}
+
+		assignment := service.AssignTemplatePath(user.ID, templatePath, dueDateParams, assignedBy)
+		if len(assignment.AddedCourses) > 0 {
+			service.Logger.Infof("Rule %d assigned template path %d to user %d", rule.ID, templatePath.ID, user.ID)
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments
+}
+
+// AssignTemplatePath enrolls a user in the courses of a template path in path order
+func (service *Service) AssignTemplatePath(userID int, templatePath m.TemplatePath, dueDateParams param.DueDateParams, assignedBy int) response.PathAssignmentResponse {
+	assignment := response.PathAssignmentResponse{
+		UserID:         userID,
+		AddedCourses:   []int{},
+		BlockedCourses: []response.BlockedEnrollmentResponse{},
+	}
+
+	dueDate, err := service.ResolveDueDate(dueDateParams, userID)
+	if err != nil {
+		assignment.Error = err.Error()
+		return assignment
+	}
+
+	// Electives are enrolled when the trainee chooses them
+	requiredCourseIDs := PathRequiredCourseIDs(templatePath)
+	assignment.AddedCourses, assignment.BlockedCourses, err = service.enrollCourses(userID, requiredCourseIDs, PathCoursePositions(templatePath), dueDate, assignedBy, templatePath.ID)
+	if err != nil {
+		service.Logger.Errorf("Failed to assign template path %d to user %d: %v", templatePath.ID, userID, err)
+		assignment.Error = "Failed to check course prerequisites"
+	}
+
+	return assignment
+}
+
+// GetBlockedCourses returns, for each course that cannot be assigned, its unmet prerequisites.
+// A prerequisite is met when the user completed it or when it is assigned in the same request
+// and is not blocked itself.
+func (service *Service) GetBlockedCourses(userID int, courseIDs []int) (map[int][]m.Course, error) {
+	missingByCourse := make(map[int][]m.Course)
+	for _, courseID := range courseIDs {
+		missing, err := service.PrerequisiteRepo.GetMissingPrerequisites(userID, courseID)
+		if err != nil {
+			return nil, err
+		}
+		missingByCourse[courseID] = missing
+	}
+
+	blockedCourses := make(map[int][]m.Course)
+	for changed := true; changed; {
+		changed = false
+
+		for _, courseID := range courseIDs {
+			if _, blocked := blockedCourses[courseID]; blocked {
+				continue
+			}
+
+			unmet := []m.Course{}
+			for _, prerequisite := range missingByCourse[courseID] {
+				_, prerequisiteBlocked := blockedCourses[prerequisite.ID]
+				if !utils.FindIntInSlice(courseIDs, prerequisite.ID) || prerequisiteBlocked {
+					unmet = append(unmet, prerequisite)
+				}
+			}
+
+			if len(unmet) > 0 {
+				blockedCourses[courseID] = unmet
+				changed = true
+			}
+		}
+	}
+
+	return blockedCourses, nil
+}
+
+func NewBlockedEnrollment(userID int, courseID int, missing []m.Course) response.BlockedEnrollmentResponse {
+	blockedEnrollment := response.BlockedEnrollmentResponse{
+		UserID:               userID,
+		CourseID:             courseID,
+		MissingPrerequisites: []response.PrerequisiteCourseResponse{},
+	}
+
+	for _, course := range missing {
+		blockedEnrollment.MissingPrerequisites = append(blockedEnrollment.MissingPrerequisites, response.PrerequisiteCourseResponse{
+			CourseID: course.ID,
+			Title:    course.Title,
+		})
+	}
+
+	return blockedEnrollment
+}
+
+// ValidateDueDateParams checks the due date options before any enrollment is created
+func ValidateDueDateParams(dueDateParams param.DueDateParams) error {
+	if dueDateParams.DueDate != "" {
+		if _, err := time.Parse(cf.FormatDateDatabase, dueDateParams.DueDate); err != nil {
+			return errors.New("due_date must be formatted as YYYY-MM-DD")
+		}
+		return nil
+	}
+
+	if dueDateParams.DueInDays < 0 {
+		return errors.New("due_in_days must be a positive number")
+	}
+
+	switch dueDateParams.DueRelativeTo {
+	case "", cf.DueRelativeToAssignment, cf.DueRelativeToJoinedDate:
+		return nil
+	default:
+		return errors.New("due_relative_to must be assignment or company_joined_date")
+	}
+}
+
+// ResolveDueDate returns the due date (YYYY-MM-DD) of a new enrollment, empty when it has no deadline
+func (service *Service) ResolveDueDate(dueDateParams param.DueDateParams, userID int) (string, error) {
+	if err := ValidateDueDateParams(dueDateParams); err != nil {
+		return "", err
+	}
+
+	if dueDateParams.DueDate != "" {
+		return dueDateParams.DueDate, nil
+	}
+
+	if dueDateParams.DueInDays == 0 {
+		return "", nil
+	}
+
+	startDate := utils.TimeNowUTC()
+	if dueDateParams.DueRelativeTo == cf.DueRelativeToJoinedDate {
+		user, err := service.UserRepo.GetUserProfile(userID)
+		if err != nil {
+			service.Logger.Errorf("Failed to fetch user %d for due date: %v", userID, err)
+			return "", errors.New("user not found")
+		}
+
+		startDate, err = time.Parse(cf.FormatDateDatabase, utils.FormatDueDate(user.UserProfile.CompanyJoinedDate))
+		if err != nil {
+			return "", errors.New("user has no valid company joined date")
+		}
+	}
+
+	return startDate.AddDate(0, 0, dueDateParams.DueInDays).Format(cf.FormatDateDatabase), nil
+}
+
+// EnrollCourses creates the enrollments of a user following the order of courseIDs.
+// Courses already assigned are skipped and courses with unmet prerequisites are reported as blocked,
+// the courses enrolled keep their place in courseIDs as course position so a blocked course leaves a gap.
+func (service *Service) EnrollCourses(userID int, courseIDs []int, dueDate string, assignedBy int, templatePathID int) ([]int, []response.BlockedEnrollmentResponse, error) {
+	positions := make(map[int]int)
+	for i, courseID := range courseIDs {
+		positions[courseID] = i + 1
+	}
+
+	return service.enrollCourses(userID, courseIDs, positions, dueDate, assignedBy, templatePathID)
+}
+
+// ChooseElective enrolls a user in an elective course of a path assigned to them,
+// the enrollment gets the due date and the assigner of the path
+func (service *Service) ChooseElective(userID int, templatePath m.TemplatePath, courseID int) (response.PathAssignmentResponse, error) {
+	assignment := response.PathAssignmentResponse{
+		UserID:         userID,
+		AddedCourses:   []int{},
+		BlockedCourses: []response.BlockedEnrollmentResponse{},
+	}
+
+	userProgresses, err := service.UserRepo.GetUserProgressByUserID(userID)
+	if err != nil {
+		service.Logger.Errorf("Failed to fetch progresses of user %d: %v", userID, err)
+		return assignment, errors.New("failed to fetch user progresses")
+	}
+
+	assigned := false
+	latestDueDate := time.Time{}
+	assignedBy := 0
+	for _, progress := range userProgresses {
+		if progress.TemplatePathID != templatePath.ID {
+			continue
+		}
+
+		assigned = true
+		assignedBy = progress.AssignedBy
+		progressDueDate, err := time.Parse(cf.FormatDateDatabase, utils.FormatDueDate(progress.DueDate))
+		if err == nil && progressDueDate.After(latestDueDate) {
+			latestDueDate = progressDueDate
+		}
+	}
+
+	dueDate := ""
+	if !latestDueDate.IsZero() {
+		dueDate = latestDueDate.Format(cf.FormatDateDatabase)
+	}
+
+	if !assigned {
+		return assignment, errors.New("template path is not assigned to this user")
+	}
+
+	for _, stageProgress := range EvaluatePathStages(templatePath, userProgresses) {
+		if stageProgress.StageType != cf.StageTypeElective {
+			continue
+		}
+
+		for _, stageCourse := range stageProgress.Courses {
+			if stageCourse.CourseID != courseID {
+				continue
+			}
+
+			if stageCourse.Enrolled {
+				return assignment, errors.New("course is already chosen")
+			}
+
+			if stageProgress.ElectivesToChoose == 0 {
+				return assignment, errors.New("all electives of this stage are already chosen")
+			}
+
+			assignment.AddedCourses, assignment.BlockedCourses, err = service.enrollCourses(userID, []int{courseID}, PathCoursePositions(templatePath), dueDate, assignedBy, templatePath.ID)
+			if err != nil {
+				service.Logger.Errorf("Failed to enroll user %d in elective %d: %v", userID, courseID, err)
+				assignment.Error = "Failed to check course prerequisites"
+			}
+
+			return assignment, nil
+		}
+	}
+
+	return assignment, errors.New("course is not an elective of this template path")
+}
+
+// enrollCourses enrolls a user in the courses not blocked by prerequisites at their given positions,
+// positions are not renumbered so a course enrolled later still takes its place in the order
+func (service *Service) enrollCourses(userID int, courseIDs []int, positions map[int]int, dueDate string, assignedBy int, templatePathID int) ([]int, []response.BlockedEnrollmentResponse, error) {
+	addedCourses := []int{}
+	blockedEnrollments := []response.BlockedEnrollmentResponse{}
+
+	blockedCourses, err := service.GetBlockedCourses(userID, courseIDs)
+	if err != nil {
+		return addedCourses, blockedEnrollments, err
+	}
+
+	for _, courseID := range courseIDs {
+		if missing, blocked := blockedCourses[courseID]; blocked {
+			blockedEnrollments = append(blockedEnrollments, NewBlockedEnrollment(userID, courseID, missing))
+			continue
+		}
+
+		userProgress, err := service.newUserProgress(userID, courseID, positions[courseID], dueDate, assignedBy, templatePathID)
+		if err != nil || userProgress == nil {
+			continue
+		}
+
+		if err := service.UserProgressRepo.SaveUserProgress(userProgress); err != nil {
+			service.Logger.Errorf("Failed to create user progress for course %d: %v", courseID, err)
+			continue
+		}
+
+		addedCourses = append(addedCourses, courseID)
+	}
+
+	return addedCourses, blockedEnrollments, nil
+}
+
+// NewEnrollment prepares the enrollment of a user in a course at a position without saving it,
+// the enrollment is nil when the course is blocked by prerequisites or already assigned to the user
+func (service *Service) NewEnrollment(userID int, courseID int, position int, dueDate string, assignedBy int, templatePathID int) (*m.UserProgress, []response.BlockedEnrollmentResponse, error) {
+	blockedEnrollments := []response.BlockedEnrollmentResponse{}
+
+	blockedCourses, err := service.GetBlockedCourses(userID, []int{courseID})
+	if err != nil {
+		return nil, blockedEnrollments, err
+	}
+	if missing, blocked := blockedCourses[courseID]; blocked {
+		return nil, append(blockedEnrollments, NewBlockedEnrollment(userID, courseID, missing)), nil
+	}
+
+	userProgress, err := service.newUserProgress(userID, courseID, position, dueDate, assignedBy, templatePathID)
+	return userProgress, blockedEnrollments, err
+}
+
+// newUserProgress builds the enrollment of a user in a course, nil when the user is already enrolled.
+// A course assigned again after it was unassigned starts a new cycle.
+func (service *Service) newUserProgress(userID int, courseID int, position int, dueDate string, assignedBy int, templatePathID int) (*m.UserProgress, error) {
+	existingProgress, err := service.UserProgressRepo.GetSingleUserProgress(userID, courseID)
+	if err == nil && existingProgress.ID != 0 {
+		service.Logger.Infof("Progress already exists for user %d and course %d", userID, courseID)
+		return nil, nil
+	}
+
+	latestCycle, err := service.UserProgressRepo.GetLatestCycle(userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m.UserProgress{
+		UserID:             userID,
+		CourseID:           courseID,
+		CoursePosition:     position,
+		ModulePosition:     1,
+		ModuleItemPosition: 1,
+		Completed:          false,
+		DueDate:            dueDate,
+		AssignedBy:         assignedBy,
+		TemplatePathID:     templatePathID,
+		Cycle:              latestCycle + 1,
+	}, nil
+}
@@ -1,16 +1,23 @@
 package lectures
 
 import (
+	"fmt"
 	"net/http"
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/progression"
+	"orientation-training-api/internal/domains/quizzes"
 	rp "orientation-training-api/internal/interfaces/repository"
 	param "orientation-training-api/internal/interfaces/requestparams"
 	response "orientation-training-api/internal/interfaces/response"
 	m "orientation-training-api/internal/models"
 	cld "orientation-training-api/internal/platform/cloud"
+	"orientation-training-api/internal/platform/scorm"
+	"orientation-training-api/internal/platform/utils"
+	"orientation-training-api/internal/platform/xapi"
 	"orientation-training-api/internal/platform/youtube"
 	"os"
+	"time"
 
 	valid "github.com/asaskevich/govalidator"
 	"github.com/labstack/echo/v4"
@@ -24,6 +31,10 @@ type LectureController struct {
 	CourseRepo       rp.CourseRepository
 	UserProgressRepo rp.UserProgressRepository
 	QuizRepo         rp.QuizRepository
+	ScormRepo        rp.ScormRepository
+	XapiRepo         rp.XapiRepository
+	PrerequisiteRepo rp.CoursePrerequisiteRepository
+	Progression      *progression.Service
 	Cloud            cld.StorageUtility
 }
 
@@ -34,6 +45,10 @@ func NewLectureController(
 	courseRepo rp.CourseRepository,
 	upRepo rp.UserProgressRepository,
 	quizRepo rp.QuizRepository,
+	scormRepo rp.ScormRepository,
+	xapiRepo rp.XapiRepository,
+	prerequisiteRepo rp.CoursePrerequisiteRepository,
+	progressionService *progression.Service,
 	cloud cld.StorageUtility) (ctr *LectureController) {
 
 	ctr = &LectureController{
@@ -43,6 +58,10 @@ func NewLectureController(
 		courseRepo,
 		upRepo,
 		quizRepo,
+		scormRepo,
+		xapiRepo,
+		prerequisiteRepo,
+		progressionService,
 		cloud,
 	}
 	ctr.Init(logger)
@@ -79,8 +98,6 @@ func (ctr *LectureController) GetLectureList(c echo.Context) error {
 		})
 	}
 
-	currentModulePosition, currentModuleItemPosition := userProgress.ModulePosition, userProgress.ModuleItemPosition
-
 	moduleListParams := &param.ModuleListParams{
 		CourseID: lectureListParams.CourseID,
 	}
@@ -106,6 +123,41 @@ func (ctr *LectureController) GetLectureList(c echo.Context) error {
 		allModuleItems = append(allModuleItems, moduleItems...)
 	}
 
+	missingPrerequisites, err := ctr.PrerequisiteRepo.GetMissingPrerequisites(userProfile.ID, lectureListParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch missing prerequisites: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to check course prerequisites",
+		})
+	}
+
+	if len(missingPrerequisites) > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "Complete the prerequisite courses to unlock this course",
+			Data:    buildLockedLectureList(modules, allModuleItems, missingPrerequisites),
+		})
+	}
+
+	courseItems, err := ctr.Progression.GetCourseItems(lectureListParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch course items: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch module items",
+		})
+	}
+
+	itemStates, err := ctr.Progression.GetItemStates(userProgress, courseItems)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch item completions: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch user progress",
+		})
+	}
+
 	moduleResponses := []response.LectureModuleResponse{}
 
 	for _, module := range modules {
@@ -122,18 +174,16 @@ func (ctr *LectureController) GetLectureList(c echo.Context) error {
 				continue
 			}
 
-			isUnlocked := false
-			if module.Position < currentModulePosition ||
-				(module.Position == currentModulePosition && item.Position <= currentModuleItemPosition) {
-				isUnlocked = true
-			}
-
 			lectureItem := response.LectureItemResponse{
 				ModuleItemID:       item.ID,
 				ModuleItemTitle:    item.Title,
 				ModuleItemPosition: item.Position,
 				ItemType:           item.ItemType,
-				Unlocked:           isUnlocked,
+				Unlocked:           itemStates[item.ID].Unlocked,
+				Completed:          itemStates[item.ID].Completed,
+				TimeSpent:          itemStates[item.ID].Completion.TimeSpent,
+				RequiredTimeMet:    progression.RequiredTimeMet(item, itemStates[item.ID].Completion.TimeSpent),
+				UnlockDate:         itemStates[item.ID].UnlockDate,
 			}
 
 			if item.ItemType == "video" {
@@ -174,60 +224,60 @@ func (ctr *LectureController) GetLectureList(c echo.Context) error {
 					continue
 				}
 
-				questions, err := ctr.QuizRepo.GetQuizQuestionsWithAnswers(item.QuizID)
+				attemptQuestions, err := quizzes.QuestionsForUser(ctr.QuizRepo, quiz, userProfile.ID)
 				if err != nil {
 					ctr.Logger.Errorf("Failed to fetch quiz questions for quiz ID %d: %v", item.QuizID, err)
 					continue
 				}
 
+				questions := make([]m.QuizQuestion, len(attemptQuestions))
+				for i, attemptQuestion := range attemptQuestions {
+					questions[i] = attemptQuestion.QuizQuestion
+				}
+
+				drawRules, err := ctr.QuizRepo.GetDrawRules(item.QuizID)
+				if err != nil {
+					ctr.Logger.Errorf("Failed to fetch draw rules for quiz ID %d: %v", item.QuizID, err)
+					continue
+				}
+
 				quizContent := response.QuizContentResponse{
 					QuizID:     quiz.ID,
 					QuizTitle:  item.Title,
 					Difficulty: cf.DifficultyLabels[quiz.Difficulty],
 					TotalScore: quiz.TotalScore,
 					TimeLimit:  quiz.TimeLimit,
+
+					PassMark:      quiz.PassMark,
+					PassMarkType:  quiz.PassMarkType,
+					PassThreshold: quizzes.PassThreshold(quiz),
+
+					DrawsQuestions: len(drawRules) > 0,
 				}
 
-				if len(questions) > 0 && questions[0].QuestionType == cf.QuestionTypeEssay {
-					quizContent.QuizType = "essay"
-					quizContent.Questions = []response.QuizQuestionResponse{}
-
-					for _, q := range questions {
-						questionResponse := response.QuizQuestionResponse{
-							QuestionID:   q.ID,
-							QuestionText: q.QuestionText,
-							Points:       q.Weight * quiz.TotalScore,
-							Options:      []response.QuizOptionResponse{},
-						}
-
-						quizContent.Questions = append(quizContent.Questions, questionResponse)
-					}
-				} else {
-					quizContent.QuizType = "multiple_choice"
-					quizContent.Questions = []response.QuizQuestionResponse{}
-
-					for _, q := range questions {
-						questionResponse := response.QuizQuestionResponse{
-							QuestionID:    q.ID,
-							QuestionText:  q.QuestionText,
-							AllowMultiple: q.IsMultipleCorrect,
-							Points:        q.Weight * quiz.TotalScore,
-							Options:       []response.QuizOptionResponse{},
-						}
-
-						for _, a := range q.Answers {
-							option := response.QuizOptionResponse{
-								ID:   a.ID,
-								Text: a.AnswerText,
-							}
-							questionResponse.Options = append(questionResponse.Options, option)
-						}
-
-						quizContent.Questions = append(quizContent.Questions, questionResponse)
-					}
+				quizContent.QuizType = quizzes.QuizType(questions)
+
+				quizContent.Questions = []response.QuizQuestionResponse{}
+				for _, attemptQuestion := range attemptQuestions {
+					quizContent.Questions = append(quizContent.Questions, quizzes.NewQuizQuestionResponse(attemptQuestion.QuizQuestion, attemptQuestion.Points))
 				}
 
 				lectureItem.Content = quizContent
+			} else if item.ItemType == "scorm" {
+				scormPackage, err := ctr.ScormRepo.GetScormPackageByModuleItemID(item.ID)
+				if err != nil {
+					ctr.Logger.Errorf("Failed to fetch SCORM package for module item ID %d: %v", item.ID, err)
+					continue
+				}
+
+				// The package is served from the API origin so that it reaches the runtime API of the player frame
+				contentToken := scorm.NewContentToken(utils.GetKeyToken(), userProfile.ID, item.ID, utils.TimeNowUTC().Add(cf.ScormContentTokenTTL*time.Hour))
+				scormContent := response.ScormContentResponse{
+					Version:      scormPackage.Version,
+					LaunchURL:    fmt.Sprintf("/scorm/content/%d/%s/%s", item.ID, contentToken, scormPackage.LaunchPath),
+					RequiredTime: item.RequiredTime,
+				}
+				lectureItem.Content = scormContent
 			}
 
 			moduleResponse.Lectures = append(moduleResponse.Lectures, lectureItem)
@@ -236,9 +286,84 @@ func (ctr *LectureController) GetLectureList(c echo.Context) error {
 		moduleResponses = append(moduleResponses, moduleResponse)
 	}
 
+	ctr.recordLaunchedStatement(userProfile, userProgress, courseItems)
+
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
 		Message: "Success",
 		Data:    moduleResponses,
 	})
 }
+
+// recordLaunchedStatement emits a launched xAPI statement for the lecture the trainee is on, within its course
+func (ctr *LectureController) recordLaunchedStatement(userProfile m.User, userProgress m.UserProgress, courseItems []progression.CourseItem) {
+	for _, courseItem := range courseItems {
+		if courseItem.ModulePosition != userProgress.ModulePosition || courseItem.Item.Position != userProgress.ModuleItemPosition {
+			continue
+		}
+
+		courseTitle := ""
+		if course, err := ctr.CourseRepo.GetCourseByID(userProgress.CourseID); err == nil {
+			courseTitle = course.Title
+		}
+
+		statement := xapi.NewStatement(
+			xapi.NewAgent(userProfile.Email, userProfile.UserProfile.FirstName+" "+userProfile.UserProfile.LastName),
+			xapi.VerbLaunched,
+			xapi.NewActivity(xapi.ActivityTypeLesson, courseItem.Item.ID, courseItem.Item.Title),
+		).WithParent(xapi.NewActivity(xapi.ActivityTypeCourse, userProgress.CourseID, courseTitle))
+		statement.Context.Extensions = map[string]interface{}{
+			xapi.ExtensionModulePosition:     userProgress.ModulePosition,
+			xapi.ExtensionModuleItemPosition: userProgress.ModuleItemPosition,
+		}
+
+		if err := ctr.XapiRepo.RecordStatement(userProfile.ID, statement); err != nil {
+			ctr.Logger.Errorf("Failed to record xAPI statement: %v", err)
+		}
+		return
+	}
+}
+
+// buildLockedLectureList lists the course outline with every lecture locked and no content
+func buildLockedLectureList(modules []m.Module, moduleItems []m.ModuleItem, missingPrerequisites []m.Course) response.LockedLectureListResponse {
+	lockedResponse := response.LockedLectureListResponse{
+		Locked:               true,
+		MissingPrerequisites: []response.PrerequisiteCourseResponse{},
+		Modules:              []response.LectureModuleResponse{},
+	}
+
+	for _, course := range missingPrerequisites {
+		lockedResponse.MissingPrerequisites = append(lockedResponse.MissingPrerequisites, response.PrerequisiteCourseResponse{
+			CourseID: course.ID,
+			Title:    course.Title,
+		})
+	}
+
+	for _, module := range modules {
+		moduleResponse := response.LectureModuleResponse{
+			ModuleID:       module.ID,
+			ModuleTitle:    module.Title,
+			ModulePosition: module.Position,
+			Duration:       module.Duration,
+			Lectures:       []response.LectureItemResponse{},
+		}
+
+		for _, item := range moduleItems {
+			if item.ModuleID != module.ID {
+				continue
+			}
+
+			moduleResponse.Lectures = append(moduleResponse.Lectures, response.LectureItemResponse{
+				ModuleItemID:       item.ID,
+				ModuleItemTitle:    item.Title,
+				ModuleItemPosition: item.Position,
+				ItemType:           item.ItemType,
+				Unlocked:           false,
+			})
+		}
+
+		lockedResponse.Modules = append(lockedResponse.Modules, moduleResponse)
+	}
+
+	return lockedResponse
+}
@@ -1,13 +1,17 @@
 package moduleitem
 
 import (
+	"encoding/base64"
 	"net/http"
 	"net/url"
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/quizzes"
 	rp "orientation-training-api/internal/interfaces/repository"
 	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
 	cld "orientation-training-api/internal/platform/cloud"
+	"orientation-training-api/internal/platform/scorm"
 	"orientation-training-api/internal/platform/utils"
 	"orientation-training-api/internal/platform/youtube"
 	"strconv"
@@ -25,11 +29,12 @@ type ModuleItemController struct {
 
 	ModuleItemRepo rp.ModuleItemRepository
 	QuizRepo       rp.QuizRepository
+	ScormRepo      rp.ScormRepository
 	cloud          cld.StorageUtility
 }
 
-func NewModuleItemController(logger echo.Logger, moduleItemRepo rp.ModuleItemRepository, quizRepo rp.QuizRepository, cloud cld.StorageUtility) (ctr *ModuleItemController) {
-	ctr = &ModuleItemController{cm.BaseController{}, moduleItemRepo, quizRepo, cloud}
+func NewModuleItemController(logger echo.Logger, moduleItemRepo rp.ModuleItemRepository, quizRepo rp.QuizRepository, scormRepo rp.ScormRepository, cloud cld.StorageUtility) (ctr *ModuleItemController) {
+	ctr = &ModuleItemController{cm.BaseController{}, moduleItemRepo, quizRepo, scormRepo, cloud}
 	ctr.Init(logger)
 	return
 }
@@ -106,10 +111,11 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 		(createModuleItemParams.ItemType != "video" &&
 			createModuleItemParams.ItemType != "file" &&
 			createModuleItemParams.ItemType != "quiz" &&
-			createModuleItemParams.ItemType != "slide") { // Thêm slide
+			createModuleItemParams.ItemType != "slide" && // Thêm slide
+			createModuleItemParams.ItemType != "scorm") {
 		return c.JSON(http.StatusBadRequest, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Invalid item_type. Allowed values: video, file, quiz, slide",
+			Message: "Invalid item_type. Allowed values: video, file, quiz, slide, scorm",
 		})
 	}
 
@@ -132,6 +138,7 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 	}
 
 	var quizID int = 0
+	var scormPackage *scorm.Package
 
 	if createModuleItemParams.ItemType == "video" {
 		if !valid.IsURL(createModuleItemParams.Resource) {
@@ -304,17 +311,17 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 			})
 		}
 
-		if createModuleItemParams.QuizData.QuestionType != cf.QuesMultipleChoice && createModuleItemParams.QuizData.QuestionType != cf.QuesEssay {
+		if len(createModuleItemParams.QuizData.Questions) == 0 {
 			return c.JSON(http.StatusBadRequest, cf.JsonResponse{
 				Status:  cf.FailResponseCode,
-				Message: "Invalid question type. Allowed values: 1 (multiple choice), 2 (essay)",
+				Message: "Quiz must have at least one question",
 			})
 		}
 
-		if len(createModuleItemParams.QuizData.Questions) == 0 {
+		if message := quizzes.ValidatePolicyParams(createModuleItemParams.QuizData.QuizPolicyParams, createModuleItemParams.QuizData.TotalScore); message != "" {
 			return c.JSON(http.StatusBadRequest, cf.JsonResponse{
 				Status:  cf.FailResponseCode,
-				Message: "Quiz must have at least one question",
+				Message: message,
 			})
 		}
 
@@ -322,28 +329,18 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 		for _, question := range createModuleItemParams.QuizData.Questions {
 			totalWeight += question.Weight
 
-			if createModuleItemParams.QuizData.QuestionType == cf.QuesMultipleChoice {
-				if len(question.Options) < 2 {
-					return c.JSON(http.StatusBadRequest, cf.JsonResponse{
-						Status:  cf.FailResponseCode,
-						Message: "Multiple choice questions must have at least two options",
-					})
-				}
-
-				hasCorrectAnswer := false
-				for _, option := range question.Options {
-					if option.IsCorrect {
-						hasCorrectAnswer = true
-						break
-					}
-				}
+			if message := quizzes.ValidateScoringStrategy(question.ScoringStrategy); message != "" {
+				return c.JSON(http.StatusBadRequest, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: message,
+				})
+			}
 
-				if !hasCorrectAnswer {
-					return c.JSON(http.StatusBadRequest, cf.JsonResponse{
-						Status:  cf.FailResponseCode,
-						Message: "Multiple choice questions must have at least one correct answer",
-					})
-				}
+			if _, _, message := quizzes.NewQuestionFromData(createModuleItemParams.QuizData, question); message != "" {
+				return c.JSON(http.StatusBadRequest, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: message,
+				})
 			}
 		}
 
@@ -372,6 +369,43 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 		createModuleItemParams.QuizID = quizID
 		createModuleItemParams.Resource = ""
 		createModuleItemParams.RequiredTime = 0
+	} else if createModuleItemParams.ItemType == "scorm" {
+		parts := strings.SplitN(createModuleItemParams.Resource, ",", 2)
+		if len(parts) != 2 || !strings.Contains(parts[0], "zip") {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "SCORM package must be a zip file",
+			})
+		}
+
+		zipData, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Invalid File Format",
+			})
+		}
+
+		scormPackage, err = scorm.ReadPackage(zipData)
+		if err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: err.Error(),
+			})
+		}
+
+		millisecondTimeNow := int(time.Now().UnixNano() / int64(time.Millisecond))
+		folderName := strconv.Itoa(createModuleItemParams.ModuleID) + "_" + strconv.Itoa(millisecondTimeNow)
+
+		if err := ctr.uploadScormFiles(folderName, scormPackage.Files); err != nil {
+			ctr.Logger.Error(err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to upload SCORM package to cloud",
+			})
+		}
+
+		createModuleItemParams.Resource = folderName
 	}
 
 	savedItem, err := ctr.ModuleItemRepo.SaveModuleItem(createModuleItemParams)
@@ -384,12 +418,41 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 			}
 		}
 
+		if scormPackage != nil {
+			ctr.deleteScormFiles(createModuleItemParams.Resource, scormFilePaths(scormPackage.Files))
+		}
+
 		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
 			Message: "Failed to save Module Item to database",
 		})
 	}
 
+	if scormPackage != nil {
+		err = ctr.ScormRepo.SaveScormPackage(&m.ScormPackage{
+			ModuleItemID: savedItem.ID,
+			Version:      scormPackage.Version,
+			Identifier:   scormPackage.Identifier,
+			Title:        scormPackage.Title,
+			LaunchPath:   scormPackage.LaunchPath,
+			BasePath:     savedItem.Resource,
+			Files:        scormFilePaths(scormPackage.Files),
+		})
+		if err != nil {
+			ctr.Logger.Errorf("Failed to save SCORM package: %v", err)
+
+			if deleteErr := ctr.ModuleItemRepo.DeleteModuleItem(savedItem.ID); deleteErr != nil {
+				ctr.Logger.Errorf("Failed to clean up module item after SCORM package creation failed: %v", deleteErr)
+			}
+			ctr.deleteScormFiles(savedItem.Resource, scormFilePaths(scormPackage.Files))
+
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to save SCORM package to database",
+			})
+		}
+	}
+
 	moduleItemResponse := map[string]interface{}{
 		"id":       savedItem.ID,
 		"type":     savedItem.ItemType,
@@ -399,6 +462,10 @@ func (ctr *ModuleItemController) AddModuleItem(c echo.Context) error {
 
 	if savedItem.ItemType == "quiz" {
 		moduleItemResponse["quiz_id"] = quizID
+	} else if savedItem.ItemType == "scorm" {
+		moduleItemResponse["resource"] = savedItem.Resource
+		moduleItemResponse["scorm_version"] = scormPackage.Version
+		moduleItemResponse["launch_path"] = scormPackage.LaunchPath
 	} else {
 		moduleItemResponse["resource"] = savedItem.Resource
 		moduleItemResponse["required_time"] = savedItem.RequiredTime
@@ -454,6 +521,23 @@ func (ctr *ModuleItemController) DeleteModuleItem(c echo.Context) error {
 				Data:    err,
 			})
 		}
+	} else if moduleItem.ItemType == "scorm" {
+		scormPackage, err := ctr.ScormRepo.GetScormPackageByModuleItemID(moduleItem.ID)
+		if err != nil && err.Error() != pg.ErrNoRows.Error() {
+			ctr.Logger.Errorf("Failed to fetch SCORM package: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+
+		if err == nil {
+			ctr.deleteScormFiles(scormPackage.BasePath, scormPackage.Files)
+
+			if err := ctr.ScormRepo.DeleteScormPackageByModuleItemID(moduleItem.ID); err != nil {
+				ctr.Logger.Errorf("Failed to delete SCORM package: %v", err)
+			}
+		}
 	}
 	err := ctr.ModuleItemRepo.DeleteModuleItem(moduleItemIDParam.ModuleItemID)
 
@@ -469,3 +553,42 @@ func (ctr *ModuleItemController) DeleteModuleItem(c echo.Context) error {
 		Message: "Deleted",
 	})
 }
+
+// uploadScormFiles : upload every file extracted from a SCORM package under its own folder
+// Params : folderName, files
+// Returns : error
+func (ctr *ModuleItemController) uploadScormFiles(folderName string, files []scorm.PackageFile) error {
+	uploaded := []string{}
+	for _, file := range files {
+		err := ctr.cloud.UploadFileToCloud(
+			base64.StdEncoding.EncodeToString(file.Data),
+			file.Path,
+			cf.ScormFolderGCS+folderName+"/",
+		)
+		if err != nil {
+			ctr.deleteScormFiles(folderName, uploaded)
+			return err
+		}
+		uploaded = append(uploaded, file.Path)
+	}
+
+	return nil
+}
+
+// deleteScormFiles : delete the files of a SCORM package from cloud, logging failures
+// Params : folderName, filePaths
+func (ctr *ModuleItemController) deleteScormFiles(folderName string, filePaths []string) {
+	for _, filePath := range filePaths {
+		if err := ctr.cloud.DeleteFileCloud(filePath, cf.ScormFolderGCS+folderName+"/"); err != nil {
+			ctr.Logger.Errorf("Failed to delete SCORM file %s: %v", filePath, err)
+		}
+	}
+}
+
+func scormFilePaths(files []scorm.PackageFile) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
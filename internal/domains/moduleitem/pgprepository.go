@@ -99,3 +99,19 @@ func (repo *PgModuleItemRepository) GetModuleItemsByModuleID(moduleID int) ([]m.
 	}
 	return moduleItems, nil
 }
+
+// GetModuleItemsByQuizID : retrieve the module items of a quiz
+// Params : quizID
+// Returns : slice of module items and error
+func (repo *PgModuleItemRepository) GetModuleItemsByQuizID(quizID int) ([]m.ModuleItem, error) {
+	moduleItems := []m.ModuleItem{}
+	err := repo.DB.Model(&moduleItems).
+		Where("item_type = ?", "quiz").
+		Where("quiz_id = ?", quizID).
+		Where("deleted_at is null").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return moduleItems, nil
+}
@@ -0,0 +1,213 @@
+package moduleitemcompletion
+
+import (
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type PgModuleItemCompletionRepository struct {
+	cm.AppRepository
+}
+
+func NewPgModuleItemCompletionRepository(logger echo.Logger) (repo *PgModuleItemCompletionRepository) {
+	repo = &PgModuleItemCompletionRepository{}
+	repo.Init(logger)
+	return
+}
+
+// GetCompletionsByCourse : get the item records of a user for a cycle of a course
+func (repo *PgModuleItemCompletionRepository) GetCompletionsByCourse(userID int, courseID int, cycle int) ([]m.ModuleItemCompletion, error) {
+	completions := []m.ModuleItemCompletion{}
+
+	err := repo.DB.Model(&completions).
+		Where("user_id = ?", userID).
+		Where("course_id = ?", courseID).
+		Where("cycle = ?", cycle).
+		Where("deleted_at IS NULL").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching item completions of user %d for course %d: %v", userID, courseID, err)
+	}
+
+	return completions, err
+}
+
+// GetCompletion : get the record of a user for an item in a cycle
+func (repo *PgModuleItemCompletionRepository) GetCompletion(userID int, moduleItemID int, cycle int) (m.ModuleItemCompletion, error) {
+	completion := m.ModuleItemCompletion{}
+
+	err := repo.DB.Model(&completion).
+		Where("user_id = ?", userID).
+		Where("module_item_id = ?", moduleItemID).
+		Where("cycle = ?", cycle).
+		Where("deleted_at IS NULL").
+		First()
+
+	return completion, err
+}
+
+// StartItem : create the in progress record of an item, an existing record is kept
+func (repo *PgModuleItemCompletionRepository) StartItem(completion *m.ModuleItemCompletion) error {
+	completion.Status = cf.ItemStatusInProgress
+
+	_, err := repo.DB.Model(completion).
+		OnConflict("(user_id, module_item_id, cycle) DO NOTHING").
+		Insert()
+
+	if err != nil {
+		repo.Logger.Errorf("Error starting item %d for user %d: %v", completion.ModuleItemID, completion.UserID, err)
+	}
+
+	return err
+}
+
+// RecordItemView : count a view of an item, the first view starts the item
+func (repo *PgModuleItemCompletionRepository) RecordItemView(completion *m.ModuleItemCompletion) error {
+	completion.Status = cf.ItemStatusInProgress
+	completion.ViewCount = 1
+	completion.LastViewedAt = completion.StartedAt
+
+	_, err := repo.DB.Model(completion).
+		OnConflict("(user_id, module_item_id, cycle) DO UPDATE").
+		Set("view_count = module_item_completion.view_count + 1").
+		Set("last_viewed_at = EXCLUDED.last_viewed_at").
+		Set("updated_at = NOW()").
+		Returning("*").
+		Insert()
+
+	if err != nil {
+		repo.Logger.Errorf("Error recording view of item %d for user %d: %v", completion.ModuleItemID, completion.UserID, err)
+	}
+
+	return err
+}
+
+// CompleteItem : mark an item completed with the time spent and the score reached
+func (repo *PgModuleItemCompletionRepository) CompleteItem(completion *m.ModuleItemCompletion) error {
+	completion.Status = cf.ItemStatusCompleted
+
+	_, err := repo.DB.Model(completion).
+		OnConflict("(user_id, module_item_id, cycle) DO UPDATE").
+		Set("status = EXCLUDED.status").
+		Set("completed_at = COALESCE(module_item_completion.completed_at, EXCLUDED.completed_at)").
+		Set("time_spent = GREATEST(module_item_completion.time_spent, EXCLUDED.time_spent)").
+		Set("score = EXCLUDED.score").
+		Set("updated_at = NOW()").
+		Returning("*").
+		Insert()
+
+	if err != nil {
+		repo.Logger.Errorf("Error completing item %d for user %d: %v", completion.ModuleItemID, completion.UserID, err)
+	}
+
+	return err
+}
+
+// RecordHeartbeat : add the seconds credited by a heartbeat to the time spent on an item and keep the playback position.
+// The record is locked while credit computes the seconds from the previous heartbeat, so concurrent heartbeats
+// are credited one after the other and never for the same time. completion holds the saved record afterwards.
+func (repo *PgModuleItemCompletionRepository) RecordHeartbeat(completion *m.ModuleItemCompletion, credit func(previous m.ModuleItemCompletion) (int, bool)) error {
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model(&m.ModuleItemCompletion{
+			UserID:       completion.UserID,
+			ModuleItemID: completion.ModuleItemID,
+			CourseID:     completion.CourseID,
+			Cycle:        completion.Cycle,
+			Status:       cf.ItemStatusInProgress,
+			StartedAt:    completion.StartedAt,
+		}).
+			OnConflict("(user_id, module_item_id, cycle) DO NOTHING").
+			Insert()
+		if err != nil {
+			return err
+		}
+
+		previous := m.ModuleItemCompletion{}
+		err = tx.Model(&previous).
+			Where("user_id = ?", completion.UserID).
+			Where("module_item_id = ?", completion.ModuleItemID).
+			Where("cycle = ?", completion.Cycle).
+			Where("deleted_at IS NULL").
+			For("UPDATE").
+			First()
+		if err != nil {
+			return err
+		}
+
+		credited, flagged := credit(previous)
+
+		saved := previous
+		saved.TimeSpent += credited
+		if flagged {
+			saved.FlaggedHeartbeats++
+		}
+		if previous.PositionChangedAt.IsZero() || previous.LastPosition != completion.LastPosition {
+			saved.PositionChangedAt = completion.LastHeartbeatAt
+		}
+		saved.LastHeartbeatAt = completion.LastHeartbeatAt
+		saved.LastPosition = completion.LastPosition
+		saved.LastViewedAt = completion.LastViewedAt
+
+		_, err = tx.Model(&saved).
+			Column("time_spent", "flagged_heartbeats", "last_heartbeat_at", "last_position", "position_changed_at", "last_viewed_at", "updated_at").
+			WherePK().
+			Update()
+		if err != nil {
+			return err
+		}
+
+		*completion = saved
+		return nil
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error recording heartbeat of item %d for user %d: %v", completion.ModuleItemID, completion.UserID, err)
+	}
+
+	return err
+}
+
+// ReopenItemsWithTx : set completed items of a user back in progress within a transaction, their activity is kept
+func (repo *PgModuleItemCompletionRepository) ReopenItemsWithTx(tx *pg.Tx, userID int, cycle int, moduleItemIDs []int) error {
+	if len(moduleItemIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Model((*m.ModuleItemCompletion)(nil)).
+		Set("status = ?", cf.ItemStatusInProgress).
+		Set("completed_at = NULL").
+		Set("updated_at = NOW()").
+		Where("user_id = ?", userID).
+		Where("cycle = ?", cycle).
+		Where("module_item_id IN (?)", pg.In(moduleItemIDs)).
+		Where("deleted_at IS NULL").
+		Update()
+
+	if err != nil {
+		repo.Logger.Errorf("Error reopening items of user %d: %v", userID, err)
+	}
+
+	return err
+}
+
+// InsertCompletionsWithTx : insert item records within a transaction, items the user already has a record of are kept
+func (repo *PgModuleItemCompletionRepository) InsertCompletionsWithTx(tx *pg.Tx, completions []m.ModuleItemCompletion) error {
+	if len(completions) == 0 {
+		return nil
+	}
+
+	_, err := tx.Model(&completions).
+		OnConflict("(user_id, module_item_id, cycle) DO NOTHING").
+		Insert()
+
+	if err != nil {
+		repo.Logger.Errorf("Error inserting item records: %v", err)
+	}
+
+	return err
+}
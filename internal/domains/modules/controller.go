@@ -6,6 +6,8 @@ import (
 	cm "orientation-training-api/internal/common"
 	rp "orientation-training-api/internal/interfaces/repository"
 	param "orientation-training-api/internal/interfaces/requestparams"
+	"orientation-training-api/internal/platform/utils"
+	"time"
 
 	valid "github.com/asaskevich/govalidator"
 
@@ -207,6 +209,15 @@ func (ctr *ModuleController) AddModule(c echo.Context) error {
 		})
 	}
 
+	if createModuleParams.NavigationMode != "" {
+		if _, ok := utils.FindStringInArray(cf.ModuleNavigationModes, createModuleParams.NavigationMode); !ok {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "navigation_mode must be sequential or free",
+			})
+		}
+	}
+
 	module, err := ctr.ModuleRepo.SaveModule(createModuleParams)
 	if err != nil {
 		ctr.Logger.Errorf("Error creating module: %v", err)
@@ -224,6 +235,127 @@ func (ctr *ModuleController) AddModule(c echo.Context) error {
 	})
 }
 
+// UpdateModuleNavigation : set whether the items of a module are unlocked in order, an empty mode follows the course
+// Params : echo.Context
+// Returns : return error
+func (ctr *ModuleController) UpdateModuleNavigation(c echo.Context) error {
+	navigationParams := new(param.UpdateModuleNavigationParams)
+
+	if err := c.Bind(navigationParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(navigationParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if navigationParams.NavigationMode != "" {
+		if _, ok := utils.FindStringInArray(cf.ModuleNavigationModes, navigationParams.NavigationMode); !ok {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "navigation_mode must be sequential or free",
+			})
+		}
+	}
+
+	if _, err := ctr.ModuleRepo.GetModuleByID(navigationParams.ModuleID); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Module not found",
+		})
+	}
+
+	if err := ctr.ModuleRepo.UpdateModuleNavigationMode(navigationParams.ModuleID, navigationParams.NavigationMode); err != nil {
+		ctr.Logger.Errorf("Error updating navigation mode of module %d: %v", navigationParams.ModuleID, err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Update Module Failed",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Module Updated Successfully",
+	})
+}
+
+// UpdateModuleRelease : set when a module opens to trainees, an empty release type opens it right away
+// Params : echo.Context
+// Returns : return error
+func (ctr *ModuleController) UpdateModuleRelease(c echo.Context) error {
+	releaseParams := new(param.UpdateModuleReleaseParams)
+
+	if err := c.Bind(releaseParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(releaseParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	switch releaseParams.ReleaseType {
+	case "":
+		releaseParams.ReleaseDate = ""
+		releaseParams.ReleaseOffsetDays = 0
+	case cf.ReleaseTypeAbsoluteDate:
+		if _, err := time.Parse(cf.FormatDateDatabase, releaseParams.ReleaseDate); err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "release_date must be formatted as YYYY-MM-DD",
+			})
+		}
+		releaseParams.ReleaseOffsetDays = 0
+	case cf.ReleaseTypeAfterEnrollment, cf.ReleaseTypeAfterJoinedDate:
+		if releaseParams.ReleaseOffsetDays < 0 {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "release_offset_days can not be negative",
+			})
+		}
+		releaseParams.ReleaseDate = ""
+	default:
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "release_type must be absolute_date, after_enrollment or after_joined_date",
+		})
+	}
+
+	if _, err := ctr.ModuleRepo.GetModuleByID(releaseParams.ModuleID); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Module not found",
+		})
+	}
+
+	err := ctr.ModuleRepo.UpdateModuleRelease(releaseParams.ModuleID, releaseParams.ReleaseType, releaseParams.ReleaseDate, releaseParams.ReleaseOffsetDays)
+	if err != nil {
+		ctr.Logger.Errorf("Error updating release rule of module %d: %v", releaseParams.ModuleID, err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Update Module Failed",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Module Updated Successfully",
+	})
+}
+
 // DeleteModule : delete module by id
 // Params : echo.Context
 // Returns : object
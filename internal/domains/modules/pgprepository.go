@@ -40,11 +40,32 @@ func (repo *PgModuleRepository) SaveModule(createModuleParams *param.CreateModul
 		Title:    createModuleParams.Title,
 		CourseID: createModuleParams.CourseID,
 		Position: createModuleParams.Position,
+
+		NavigationMode: createModuleParams.NavigationMode,
 	}
 	err := repo.DB.Insert(&module)
 	return module, err
 }
 
+// UpdateModuleNavigationMode : set the navigation mode of a module, an empty mode follows the course
+// Params : moduleID, navigationMode
+// Returns : error
+func (repo *PgModuleRepository) UpdateModuleNavigationMode(moduleID int, navigationMode string) error {
+	var mode interface{}
+	if navigationMode != "" {
+		mode = navigationMode
+	}
+
+	_, err := repo.DB.Model((*m.Module)(nil)).
+		Set("navigation_mode = ?", mode).
+		Set("updated_at = NOW()").
+		Where("id = ?", moduleID).
+		Where("deleted_at is null").
+		Update()
+
+	return err
+}
+
 func (repo *PgModuleRepository) GetModuleByID(id int) (m.Module, error) {
 	module := m.Module{}
 	err := repo.DB.Model(&module).
@@ -111,3 +132,71 @@ func (repo *PgModuleRepository) GetModuleByPositionAndCourse(courseID int, posit
 
 	return module, err
 }
+
+// UpdateModuleRelease : set the release rule of a module, an empty release type removes it
+// Params : moduleID, releaseType, releaseDate, releaseOffsetDays
+// Returns : error
+func (repo *PgModuleRepository) UpdateModuleRelease(moduleID int, releaseType string, releaseDate string, releaseOffsetDays int) error {
+	var typeValue, dateValue interface{}
+	if releaseType != "" {
+		typeValue = releaseType
+	}
+	if releaseDate != "" {
+		dateValue = releaseDate
+	}
+
+	_, err := repo.DB.Model((*m.Module)(nil)).
+		Set("release_type = ?", typeValue).
+		Set("release_date = ?", dateValue).
+		Set("release_offset_days = ?", releaseOffsetDays).
+		Set("updated_at = NOW()").
+		Where("id = ?", moduleID).
+		Where("deleted_at is null").
+		Update()
+
+	return err
+}
+
+// GetModulesWithReleaseRule : retrieve the modules that open on a schedule
+// Returns : slice of modules and error
+func (repo *PgModuleRepository) GetModulesWithReleaseRule() ([]m.Module, error) {
+	modules := []m.Module{}
+	err := repo.DB.Model(&modules).
+		Where("release_type IS NOT NULL").
+		Where("deleted_at is null").
+		Order("course_id ASC", "position ASC").
+		Select()
+
+	return modules, err
+}
+
+// GetReleaseNotifications : retrieve the users already told that a module opened, with the cycle they were told in
+// Params : moduleID
+// Returns : slice of notifications and error
+func (repo *PgModuleRepository) GetReleaseNotifications(moduleID int) ([]m.ModuleReleaseNotification, error) {
+	notifications := []m.ModuleReleaseNotification{}
+	err := repo.DB.Model(&notifications).
+		Column("user_id", "cycle").
+		Where("module_id = ?", moduleID).
+		Where("deleted_at is null").
+		Select()
+
+	return notifications, err
+}
+
+// MarkReleaseNotified : record that a user was told a module opened in a cycle of their enrollment
+// Params : moduleID, userID, cycle
+// Returns : error
+func (repo *PgModuleRepository) MarkReleaseNotified(moduleID int, userID int, cycle int) error {
+	notification := &m.ModuleReleaseNotification{
+		ModuleID: moduleID,
+		UserID:   userID,
+		Cycle:    cycle,
+	}
+
+	_, err := repo.DB.Model(notification).
+		OnConflict("(module_id, user_id, cycle) DO NOTHING").
+		Insert()
+
+	return err
+}
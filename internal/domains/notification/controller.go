@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"net/http"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationController struct {
+	cm.BaseController
+
+	NotificationRepo rp.NotificationRepository
+}
+
+func NewNotificationController(logger echo.Logger, notificationRepo rp.NotificationRepository) (ctr *NotificationController) {
+	ctr = &NotificationController{cm.BaseController{}, notificationRepo}
+	ctr.Init(logger)
+	return
+}
+
+// GetNotifications : list the notifications of the logged in user
+// Params : echo.Context
+// Returns : return error
+func (ctr *NotificationController) GetNotifications(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	getNotificationsParams := new(param.GetNotificationsParams)
+
+	if err := c.Bind(getNotificationsParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	notifications, err := ctr.NotificationRepo.GetNotificationsByUserID(userProfile.ID, getNotificationsParams.UnreadOnly)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch notifications: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch notifications",
+		})
+	}
+
+	unreadCount, err := ctr.NotificationRepo.CountUnreadNotifications(userProfile.ID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to count unread notifications: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch notifications",
+		})
+	}
+
+	if notifications == nil {
+		notifications = []m.Notification{}
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data: map[string]interface{}{
+			"notifications": notifications,
+			"unread_count":  unreadCount,
+		},
+	})
+}
+
+// MarkNotificationsRead : mark notifications of the logged in user as read
+// Params : echo.Context
+// Returns : return error
+func (ctr *NotificationController) MarkNotificationsRead(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	markNotificationsReadParams := new(param.MarkNotificationsReadParams)
+
+	if err := c.Bind(markNotificationsReadParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if err := ctr.NotificationRepo.MarkNotificationsRead(userProfile.ID, markNotificationsReadParams.NotificationIDs); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to update notifications",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Notifications marked as read",
+	})
+}
@@ -0,0 +1,79 @@
+package notification
+
+import (
+	cm "orientation-training-api/internal/common"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type PgNotificationRepository struct {
+	cm.AppRepository
+}
+
+func NewPgNotificationRepository(logger echo.Logger) (repo *PgNotificationRepository) {
+	repo = &PgNotificationRepository{}
+	repo.Init(logger)
+	return
+}
+
+// CreateNotification : store a notification for a user
+func (repo *PgNotificationRepository) CreateNotification(notification *m.Notification) error {
+	_, err := repo.DB.Model(notification).Insert()
+	if err != nil {
+		repo.Logger.Errorf("Error creating notification for user %d: %v", notification.UserID, err)
+	}
+
+	return err
+}
+
+// GetNotificationsByUserID : get the notifications of a user, newest first
+func (repo *PgNotificationRepository) GetNotificationsByUserID(userID int, unreadOnly bool) ([]m.Notification, error) {
+	var notifications []m.Notification
+
+	query := repo.DB.Model(&notifications).
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL")
+
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	err := query.Order("created_at DESC").Select()
+	if err != nil {
+		repo.Logger.Errorf("Error fetching notifications for user %d: %v", userID, err)
+	}
+
+	return notifications, err
+}
+
+// CountUnreadNotifications : count the notifications a user has not read yet
+func (repo *PgNotificationRepository) CountUnreadNotifications(userID int) (int, error) {
+	return repo.DB.Model((*m.Notification)(nil)).
+		Where("user_id = ?", userID).
+		Where("read_at IS NULL").
+		Where("deleted_at IS NULL").
+		Count()
+}
+
+// MarkNotificationsRead : mark notifications of a user as read, all of them when no ids are given
+func (repo *PgNotificationRepository) MarkNotificationsRead(userID int, notificationIDs []int) error {
+	query := repo.DB.Model((*m.Notification)(nil)).
+		Set("read_at = NOW()").
+		Set("updated_at = NOW()").
+		Where("user_id = ?", userID).
+		Where("read_at IS NULL").
+		Where("deleted_at IS NULL")
+
+	if len(notificationIDs) > 0 {
+		query = query.Where("id IN (?)", pg.In(notificationIDs))
+	}
+
+	if _, err := query.Update(); err != nil {
+		repo.Logger.Errorf("Error marking notifications as read for user %d: %v", userID, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package pathassignmentrule
+
+import (
+	"net/http"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/enrollment"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type PathAssignmentRuleController struct {
+	cm.BaseController
+
+	PathRuleRepo     rp.PathAssignmentRuleRepository
+	TemplatePathRepo rp.TemplatePathRepository
+	UserRepo         rp.UserRepository
+}
+
+func NewPathAssignmentRuleController(logger echo.Logger, pathRuleRepo rp.PathAssignmentRuleRepository, templatePathRepo rp.TemplatePathRepository, userRepo rp.UserRepository) (ctr *PathAssignmentRuleController) {
+	ctr = &PathAssignmentRuleController{cm.BaseController{}, pathRuleRepo, templatePathRepo, userRepo}
+	ctr.Init(logger)
+	return
+}
+
+// GetRules : get every path assignment rule
+// Params : echo.Context
+// Returns : return error
+func (ctr *PathAssignmentRuleController) GetRules(c echo.Context) error {
+	rules, err := ctr.PathRuleRepo.GetRules()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	if rules == nil {
+		rules = []m.PathAssignmentRule{}
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data:    rules,
+	})
+}
+
+// SaveRule : create or update a path assignment rule
+// Params : echo.Context
+// Returns : return error
+func (ctr *PathAssignmentRuleController) SaveRule(c echo.Context) error {
+	saveRuleParams := new(param.SavePathAssignmentRuleParams)
+
+	if err := c.Bind(saveRuleParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(saveRuleParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	saveRuleParams.Department = strings.TrimSpace(saveRuleParams.Department)
+	if saveRuleParams.Department == "" && saveRuleParams.RoleID == 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "A rule needs a department or a role",
+		})
+	}
+
+	if saveRuleParams.DueInDays < 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "due_in_days can not be negative",
+		})
+	}
+
+	if _, err := ctr.TemplatePathRepo.GetTemplatePathByID(saveRuleParams.TemplatePathID); err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Template path not found",
+			})
+		}
+
+		ctr.Logger.Errorf("Failed to fetch template path: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	rule := &m.PathAssignmentRule{}
+	if saveRuleParams.ID != 0 {
+		existingRule, err := ctr.PathRuleRepo.GetRuleByID(saveRuleParams.ID)
+		if err != nil {
+			if err.Error() == pg.ErrNoRows.Error() {
+				return c.JSON(http.StatusOK, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: "Rule not found",
+				})
+			}
+
+			ctr.Logger.Errorf("Failed to fetch path assignment rule: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+
+		rule = &existingRule
+	} else {
+		userProfile := c.Get("user_profile").(m.User)
+		rule.CreatedBy = userProfile.ID
+		rule.Active = true
+	}
+
+	rule.Name = saveRuleParams.Name
+	rule.Department = saveRuleParams.Department
+	rule.RoleID = saveRuleParams.RoleID
+	rule.TemplatePathID = saveRuleParams.TemplatePathID
+	rule.DueInDays = saveRuleParams.DueInDays
+	if saveRuleParams.Active != nil {
+		rule.Active = *saveRuleParams.Active
+	}
+
+	if err := ctr.PathRuleRepo.SaveRule(rule); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save path assignment rule",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Path assignment rule saved",
+		Data:    rule,
+	})
+}
+
+// DeleteRule : delete a path assignment rule, paths already assigned are kept
+// Params : echo.Context
+// Returns : return error
+func (ctr *PathAssignmentRuleController) DeleteRule(c echo.Context) error {
+	ruleIDParams := new(param.PathAssignmentRuleIDParams)
+
+	if err := c.Bind(ruleIDParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(ruleIDParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if err := ctr.PathRuleRepo.DeleteRule(ruleIDParams.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to delete path assignment rule",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Path assignment rule deleted",
+	})
+}
+
+// PreviewRule : list the users a rule matches and the courses of the path they would be assigned
+// Params : echo.Context
+// Returns : return error
+func (ctr *PathAssignmentRuleController) PreviewRule(c echo.Context) error {
+	previewParams := new(param.PreviewPathAssignmentRuleParams)
+
+	if err := c.Bind(previewParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	rule := m.PathAssignmentRule{
+		Department:     strings.TrimSpace(previewParams.Department),
+		RoleID:         previewParams.RoleID,
+		TemplatePathID: previewParams.TemplatePathID,
+	}
+
+	if previewParams.ID != 0 {
+		savedRule, err := ctr.PathRuleRepo.GetRuleByID(previewParams.ID)
+		if err != nil {
+			if err.Error() == pg.ErrNoRows.Error() {
+				return c.JSON(http.StatusOK, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: "Rule not found",
+				})
+			}
+
+			ctr.Logger.Errorf("Failed to fetch path assignment rule: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+
+		rule = savedRule
+	}
+
+	if rule.Department == "" && rule.RoleID == 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "A rule needs a department or a role",
+		})
+	}
+
+	templatePath, err := ctr.TemplatePathRepo.GetTemplatePathByID(rule.TemplatePathID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Template path not found",
+			})
+		}
+
+		ctr.Logger.Errorf("Failed to fetch template path: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	users, err := ctr.UserRepo.GetAllUsersExceptRole(cf.AdminRoleID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	matchedUsers := []map[string]interface{}{}
+	for _, user := range users {
+		if !enrollment.RuleMatchesUser(rule, user) {
+			continue
+		}
+
+		progresses, err := ctr.UserRepo.GetUserProgressByUserID(user.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+
+		enrolledCourses := make(map[int]bool)
+		for _, progress := range progresses {
+			enrolledCourses[progress.CourseID] = true
+		}
+
+		pendingCourses := []int{}
+		for _, courseID := range enrollment.PathRequiredCourseIDs(templatePath) {
+			if !enrolledCourses[courseID] {
+				pendingCourses = append(pendingCourses, courseID)
+			}
+		}
+
+		matchedUsers = append(matchedUsers, map[string]interface{}{
+			"user_id":         user.ID,
+			"fullname":        strings.TrimSpace(user.UserProfile.FirstName + " " + user.UserProfile.LastName),
+			"email":           user.Email,
+			"department":      user.UserProfile.Department,
+			"role_id":         user.RoleID,
+			"pending_courses": pendingCourses,
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data: map[string]interface{}{
+			"template_path_id": templatePath.ID,
+			"template_path":    templatePath.Name,
+			"total_users":      len(matchedUsers),
+			"users":            matchedUsers,
+		},
+	})
+}
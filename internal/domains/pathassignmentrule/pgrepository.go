@@ -0,0 +1,101 @@
+package pathassignmentrule
+
+import (
+	cm "orientation-training-api/internal/common"
+	m "orientation-training-api/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PgPathAssignmentRuleRepository struct {
+	cm.AppRepository
+}
+
+func NewPgPathAssignmentRuleRepository(logger echo.Logger) (repo *PgPathAssignmentRuleRepository) {
+	repo = &PgPathAssignmentRuleRepository{}
+	repo.Init(logger)
+	return
+}
+
+// GetRules : get every path assignment rule with its template path
+func (repo *PgPathAssignmentRuleRepository) GetRules() ([]m.PathAssignmentRule, error) {
+	var rules []m.PathAssignmentRule
+
+	err := repo.DB.Model(&rules).
+		Relation("TemplatePath").
+		Where("path_assignment_rule.deleted_at IS NULL").
+		Order("path_assignment_rule.id ASC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching path assignment rules: %v", err)
+	}
+
+	return rules, err
+}
+
+// GetActiveRules : get the enabled rules whose template path still exists
+func (repo *PgPathAssignmentRuleRepository) GetActiveRules() ([]m.PathAssignmentRule, error) {
+	var rules []m.PathAssignmentRule
+
+	err := repo.DB.Model(&rules).
+		Join("JOIN template_paths AS tp ON tp.id = path_assignment_rule.template_path_id AND tp.deleted_at IS NULL").
+		Where("path_assignment_rule.active = TRUE").
+		Where("path_assignment_rule.deleted_at IS NULL").
+		Order("path_assignment_rule.id ASC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching active path assignment rules: %v", err)
+	}
+
+	return rules, err
+}
+
+// GetRuleByID : get a path assignment rule
+func (repo *PgPathAssignmentRuleRepository) GetRuleByID(ruleID int) (m.PathAssignmentRule, error) {
+	rule := m.PathAssignmentRule{}
+
+	err := repo.DB.Model(&rule).
+		Where("id = ?", ruleID).
+		Where("deleted_at IS NULL").
+		First()
+
+	return rule, err
+}
+
+// SaveRule : create a rule or update an existing one
+func (repo *PgPathAssignmentRuleRepository) SaveRule(rule *m.PathAssignmentRule) error {
+	var err error
+
+	if rule.ID == 0 {
+		_, err = repo.DB.Model(rule).Insert()
+	} else {
+		_, err = repo.DB.Model(rule).
+			Column("name", "department", "role_id", "template_path_id", "due_in_days", "active", "updated_at").
+			Where("id = ?", rule.ID).
+			Where("deleted_at IS NULL").
+			Update()
+	}
+
+	if err != nil {
+		repo.Logger.Errorf("Error saving path assignment rule: %v", err)
+	}
+
+	return err
+}
+
+// DeleteRule : soft delete a path assignment rule
+func (repo *PgPathAssignmentRuleRepository) DeleteRule(ruleID int) error {
+	_, err := repo.DB.Model((*m.PathAssignmentRule)(nil)).
+		Set("deleted_at = NOW()").
+		Where("id = ?", ruleID).
+		Where("deleted_at IS NULL").
+		Update()
+
+	if err != nil {
+		repo.Logger.Errorf("Error deleting path assignment rule %d: %v", ruleID, err)
+	}
+
+	return err
+}
@@ -0,0 +1,263 @@
+package progression
+
+import (
+	"fmt"
+
+	cf "orientation-training-api/configs"
+	"orientation-training-api/internal/domains/quizzes"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+
+	"github.com/go-pg/pg/v9"
+)
+
+// NewProgressHistory returns the snapshot of the current cycle of an enrollment with its latest quiz results,
+// it is kept before the enrollment is reset, reopened, unassigned or recertified
+func (service *Service) NewProgressHistory(userProgress m.UserProgress, reason string, actionBy int, note string) *m.UserProgressHistory {
+	history := &m.UserProgressHistory{
+		UserID:             userProgress.UserID,
+		CourseID:           userProgress.CourseID,
+		Cycle:              progressCycle(userProgress),
+		AssignedAt:         userProgress.AssignedAt,
+		DueDate:            userProgress.DueDate,
+		CompletedDate:      userProgress.CompletedDate,
+		CompletedAt:        userProgress.CompletedAt,
+		PerformanceRating:  userProgress.PerformanceRating,
+		PerformanceComment: userProgress.PerformanceComment,
+		ReviewedBy:         userProgress.ReviewedBy,
+		QuizResults:        service.QuizResults(userProgress.UserID, userProgress.CourseID),
+		Reason:             reason,
+		ActionBy:           actionBy,
+		Note:               note,
+	}
+
+	for _, quizResult := range history.QuizResults {
+		history.QuizScore += quizResult.Score
+		history.QuizMaxScore += quizResult.TotalScore
+	}
+
+	return history
+}
+
+// QuizResults returns the score that counts for every quiz of a course under its attempt policy
+func (service *Service) QuizResults(userID int, courseID int) []m.QuizResultSnapshot {
+	quizResults := []m.QuizResultSnapshot{}
+
+	quizIDs, err := service.CourseQuizIDs(courseID)
+	if err != nil {
+		service.Logger.Errorf("Failed to fetch quizzes of course %d: %v", courseID, err)
+		return quizResults
+	}
+
+	for _, quizID := range quizIDs {
+		quiz, err := service.QuizRepo.GetQuizByID(quizID)
+		if err != nil {
+			continue
+		}
+
+		quizResult, err := quizzes.UserAttemptResult(service.QuizRepo, userID, quiz)
+		if err != nil || quizResult.AttemptsUsed == 0 {
+			continue
+		}
+
+		quizResults = append(quizResults, m.QuizResultSnapshot{
+			QuizID:     quizID,
+			Attempt:    quizResult.LatestAttempt,
+			Score:      quizResult.KeptScore,
+			TotalScore: quiz.TotalScore,
+		})
+	}
+
+	return quizResults
+}
+
+// CourseQuizIDs returns the quizzes used by the items of a course
+func (service *Service) CourseQuizIDs(courseID int) ([]int, error) {
+	quizIDs := []int{}
+
+	courseItems, err := service.GetCourseItems(courseID)
+	if err != nil {
+		return quizIDs, err
+	}
+
+	for _, courseItem := range courseItems {
+		if courseItem.Item.ItemType == "quiz" && courseItem.Item.QuizID > 0 {
+			quizIDs = append(quizIDs, courseItem.Item.QuizID)
+		}
+	}
+
+	return quizIDs, nil
+}
+
+// ArchiveQuizAttempts archives the attempts of a user on the quizzes of a course so that they are taken again
+func (service *Service) ArchiveQuizAttempts(userID int, courseID int) error {
+	quizIDs, err := service.CourseQuizIDs(courseID)
+	if err != nil {
+		return err
+	}
+
+	return service.QuizRepo.ArchiveQuizSubmissions(userID, quizIDs)
+}
+
+// ArchiveQuizAttemptsWithTx archives the attempts of a user on the quizzes of a course within a transaction
+func (service *Service) ArchiveQuizAttemptsWithTx(tx *pg.Tx, userID int, courseID int) error {
+	quizIDs, err := service.CourseQuizIDs(courseID)
+	if err != nil {
+		return err
+	}
+
+	return service.QuizRepo.ArchiveQuizSubmissionsWithTx(tx, userID, quizIDs)
+}
+
+// ResetScormRuntimesWithTx starts the SCORM items of a course over for a user within a transaction,
+// so that their completion is verified again in a new cycle
+func (service *Service) ResetScormRuntimesWithTx(tx *pg.Tx, userID int, courseID int) error {
+	courseItems, err := service.GetCourseItems(courseID)
+	if err != nil {
+		return err
+	}
+
+	scormItemIDs := []int{}
+	for _, courseItem := range courseItems {
+		if courseItem.Item.ItemType == "scorm" {
+			scormItemIDs = append(scormItemIDs, courseItem.Item.ID)
+		}
+	}
+
+	return service.ScormRepo.ResetScormRuntimesWithTx(tx, userID, scormItemIDs)
+}
+
+// ReopenProgress reopens a completed enrollment from an item, the item and the ones after it in the course
+// are no longer completed and the cursor moves back to the first item not completed.
+// Without an item the first item not completed is kept, or the last item when every item is completed.
+// The items and the enrollment are reopened in one transaction with the history of the completed cycle.
+// It returns the reason the progress could not be reopened, empty when it was.
+func (service *Service) ReopenProgress(userProgress *m.UserProgress, fromItemID int, history *m.UserProgressHistory) (string, error) {
+	courseItems, err := service.GetCourseItems(userProgress.CourseID)
+	if err != nil {
+		return "", err
+	}
+	if len(courseItems) == 0 {
+		return "The course has no module items", nil
+	}
+
+	completions, err := service.GetItemCompletions(*userProgress)
+	if err != nil {
+		return "", err
+	}
+
+	fromIndex := CurrentItemIndex(courseItems, completions, 0)
+	if fromItemID > 0 {
+		fromIndex = -1
+		for i, courseItem := range courseItems {
+			if courseItem.Item.ID == fromItemID {
+				fromIndex = i
+				break
+			}
+		}
+		if fromIndex < 0 {
+			return "Module item not found in this course", nil
+		}
+	} else if fromIndex == len(courseItems) {
+		fromIndex = len(courseItems) - 1
+	}
+
+	reopenedItemIDs := []int{}
+	for _, courseItem := range courseItems[fromIndex:] {
+		if completions[courseItem.Item.ID].Status == cf.ItemStatusCompleted {
+			reopenedItemIDs = append(reopenedItemIDs, courseItem.Item.ID)
+			delete(completions, courseItem.Item.ID)
+		}
+	}
+
+	reopened := *userProgress
+	currentItem := courseItems[CurrentItemIndex(courseItems, completions, 0)]
+	reopened.ModulePosition = currentItem.ModulePosition
+	reopened.ModuleItemPosition = currentItem.Item.Position
+	reopened.ItemStartedAt = utils.TimeNowUTC()
+	reopened.Completed = false
+	reopened.CompletedDate = ""
+
+	reopenItems := func(tx *pg.Tx) error {
+		return service.CompletionRepo.ReopenItemsWithTx(tx, userProgress.UserID, progressCycle(*userProgress), reopenedItemIDs)
+	}
+	if err := service.UserProgressRepo.ReopenUserProgress(&reopened, history, reopenItems); err != nil {
+		return "", err
+	}
+
+	*userProgress = reopened
+	return "", nil
+}
+
+// TransferProgress replaces an enrollment by the enrollment of another course in one transaction
+// with the history of the previous course. The records of the items found in both courses,
+// the same quiz or the same resource, carry over to the new course and its cursor moves
+// to the first item not completed, the new course is completed when every item was.
+func (service *Service) TransferProgress(userProgress m.UserProgress, newProgress *m.UserProgress, history *m.UserProgressHistory) error {
+	previousItems, err := service.GetCourseItems(userProgress.CourseID)
+	if err != nil {
+		return err
+	}
+
+	previousCompletions, err := service.GetItemCompletions(userProgress)
+	if err != nil {
+		return err
+	}
+
+	courseItems, err := service.GetCourseItems(newProgress.CourseID)
+	if err != nil {
+		return err
+	}
+
+	completionsByKey := make(map[string]m.ModuleItemCompletion)
+	for _, courseItem := range previousItems {
+		if completion, ok := previousCompletions[courseItem.Item.ID]; ok {
+			completionsByKey[transferKey(courseItem.Item)] = completion
+		}
+	}
+
+	completions := make(map[int]m.ModuleItemCompletion)
+	carriedOver := []m.ModuleItemCompletion{}
+	for _, courseItem := range courseItems {
+		previous, ok := completionsByKey[transferKey(courseItem.Item)]
+		if !ok {
+			continue
+		}
+
+		completion := previous
+		completion.ID = 0
+		completion.ModuleItemID = courseItem.Item.ID
+		completion.CourseID = newProgress.CourseID
+		completion.Cycle = progressCycle(*newProgress)
+		completions[courseItem.Item.ID] = completion
+		carriedOver = append(carriedOver, completion)
+	}
+
+	now := utils.TimeNowUTC()
+	newProgress.ItemStartedAt = now
+	if currentIndex := CurrentItemIndex(courseItems, completions, 0); currentIndex < len(courseItems) {
+		newProgress.ModulePosition = courseItems[currentIndex].ModulePosition
+		newProgress.ModuleItemPosition = courseItems[currentIndex].Item.Position
+	} else if len(courseItems) > 0 {
+		lastItem := courseItems[len(courseItems)-1]
+		newProgress.ModulePosition = lastItem.ModulePosition
+		newProgress.ModuleItemPosition = lastItem.Item.Position
+		newProgress.Completed = true
+		newProgress.CompletedDate = now.Format(cf.FormatDate)
+	}
+
+	carryOver := func(tx *pg.Tx) error {
+		return service.CompletionRepo.InsertCompletionsWithTx(tx, carriedOver)
+	}
+
+	return service.UserProgressRepo.TransferUserProgress(&userProgress, history, newProgress, carryOver)
+}
+
+// transferKey identifies an item across courses by its quiz or its resource
+func transferKey(item m.ModuleItem) string {
+	if item.ItemType == "quiz" {
+		return fmt.Sprintf("quiz:%d", item.QuizID)
+	}
+
+	return item.ItemType + ":" + item.Resource
+}
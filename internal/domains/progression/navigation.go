@@ -0,0 +1,56 @@
+package progression
+
+import (
+	cf "orientation-training-api/configs"
+	m "orientation-training-api/internal/models"
+)
+
+// ModuleNavigationMode returns how the items of a module are unlocked, sequential or free.
+// A module without its own mode follows its course.
+func ModuleNavigationMode(courseMode string, moduleMode string) string {
+	if moduleMode != "" {
+		return moduleMode
+	}
+
+	if courseMode == cf.NavigationFree {
+		return cf.NavigationFree
+	}
+
+	return cf.NavigationSequential
+}
+
+// ItemUnlocks returns whether every course item is unlocked under the navigation modes of the course and its modules.
+// In a sequential course a module opens once every item of the previous modules is completed,
+// other courses open every module. Inside an open module, items of a sequential module unlock
+// up to the first item not completed while items of a free module are all unlocked.
+// Modules not released yet stay closed whatever the navigation mode.
+func ItemUnlocks(courseMode string, courseItems []CourseItem, completions map[int]m.ModuleItemCompletion, unreleasedModules map[int]string) []bool {
+	unlocks := make([]bool, len(courseItems))
+	previousModulesCompleted := true
+
+	for start := 0; start < len(courseItems); {
+		end := start
+		for end < len(courseItems) && courseItems[end].ModuleID == courseItems[start].ModuleID {
+			end++
+		}
+
+		_, unreleased := unreleasedModules[courseItems[start].ModuleID]
+		moduleOpen := !unreleased && (courseMode != cf.NavigationSequential || previousModulesCompleted)
+		freeItems := ModuleNavigationMode(courseMode, courseItems[start].ModuleNavigationMode) == cf.NavigationFree
+
+		reachedIncomplete := false
+		for i := start; i < end; i++ {
+			unlocks[i] = moduleOpen && (freeItems || !reachedIncomplete)
+			if completions[courseItems[i].Item.ID].Status != cf.ItemStatusCompleted {
+				reachedIncomplete = true
+			}
+		}
+
+		if reachedIncomplete {
+			previousModulesCompleted = false
+		}
+		start = end
+	}
+
+	return unlocks
+}
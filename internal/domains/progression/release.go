@@ -0,0 +1,63 @@
+package progression
+
+import (
+	cf "orientation-training-api/configs"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"time"
+)
+
+// ModuleReleaseDate returns the date (YYYY-MM-DD) a module opens for a trainee, empty when the module has no release rule.
+// Rules counted from the company joined date fall back to the enrollment when the joined date is unknown.
+func ModuleReleaseDate(module m.Module, assignedAt time.Time, joinedDate string) string {
+	switch module.ReleaseType {
+	case cf.ReleaseTypeAbsoluteDate:
+		return utils.FormatDueDate(module.ReleaseDate)
+	case cf.ReleaseTypeAfterJoinedDate:
+		if startDate, err := time.Parse(cf.FormatDateDatabase, utils.FormatDueDate(joinedDate)); err == nil {
+			return startDate.AddDate(0, 0, module.ReleaseOffsetDays).Format(cf.FormatDateDatabase)
+		}
+		fallthrough
+	case cf.ReleaseTypeAfterEnrollment:
+		return assignedAt.AddDate(0, 0, module.ReleaseOffsetDays).Format(cf.FormatDateDatabase)
+	}
+
+	return ""
+}
+
+// IsReleased checks a release date against the current date
+func IsReleased(releaseDate string) bool {
+	return releaseDate == "" || releaseDate <= utils.TimeNowUTC().Format(cf.FormatDateDatabase)
+}
+
+// unreleasedModules returns the release date of every module of a course not opened yet for a trainee
+func (service *Service) unreleasedModules(userProgress m.UserProgress) (map[int]string, error) {
+	unreleased := make(map[int]string)
+
+	modules, err := service.ModuleRepo.GetModulesByCourseID(userProgress.CourseID)
+	if err != nil {
+		return unreleased, err
+	}
+
+	joinedDate := ""
+	for _, module := range modules {
+		if module.ReleaseType == "" {
+			continue
+		}
+
+		if module.ReleaseType == cf.ReleaseTypeAfterJoinedDate && joinedDate == "" {
+			user, err := service.UserRepo.GetUserProfile(userProgress.UserID)
+			if err != nil {
+				return unreleased, err
+			}
+			joinedDate = user.UserProfile.CompanyJoinedDate
+		}
+
+		releaseDate := ModuleReleaseDate(module, userProgress.AssignedAt, joinedDate)
+		if !IsReleased(releaseDate) {
+			unreleased[module.ID] = releaseDate
+		}
+	}
+
+	return unreleased, nil
+}
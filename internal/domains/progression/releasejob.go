@@ -0,0 +1,118 @@
+package progression
+
+import (
+	"fmt"
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	m "orientation-training-api/internal/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReleaseJob notifies trainees when a module released on a schedule opens for them
+type ReleaseJob struct {
+	Logger           echo.Logger
+	ModuleRepo       rp.ModuleRepository
+	CourseRepo       rp.CourseRepository
+	UserProgressRepo rp.UserProgressRepository
+	UserRepo         rp.UserRepository
+	NotificationRepo rp.NotificationRepository
+}
+
+func NewReleaseJob(logger echo.Logger, moduleRepo rp.ModuleRepository, courseRepo rp.CourseRepository, userProgressRepo rp.UserProgressRepository, userRepo rp.UserRepository, notificationRepo rp.NotificationRepository) *ReleaseJob {
+	return &ReleaseJob{logger, moduleRepo, courseRepo, userProgressRepo, userRepo, notificationRepo}
+}
+
+// Run notifies every enrolled trainee of the modules released since the last run, once per cycle of the enrollment.
+// Modules already open when the trainee enrolled are recorded without a notification
+func (job *ReleaseJob) Run() error {
+	modules, err := job.ModuleRepo.GetModulesWithReleaseRule()
+	if err != nil {
+		return err
+	}
+
+	joinedDates := make(map[int]string)
+	notifiedCount := 0
+	for _, module := range modules {
+		notifications, err := job.ModuleRepo.GetReleaseNotifications(module.ID)
+		if err != nil {
+			job.Logger.Errorf("Failed to fetch release notifications of module %d: %v", module.ID, err)
+			continue
+		}
+
+		notified := make(map[[2]int]bool)
+		for _, notification := range notifications {
+			notified[[2]int{notification.UserID, notification.Cycle}] = true
+		}
+
+		userProgressList, err := job.UserProgressRepo.GetUserProgressByCourseID(module.CourseID)
+		if err != nil {
+			job.Logger.Errorf("Failed to fetch enrollments of course %d: %v", module.CourseID, err)
+			continue
+		}
+
+		for _, userProgress := range userProgressList {
+			if userProgress.Completed || notified[[2]int{userProgress.UserID, progressCycle(userProgress)}] {
+				continue
+			}
+
+			if module.ReleaseType == cf.ReleaseTypeAfterJoinedDate {
+				if _, ok := joinedDates[userProgress.UserID]; !ok {
+					joinedDates[userProgress.UserID] = job.joinedDate(userProgress.UserID)
+				}
+			}
+
+			releaseDate := ModuleReleaseDate(module, userProgress.AssignedAt, joinedDates[userProgress.UserID])
+			if !IsReleased(releaseDate) {
+				continue
+			}
+
+			if releaseDate > userProgress.AssignedAt.Format(cf.FormatDateDatabase) {
+				if err := job.notify(module, userProgress); err != nil {
+					job.Logger.Errorf("Failed to notify release of module %d to user %d: %v", module.ID, userProgress.UserID, err)
+					continue
+				}
+				notifiedCount++
+			}
+
+			if err := job.ModuleRepo.MarkReleaseNotified(module.ID, userProgress.UserID, progressCycle(userProgress)); err != nil {
+				job.Logger.Errorf("Failed to record release of module %d for user %d: %v", module.ID, userProgress.UserID, err)
+			}
+		}
+	}
+
+	if notifiedCount > 0 {
+		job.Logger.Infof("Notified %d module releases", notifiedCount)
+	}
+
+	return nil
+}
+
+func (job *ReleaseJob) joinedDate(userID int) string {
+	user, err := job.UserRepo.GetUserProfile(userID)
+	if err != nil {
+		job.Logger.Errorf("Failed to fetch user %d for module release: %v", userID, err)
+		return ""
+	}
+
+	return user.UserProfile.CompanyJoinedDate
+}
+
+func (job *ReleaseJob) notify(module m.Module, userProgress m.UserProgress) error {
+	courseTitle := fmt.Sprintf("Course %d", module.CourseID)
+	if course, err := job.CourseRepo.GetCourseByID(module.CourseID); err == nil {
+		courseTitle = course.Title
+	}
+
+	return job.NotificationRepo.CreateNotification(&m.Notification{
+		UserID:  userProgress.UserID,
+		Type:    cf.NotificationTypeModuleReleased,
+		Title:   "New content available",
+		Message: fmt.Sprintf("The module \"%s\" of the course \"%s\" is now open.", module.Title, courseTitle),
+		Data: map[string]interface{}{
+			"user_id":   userProgress.UserID,
+			"course_id": module.CourseID,
+			"module_id": module.ID,
+		},
+	})
+}
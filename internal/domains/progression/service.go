@@ -0,0 +1,601 @@
+package progression
+
+import (
+	"fmt"
+	"math"
+	cf "orientation-training-api/configs"
+	"orientation-training-api/internal/domains/quizzes"
+	rp "orientation-training-api/internal/interfaces/repository"
+	"orientation-training-api/internal/interfaces/response"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"time"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+// Service tracks the progress of a user item by item, an item is completed only once the server
+// has verified it from quiz submissions, SCORM runtime data or the time spent on it.
+// The module and item positions of UserProgress follow the first item not completed.
+type Service struct {
+	Logger           echo.Logger
+	UserProgressRepo rp.UserProgressRepository
+	ModuleRepo       rp.ModuleRepository
+	ModuleItemRepo   rp.ModuleItemRepository
+	QuizRepo         rp.QuizRepository
+	ScormRepo        rp.ScormRepository
+	CompletionRepo   rp.ModuleItemCompletionRepository
+	CourseRepo       rp.CourseRepository
+	UserRepo         rp.UserRepository
+}
+
+// CourseItem is a module item with the position and the navigation mode of its module in the course
+type CourseItem struct {
+	ModuleID             int
+	ModulePosition       int
+	ModuleNavigationMode string
+	Item                 m.ModuleItem
+}
+
+// ItemState is the state of a module item for a user, UnlockDate is set while its module is not released
+type ItemState struct {
+	Unlocked   bool
+	Completed  bool
+	UnlockDate string
+	Completion m.ModuleItemCompletion
+}
+
+func NewProgressionService(logger echo.Logger, userProgressRepo rp.UserProgressRepository, moduleRepo rp.ModuleRepository, moduleItemRepo rp.ModuleItemRepository, quizRepo rp.QuizRepository, scormRepo rp.ScormRepository, completionRepo rp.ModuleItemCompletionRepository, courseRepo rp.CourseRepository, userRepo rp.UserRepository) *Service {
+	return &Service{logger, userProgressRepo, moduleRepo, moduleItemRepo, quizRepo, scormRepo, completionRepo, courseRepo, userRepo}
+}
+
+// GetCourseItems returns the items of a course in learning order
+func (service *Service) GetCourseItems(courseID int) ([]CourseItem, error) {
+	courseItems := []CourseItem{}
+
+	modules, err := service.ModuleRepo.GetModulesByCourseID(courseID)
+	if err != nil || len(modules) == 0 {
+		return courseItems, err
+	}
+
+	moduleIDs := make([]int, len(modules))
+	for i, module := range modules {
+		moduleIDs[i] = module.ID
+	}
+
+	moduleItems, err := service.ModuleItemRepo.GetModuleItemsByModuleIDs(moduleIDs)
+	if err != nil {
+		return courseItems, err
+	}
+
+	for _, module := range modules {
+		for _, item := range moduleItems {
+			if item.ModuleID == module.ID {
+				courseItems = append(courseItems, CourseItem{
+					ModuleID:             module.ID,
+					ModulePosition:       module.Position,
+					ModuleNavigationMode: module.NavigationMode,
+					Item:                 item,
+				})
+			}
+		}
+	}
+
+	return courseItems, nil
+}
+
+// GetItemCompletions returns the item records of the current cycle of a progress by module item
+func (service *Service) GetItemCompletions(userProgress m.UserProgress) (map[int]m.ModuleItemCompletion, error) {
+	completionsByItem := make(map[int]m.ModuleItemCompletion)
+
+	completions, err := service.CompletionRepo.GetCompletionsByCourse(userProgress.UserID, userProgress.CourseID, progressCycle(userProgress))
+	if err != nil {
+		return completionsByItem, err
+	}
+
+	for _, completion := range completions {
+		completionsByItem[completion.ModuleItemID] = completion
+	}
+
+	return completionsByItem, nil
+}
+
+// CurrentItemIndex returns the index of the first item not completed, from index start
+func CurrentItemIndex(courseItems []CourseItem, completions map[int]m.ModuleItemCompletion, start int) int {
+	for i := start; i < len(courseItems); i++ {
+		if completions[courseItems[i].Item.ID].Status != cf.ItemStatusCompleted {
+			return i
+		}
+	}
+
+	return len(courseItems)
+}
+
+// GetItemStates returns the state of every item of a course, items are unlocked following
+// the navigation modes of the course and its modules once their module is released
+func (service *Service) GetItemStates(userProgress m.UserProgress, courseItems []CourseItem) (map[int]ItemState, error) {
+	itemStates := make(map[int]ItemState)
+
+	completions, err := service.GetItemCompletions(userProgress)
+	if err != nil {
+		return itemStates, err
+	}
+
+	unlocks, unreleasedModules, err := service.itemUnlocks(userProgress, courseItems, completions)
+	if err != nil {
+		return itemStates, err
+	}
+
+	for i, courseItem := range courseItems {
+		completion := completions[courseItem.Item.ID]
+		itemStates[courseItem.Item.ID] = ItemState{
+			Unlocked:   userProgress.Completed || unlocks[i],
+			UnlockDate: unreleasedModules[courseItem.ModuleID],
+			Completed:  completion.Status == cf.ItemStatusCompleted,
+			Completion: completion,
+		}
+	}
+
+	return itemStates, nil
+}
+
+// CourseCompletionRate returns the share of completed items of a course in percent,
+// 100 is kept for completed courses
+func (service *Service) CourseCompletionRate(userProgress m.UserProgress) (int, error) {
+	if userProgress.Completed {
+		return 100, nil
+	}
+
+	courseItems, err := service.GetCourseItems(userProgress.CourseID)
+	if err != nil || len(courseItems) == 0 {
+		return 0, err
+	}
+
+	completions, err := service.GetItemCompletions(userProgress)
+	if err != nil {
+		return 0, err
+	}
+
+	completedItems := 0
+	for _, courseItem := range courseItems {
+		if completions[courseItem.Item.ID].Status == cf.ItemStatusCompleted {
+			completedItems++
+		}
+	}
+
+	percentage := completedItems * 100 / len(courseItems)
+	if percentage >= 100 {
+		percentage = 99
+	}
+
+	return percentage, nil
+}
+
+// VerifyItemCompletion returns why the user has not completed an item yet, an empty reason means completed,
+// along with the score reached on the item. timeSpent is the time tracked by heartbeats on a lecture.
+func (service *Service) VerifyItemCompletion(userID int, item m.ModuleItem, timeSpent int) (string, float64, error) {
+	switch item.ItemType {
+	case "quiz":
+		if item.QuizID == 0 {
+			return "", 0, nil
+		}
+
+		passed, pendingReview, score, err := service.HasPassingAttempt(userID, item.QuizID)
+		if err != nil {
+			return "", 0, err
+		}
+		if !passed && pendingReview {
+			return "The quiz attempt is waiting for the review of its essay answers", 0, nil
+		}
+		if !passed {
+			return "A passing quiz attempt is required to continue", 0, nil
+		}
+
+		return "", score, nil
+	case "scorm":
+		scormRuntime, err := service.ScormRepo.GetScormRuntime(userID, item.ID)
+		if err != nil && err.Error() != pg.ErrNoRows.Error() {
+			return "", 0, err
+		}
+		if scormRuntime.CompletedAt.IsZero() {
+			return "The SCORM content has not been completed yet", 0, nil
+		}
+
+		return "", scormRuntime.ScoreRaw, nil
+	case "video", "file", "slide":
+		if !RequiredTimeMet(item, timeSpent) {
+			return fmt.Sprintf("%d more seconds are required on this lecture", item.RequiredTime-timeSpent), 0, nil
+		}
+	}
+
+	return "", 0, nil
+}
+
+// HasPassingAttempt checks the quiz attempts of a user against the pass rate and returns the score that counts
+// under the attempt policy of the quiz. A score below the pass rate with essay answers waiting for review does not pass
+// until the review raises it, pendingReview tells the user is waiting for it.
+func (service *Service) HasPassingAttempt(userID int, quizID int) (bool, bool, float64, error) {
+	quiz, err := service.QuizRepo.GetQuizByID(quizID)
+	if err != nil {
+		return false, false, 0, err
+	}
+
+	quizResult, err := quizzes.UserAttemptResult(service.QuizRepo, userID, quiz)
+	if err != nil {
+		return false, false, 0, err
+	}
+
+	return quizResult.Passed, quizResult.PendingReview, quizResult.KeptScore, nil
+}
+
+// AdvanceUserProgress verifies the first item not completed and records its completion,
+// the cursor then moves to the next item not completed and the course is completed after the last one.
+// It returns the reason the item could not be completed, empty when it was.
+func (service *Service) AdvanceUserProgress(userProgress *m.UserProgress) (string, error) {
+	if userProgress.Completed {
+		return "", nil
+	}
+
+	courseItems, err := service.GetCourseItems(userProgress.CourseID)
+	if err != nil {
+		return "", err
+	}
+
+	completions, err := service.GetItemCompletions(*userProgress)
+	if err != nil {
+		return "", err
+	}
+
+	currentIndex := CurrentItemIndex(courseItems, completions, 0)
+	if currentIndex < len(courseItems) {
+		return service.CompleteUnlockedItem(userProgress, courseItems[currentIndex].Item.ID)
+	}
+
+	return service.completeItem(userProgress, courseItems, completions, currentIndex)
+}
+
+// CompleteUnlockedItem verifies and records the completion of any unlocked item of a course,
+// for courses and modules that are not browsed in order.
+// It returns the reason the item could not be completed, empty when it was.
+func (service *Service) CompleteUnlockedItem(userProgress *m.UserProgress, moduleItemID int) (string, error) {
+	if userProgress.Completed {
+		return "", nil
+	}
+
+	courseItems, err := service.GetCourseItems(userProgress.CourseID)
+	if err != nil {
+		return "", err
+	}
+
+	completions, err := service.GetItemCompletions(*userProgress)
+	if err != nil {
+		return "", err
+	}
+
+	unlocks, unreleasedModules, err := service.itemUnlocks(*userProgress, courseItems, completions)
+	if err != nil {
+		return "", err
+	}
+
+	for i, courseItem := range courseItems {
+		if courseItem.Item.ID != moduleItemID {
+			continue
+		}
+
+		if !unlocks[i] {
+			return lockedReason(unreleasedModules[courseItem.ModuleID]), nil
+		}
+		if completions[moduleItemID].Status == cf.ItemStatusCompleted {
+			return "", nil
+		}
+
+		return service.completeItem(userProgress, courseItems, completions, i)
+	}
+
+	return "Module item not found in this course", nil
+}
+
+// AdvancePastItem completes an item for a user when it is unlocked and verified,
+// it is called when quiz or SCORM results come in
+func (service *Service) AdvancePastItem(userID int, moduleItemID int) error {
+	moduleItem, err := service.ModuleItemRepo.GetModuleItemByID(moduleItemID)
+	if err != nil {
+		return err
+	}
+
+	module, err := service.ModuleRepo.GetModuleByID(moduleItem.ModuleID)
+	if err != nil {
+		return err
+	}
+
+	userProgress, err := service.UserProgressRepo.GetSingleUserProgress(userID, module.CourseID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return nil
+		}
+		return err
+	}
+
+	_, err = service.CompleteUnlockedItem(&userProgress, moduleItemID)
+	return err
+}
+
+// AdvancePastQuiz completes the items of a quiz for a user in the courses the user is enrolled in,
+// it is called when the last essay answer of an attempt is reviewed
+func (service *Service) AdvancePastQuiz(userID int, quizID int) error {
+	moduleItems, err := service.ModuleItemRepo.GetModuleItemsByQuizID(quizID)
+	if err != nil {
+		return err
+	}
+
+	for _, moduleItem := range moduleItems {
+		if err := service.AdvancePastItem(userID, moduleItem.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// completeItem verifies the item at index and records its completion, the cursor then moves to
+// the first item not completed and the course is completed once every item is
+func (service *Service) completeItem(userProgress *m.UserProgress, courseItems []CourseItem, completions map[int]m.ModuleItemCompletion, index int) (string, error) {
+	now := utils.TimeNowUTC()
+	if index < len(courseItems) {
+		item := courseItems[index].Item
+
+		// Items reached before they had a record were started when the cursor moved onto them
+		startedAt := userProgress.ItemStartedAt
+		existing, ok := completions[item.ID]
+		if ok && !existing.StartedAt.IsZero() {
+			startedAt = existing.StartedAt
+		}
+
+		reason, score, err := service.VerifyItemCompletion(userProgress.UserID, item, existing.TimeSpent)
+		if err != nil || reason != "" {
+			return reason, err
+		}
+
+		completion := service.newCompletion(*userProgress, item.ID)
+		completion.StartedAt = startedAt
+		completion.CompletedAt = now
+		completion.Score = score
+
+		// Time on lectures comes from heartbeats, other items count the time since they were started
+		completion.TimeSpent = existing.TimeSpent
+		if !isLectureItem(item) {
+			completion.TimeSpent = int(now.Sub(startedAt).Seconds())
+		}
+
+		if err := service.CompletionRepo.CompleteItem(&completion); err != nil {
+			return "", err
+		}
+		completions[item.ID] = completion
+	}
+
+	nextIndex := CurrentItemIndex(courseItems, completions, 0)
+	if nextIndex < len(courseItems) {
+		nextItem := courseItems[nextIndex]
+		if nextItem.ModulePosition != userProgress.ModulePosition || nextItem.Item.Position != userProgress.ModuleItemPosition {
+			userProgress.ModulePosition = nextItem.ModulePosition
+			userProgress.ModuleItemPosition = nextItem.Item.Position
+			userProgress.ItemStartedAt = now
+		}
+
+		completion := service.newCompletion(*userProgress, nextItem.Item.ID)
+		completion.StartedAt = now
+		if err := service.CompletionRepo.StartItem(&completion); err != nil {
+			return "", err
+		}
+	} else {
+		userProgress.Completed = true
+		userProgress.CompletedDate = now.Format(cf.FormatDate)
+	}
+
+	return "", service.UserProgressRepo.SaveUserProgress(userProgress)
+}
+
+// itemUnlocks returns whether every item of a course is unlocked under the navigation mode of the course,
+// along with the release date of the modules not released yet
+func (service *Service) itemUnlocks(userProgress m.UserProgress, courseItems []CourseItem, completions map[int]m.ModuleItemCompletion) ([]bool, map[int]string, error) {
+	course, err := service.CourseRepo.GetCourseByID(userProgress.CourseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unreleasedModules, err := service.unreleasedModules(userProgress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ItemUnlocks(course.NavigationMode, courseItems, completions, unreleasedModules), unreleasedModules, nil
+}
+
+// ViewItem records that a user opened an item, locked items can not be viewed
+// It returns the reason the item can not be viewed, empty when the view was recorded.
+func (service *Service) ViewItem(userID int, moduleItemID int) (string, error) {
+	_, userProgress, reason, err := service.getUnlockedItem(userID, moduleItemID)
+	if err != nil || reason != "" {
+		return reason, err
+	}
+
+	completion := service.newCompletion(userProgress, moduleItemID)
+	completion.StartedAt = utils.TimeNowUTC()
+
+	return "", service.CompletionRepo.RecordItemView(&completion)
+}
+
+// UnlockedItemReason returns why a user can not access a module item, empty when the item is unlocked for the user
+func (service *Service) UnlockedItemReason(userID int, moduleItemID int) (string, error) {
+	_, _, reason, err := service.getUnlockedItem(userID, moduleItemID)
+	return reason, err
+}
+
+// RecordHeartbeat adds the time since the previous heartbeat of the player to the time spent on a lecture.
+// position is the playback position in seconds reported by the player.
+// It returns the reason the heartbeat was rejected, empty when it was recorded.
+func (service *Service) RecordHeartbeat(userID int, moduleItemID int, position float64) (response.HeartbeatResponse, string, error) {
+	heartbeat := response.HeartbeatResponse{ModuleItemID: moduleItemID}
+
+	moduleItem, userProgress, reason, err := service.getUnlockedItem(userID, moduleItemID)
+	if err != nil || reason != "" {
+		return heartbeat, reason, err
+	}
+
+	if !isLectureItem(moduleItem) {
+		return heartbeat, "Time is only tracked on video, file and slide lectures", nil
+	}
+
+	if position < 0 {
+		return heartbeat, "The position can not be negative", nil
+	}
+
+	now := utils.TimeNowUTC()
+	completion := service.newCompletion(userProgress, moduleItemID)
+	completion.StartedAt = now
+	completion.LastViewedAt = now
+	completion.LastHeartbeatAt = now
+	completion.LastPosition = position
+
+	credited, flagged := 0, false
+	err = service.CompletionRepo.RecordHeartbeat(&completion, func(previous m.ModuleItemCompletion) (int, bool) {
+		credited, flagged = creditHeartbeat(moduleItem, previous, position, now)
+		return credited, flagged
+	})
+	if err != nil {
+		return heartbeat, "", err
+	}
+
+	heartbeat.TimeSpent = completion.TimeSpent
+	heartbeat.RequiredTime = moduleItem.RequiredTime
+	heartbeat.RequiredTimeMet = RequiredTimeMet(moduleItem, completion.TimeSpent)
+	heartbeat.Credited = credited
+	heartbeat.Flagged = flagged
+
+	return heartbeat, "", nil
+}
+
+// CourseTimeSpent returns the time spent in seconds by a user on the items of a course in the current cycle
+func (service *Service) CourseTimeSpent(userProgress m.UserProgress) (int, error) {
+	completions, err := service.GetItemCompletions(userProgress)
+	if err != nil {
+		return 0, err
+	}
+
+	timeSpent := 0
+	for _, completion := range completions {
+		timeSpent += completion.TimeSpent
+	}
+
+	return timeSpent, nil
+}
+
+// RequiredTimeMet checks the time spent on an item against its required time
+func RequiredTimeMet(item m.ModuleItem, timeSpent int) bool {
+	return item.RequiredTime <= 0 || timeSpent >= item.RequiredTime
+}
+
+// creditHeartbeat returns the seconds of a heartbeat counted as time spent and whether it was flagged.
+// The wall clock since the previous heartbeat is counted only when the gap is short enough, for videos
+// it is capped by the playback progress and playback running faster than the wall clock allows is flagged.
+// Files and slides are counted while the page or slide in position keeps changing within HeartbeatMaxIdleTime.
+func creditHeartbeat(item m.ModuleItem, previous m.ModuleItemCompletion, position float64, now time.Time) (int, bool) {
+	if previous.LastHeartbeatAt.IsZero() {
+		return 0, false
+	}
+
+	elapsed := now.Sub(previous.LastHeartbeatAt).Seconds()
+	if elapsed <= 0 || elapsed > cf.HeartbeatMaxInterval {
+		return 0, false
+	}
+
+	if item.ItemType != "video" {
+		if position == previous.LastPosition && !previous.PositionChangedAt.IsZero() &&
+			now.Sub(previous.PositionChangedAt).Seconds() > cf.HeartbeatMaxIdleTime {
+			return 0, false
+		}
+		return int(math.Round(elapsed)), false
+	}
+
+	played := position - previous.LastPosition
+	if played > elapsed*cf.HeartbeatMaxPlaybackRate+cf.HeartbeatPositionTolerance {
+		return 0, true
+	}
+
+	// Paused or rewound playback does not count
+	if played <= 0 {
+		return 0, false
+	}
+
+	return int(math.Round(math.Min(played, elapsed))), false
+}
+
+// getUnlockedItem returns a module item with the progress of the user in its course,
+// or the reason the user can not access the item
+func (service *Service) getUnlockedItem(userID int, moduleItemID int) (m.ModuleItem, m.UserProgress, string, error) {
+	moduleItem, err := service.ModuleItemRepo.GetModuleItemByID(moduleItemID)
+	if err != nil {
+		return moduleItem, m.UserProgress{}, "Module item not found", nil
+	}
+
+	module, err := service.ModuleRepo.GetModuleByID(moduleItem.ModuleID)
+	if err != nil {
+		return moduleItem, m.UserProgress{}, "Module item not found", nil
+	}
+
+	userProgress, err := service.UserProgressRepo.GetSingleUserProgress(userID, module.CourseID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return moduleItem, userProgress, "You are not enrolled in this course", nil
+		}
+		return moduleItem, userProgress, "", err
+	}
+
+	courseItems, err := service.GetCourseItems(module.CourseID)
+	if err != nil {
+		return moduleItem, userProgress, "", err
+	}
+
+	itemStates, err := service.GetItemStates(userProgress, courseItems)
+	if err != nil {
+		return moduleItem, userProgress, "", err
+	}
+
+	if !itemStates[moduleItemID].Unlocked {
+		return moduleItem, userProgress, lockedReason(itemStates[moduleItemID].UnlockDate), nil
+	}
+
+	return moduleItem, userProgress, "", nil
+}
+
+// lockedReason tells a trainee why an item is locked, with the date its module opens when it is not released yet
+func lockedReason(unlockDate string) string {
+	if unlockDate != "" {
+		return fmt.Sprintf("This lecture opens on %s", unlockDate)
+	}
+
+	return "This lecture is locked"
+}
+
+func isLectureItem(item m.ModuleItem) bool {
+	return item.ItemType == "video" || item.ItemType == "file" || item.ItemType == "slide"
+}
+
+func (service *Service) newCompletion(userProgress m.UserProgress, moduleItemID int) m.ModuleItemCompletion {
+	return m.ModuleItemCompletion{
+		UserID:       userProgress.UserID,
+		ModuleItemID: moduleItemID,
+		CourseID:     userProgress.CourseID,
+		Cycle:        progressCycle(userProgress),
+	}
+}
+
+// progressCycle returns the cycle of a progress, progresses created before recertification are in the first cycle
+func progressCycle(userProgress m.UserProgress) int {
+	if userProgress.Cycle <= 0 {
+		return 1
+	}
+
+	return userProgress.Cycle
+}
@@ -0,0 +1,384 @@
+package questionbanks
+
+import (
+	"net/http"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/quizzes"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+
+	valid "github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+)
+
+type QuestionBankController struct {
+	cm.BaseController
+	QuestionBankRepo rp.QuestionBankRepository
+	QuizRepo         rp.QuizRepository
+	SkillKeywordRepo rp.SkillKeywordRepository
+}
+
+func NewQuestionBankController(logger echo.Logger, questionBankRepo rp.QuestionBankRepository, quizRepo rp.QuizRepository, skillKeywordRepo rp.SkillKeywordRepository) (ctr *QuestionBankController) {
+	ctr = &QuestionBankController{cm.BaseController{}, questionBankRepo, quizRepo, skillKeywordRepo}
+	ctr.Init(logger)
+	return
+}
+
+// GetQuestionBankList returns the question banks, filtered by skill keyword when one is given
+func (ctr *QuestionBankController) GetQuestionBankList(c echo.Context) error {
+	listParams := new(param.QuestionBankListParams)
+	if err := c.Bind(listParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	questionBanks, err := ctr.QuestionBankRepo.GetQuestionBanks(listParams)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch question banks",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Question banks retrieved successfully",
+		Data:    questionBanks,
+	})
+}
+
+// GetQuestionBankDetail returns a question bank with its questions and their answers
+func (ctr *QuestionBankController) GetQuestionBankDetail(c echo.Context) error {
+	bankParams := new(param.QuestionBankParams)
+	if err := c.Bind(bankParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(bankParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	questionBank, err := ctr.QuestionBankRepo.GetQuestionBankByID(bankParams.QuestionBankID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Question bank not found",
+		})
+	}
+
+	questions, err := ctr.QuestionBankRepo.GetBankQuestions(questionBank.ID, 0)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch question bank questions",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Question bank retrieved successfully",
+		Data: map[string]interface{}{
+			"question_bank": questionBank,
+			"questions":     questions,
+		},
+	})
+}
+
+// SaveQuestionBank creates a question bank, or updates it when an ID is given, with its skill keywords
+func (ctr *QuestionBankController) SaveQuestionBank(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+
+	saveParams := new(param.SaveQuestionBankParams)
+	if err := c.Bind(saveParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(saveParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	for _, skillKeywordID := range saveParams.SkillKeywordIDs {
+		if _, err := ctr.SkillKeywordRepo.GetByID(skillKeywordID); err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Skill keyword not found",
+			})
+		}
+	}
+
+	questionBank := m.QuestionBank{CreatedBy: userProfile.ID}
+	if saveParams.ID > 0 {
+		existingBank, err := ctr.QuestionBankRepo.GetQuestionBankByID(saveParams.ID)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Question bank not found",
+			})
+		}
+		questionBank = existingBank
+	}
+
+	questionBank.Name = saveParams.Name
+	questionBank.Description = saveParams.Description
+	questionBank.SkillKeywordIDs = saveParams.SkillKeywordIDs
+	if questionBank.SkillKeywordIDs == nil {
+		questionBank.SkillKeywordIDs = []int{}
+	}
+
+	if err := ctr.QuestionBankRepo.SaveQuestionBank(&questionBank); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save question bank",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Question bank saved successfully",
+		Data:    questionBank,
+	})
+}
+
+// DeleteQuestionBank deletes a question bank and its questions, a bank that quizzes draw from can not be deleted
+func (ctr *QuestionBankController) DeleteQuestionBank(c echo.Context) error {
+	bankParams := new(param.QuestionBankParams)
+	if err := c.Bind(bankParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(bankParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if _, err := ctr.QuestionBankRepo.GetQuestionBankByID(bankParams.QuestionBankID); err != nil {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Question bank not found",
+		})
+	}
+
+	ruleCount, err := ctr.QuestionBankRepo.CountDrawRulesByBank(bankParams.QuestionBankID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to delete question bank",
+		})
+	}
+	if ruleCount > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "Quizzes still draw questions from this question bank",
+		})
+	}
+
+	if err := ctr.QuestionBankRepo.DeleteQuestionBank(bankParams.QuestionBankID); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to delete question bank",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Question bank deleted successfully",
+	})
+}
+
+// SaveBankQuestion creates a question of a question bank, or updates it when an ID is given, with its answers.
+// Attempts that already showed an updated question are graded with its new answers.
+func (ctr *QuestionBankController) SaveBankQuestion(c echo.Context) error {
+	questionParams := new(param.SaveBankQuestionParams)
+	if err := c.Bind(questionParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(questionParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if message := validateBankQuestion(questionParams); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
+	if _, err := ctr.QuestionBankRepo.GetQuestionBankByID(questionParams.QuestionBankID); err != nil {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Question bank not found",
+		})
+	}
+
+	if questionParams.ID > 0 {
+		existingQuestion, err := ctr.QuestionBankRepo.GetBankQuestionByID(questionParams.ID)
+		if err != nil || existingQuestion.QuestionBankID != questionParams.QuestionBankID {
+			return c.JSON(http.StatusNotFound, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Question not found",
+			})
+		}
+	}
+
+	question := &m.QuizQuestion{
+		QuestionBankID:    questionParams.QuestionBankID,
+		Difficulty:        questionParams.Difficulty,
+		QuestionType:      questionParams.QuestionType,
+		QuestionText:      questionParams.QuestionText,
+		Explanation:       questionParams.Explanation,
+		Weight:            questionParams.Weight,
+		IsMultipleCorrect: questionParams.IsMultipleCorrect,
+		ScoringStrategy:   questionParams.ScoringStrategy,
+	}
+	question.ID = questionParams.ID
+	if question.ScoringStrategy == "" {
+		question.ScoringStrategy = cf.ScoringAllOrNothing
+	}
+
+	answers := make([]m.QuizAnswer, len(questionParams.Answers))
+	for i, answerParam := range questionParams.Answers {
+		answers[i] = m.QuizAnswer{
+			AnswerText: answerParam.AnswerText,
+			IsCorrect:  answerParam.IsCorrect,
+		}
+	}
+
+	answers, message := quizzes.BuildAnswerKey(question, answers, questionParams.QuestionKeyParams)
+	if message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
+	if err := ctr.QuizRepo.SaveQuizQuestion(question, answers); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save question",
+		})
+	}
+	question.Answers = answers
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Question saved successfully",
+		Data:    question,
+	})
+}
+
+// DeleteBankQuestion deletes a question of a question bank, attempts that showed it keep it
+func (ctr *QuestionBankController) DeleteBankQuestion(c echo.Context) error {
+	deleteParams := new(param.DeleteBankQuestionParams)
+	if err := c.Bind(deleteParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(deleteParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if _, err := ctr.QuestionBankRepo.GetBankQuestionByID(deleteParams.QuestionID); err != nil {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Question not found",
+		})
+	}
+
+	if err := ctr.QuestionBankRepo.DeleteBankQuestion(deleteParams.QuestionID); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to delete question",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Question deleted successfully",
+	})
+}
+
+// validateBankQuestion returns why a question of a question bank is invalid, empty when it is valid
+func validateBankQuestion(questionParams *param.SaveBankQuestionParams) string {
+	if _, ok := cf.QuestionTypeLabels[questionParams.QuestionType]; !ok {
+		return "Invalid question type"
+	}
+
+	if _, ok := cf.DifficultyLabels[questionParams.Difficulty]; !ok {
+		return "Invalid difficulty"
+	}
+
+	if questionParams.Weight <= 0 {
+		return "weight must be positive"
+	}
+
+	if message := quizzes.ValidateScoringStrategy(questionParams.ScoringStrategy); message != "" {
+		return message
+	}
+
+	if questionParams.QuestionType == cf.QuestionTypeMultipleChoice {
+		if len(questionParams.Answers) < 2 {
+			return "Multiple choice questions must have at least two options"
+		}
+
+		correctCount := 0
+		for _, answer := range questionParams.Answers {
+			if answer.IsCorrect {
+				correctCount++
+			}
+		}
+		if correctCount == 0 {
+			return "Multiple choice questions must have at least one correct answer"
+		}
+		if correctCount > 1 && !questionParams.IsMultipleCorrect {
+			return "Only one answer can be correct unless is_multiple_correct is set"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,228 @@
+package questionbanks
+
+import (
+	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/quizzes"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/go-pg/pg/v9/orm"
+	"github.com/labstack/echo/v4"
+)
+
+type PgQuestionBankRepository struct {
+	cm.AppRepository
+}
+
+func NewPgQuestionBankRepository(logger echo.Logger) (repo *PgQuestionBankRepository) {
+	repo = &PgQuestionBankRepository{}
+	repo.Init(logger)
+	return
+}
+
+// GetQuestionBanks returns the question banks with their skill keywords and question count, ordered by name
+func (repo *PgQuestionBankRepository) GetQuestionBanks(params *param.QuestionBankListParams) ([]m.QuestionBank, error) {
+	var questionBanks []m.QuestionBank
+
+	query := repo.DB.Model(&questionBanks).
+		Where("question_bank.deleted_at IS NULL").
+		Order("question_bank.name ASC")
+
+	if params.SkillKeywordID > 0 {
+		query.Where(`EXISTS (SELECT 1 FROM question_bank_skill_keywords AS qbsk
+			WHERE qbsk.question_bank_id = question_bank.id AND qbsk.skill_keyword_id = ? AND qbsk.deleted_at IS NULL)`, params.SkillKeywordID)
+	}
+
+	if err := query.Select(); err != nil {
+		repo.Logger.Errorf("Error fetching question banks: %v", err)
+		return nil, err
+	}
+
+	for i := range questionBanks {
+		if err := repo.loadBankDetails(&questionBanks[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return questionBanks, nil
+}
+
+// GetQuestionBankByID returns a question bank with its skill keywords and question count
+func (repo *PgQuestionBankRepository) GetQuestionBankByID(questionBankID int) (m.QuestionBank, error) {
+	questionBank := m.QuestionBank{}
+
+	err := repo.DB.Model(&questionBank).
+		Where("id = ?", questionBankID).
+		Where("deleted_at IS NULL").
+		First()
+	if err != nil {
+		return questionBank, err
+	}
+
+	err = repo.loadBankDetails(&questionBank)
+	return questionBank, err
+}
+
+// SaveQuestionBank creates or updates a question bank and replaces its skill keywords
+func (repo *PgQuestionBankRepository) SaveQuestionBank(questionBank *m.QuestionBank) error {
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		var err error
+		if questionBank.ID == 0 {
+			_, err = tx.Model(questionBank).Insert()
+		} else {
+			_, err = tx.Model(questionBank).
+				Column("name", "description", "updated_at").
+				WherePK().
+				Where("deleted_at IS NULL").
+				Update()
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("DELETE FROM question_bank_skill_keywords WHERE question_bank_id = ?", questionBank.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, skillKeywordID := range questionBank.SkillKeywordIDs {
+			bankSkillKeyword := m.QuestionBankSkillKeyword{
+				QuestionBankID: questionBank.ID,
+				SkillKeywordID: skillKeywordID,
+			}
+			if _, err := tx.Model(&bankSkillKeyword).OnConflict("DO NOTHING").Insert(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error saving question bank: %v", err)
+	}
+
+	return err
+}
+
+// DeleteQuestionBank soft deletes a question bank and its questions
+func (repo *PgQuestionBankRepository) DeleteQuestionBank(questionBankID int) error {
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model((*m.QuizQuestion)(nil)).
+			Set("deleted_at = NOW()").
+			Where("question_bank_id = ?", questionBankID).
+			Where("deleted_at IS NULL").
+			Update()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Model((*m.QuestionBank)(nil)).
+			Set("deleted_at = NOW()").
+			Where("id = ?", questionBankID).
+			Where("deleted_at IS NULL").
+			Update()
+		return err
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error deleting question bank with ID %d: %v", questionBankID, err)
+	}
+
+	return err
+}
+
+// CountDrawRulesByBank counts the draw rules of quizzes drawing from a question bank
+func (repo *PgQuestionBankRepository) CountDrawRulesByBank(questionBankID int) (int, error) {
+	count, err := repo.DB.Model((*m.QuizDrawRule)(nil)).
+		Join("JOIN quizzes AS q ON q.id = quiz_draw_rule.quiz_id AND q.deleted_at IS NULL").
+		Where("quiz_draw_rule.question_bank_id = ?", questionBankID).
+		Where("quiz_draw_rule.deleted_at IS NULL").
+		Count()
+
+	if err != nil {
+		repo.Logger.Errorf("Error counting draw rules of question bank %d: %v", questionBankID, err)
+	}
+
+	return count, err
+}
+
+// GetBankQuestions returns the questions of a question bank with their answers,
+// difficulty 0 returns the questions of any difficulty
+func (repo *PgQuestionBankRepository) GetBankQuestions(questionBankID int, difficulty int) ([]m.QuizQuestion, error) {
+	var questions []m.QuizQuestion
+
+	query := quizzes.WithAnswerKeys(repo.DB.Model(&questions)).
+		Relation("Answers", func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("deleted_at IS NULL").Order("position ASC", "id ASC"), nil
+		}).
+		Where("quiz_question.question_bank_id = ?", questionBankID).
+		Where("quiz_question.deleted_at IS NULL").
+		Order("quiz_question.position ASC", "quiz_question.id ASC")
+
+	if difficulty > 0 {
+		query.Where("quiz_question.difficulty = ?", difficulty)
+	}
+
+	if err := query.Select(); err != nil {
+		repo.Logger.Errorf("Error fetching questions of question bank %d: %v", questionBankID, err)
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// GetBankQuestionByID returns a question of a question bank
+func (repo *PgQuestionBankRepository) GetBankQuestionByID(questionID int) (m.QuizQuestion, error) {
+	question := m.QuizQuestion{}
+
+	err := repo.DB.Model(&question).
+		Where("id = ?", questionID).
+		Where("question_bank_id IS NOT NULL").
+		Where("deleted_at IS NULL").
+		First()
+
+	return question, err
+}
+
+// DeleteBankQuestion soft deletes a question of a question bank, attempts that showed it keep it
+func (repo *PgQuestionBankRepository) DeleteBankQuestion(questionID int) error {
+	_, err := repo.DB.Model((*m.QuizQuestion)(nil)).
+		Set("deleted_at = NOW()").
+		Where("id = ?", questionID).
+		Where("question_bank_id IS NOT NULL").
+		Where("deleted_at IS NULL").
+		Update()
+
+	if err != nil {
+		repo.Logger.Errorf("Error deleting bank question with ID %d: %v", questionID, err)
+	}
+
+	return err
+}
+
+// loadBankDetails fills the skill keywords and the question count of a question bank
+func (repo *PgQuestionBankRepository) loadBankDetails(questionBank *m.QuestionBank) error {
+	questionBank.SkillKeywordIDs = []int{}
+	err := repo.DB.Model((*m.QuestionBankSkillKeyword)(nil)).
+		Column("skill_keyword_id").
+		Where("question_bank_id = ?", questionBank.ID).
+		Where("deleted_at IS NULL").
+		Order("skill_keyword_id ASC").
+		Select(&questionBank.SkillKeywordIDs)
+	if err != nil {
+		repo.Logger.Errorf("Error fetching skill keywords of question bank %d: %v", questionBank.ID, err)
+		return err
+	}
+
+	questionBank.QuestionCount, err = repo.DB.Model((*m.QuizQuestion)(nil)).
+		Where("question_bank_id = ?", questionBank.ID).
+		Where("deleted_at IS NULL").
+		Count()
+	if err != nil {
+		repo.Logger.Errorf("Error counting questions of question bank %d: %v", questionBank.ID, err)
+	}
+
+	return err
+}
@@ -0,0 +1,104 @@
+package quizzes
+
+import (
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	"orientation-training-api/internal/interfaces/response"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"orientation-training-api/internal/platform/xapi"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AttemptExpiryJob closes the quiz attempts left open after their time limit,
+// they are graded with a score of 0
+type AttemptExpiryJob struct {
+	Logger   echo.Logger
+	QuizRepo rp.QuizRepository
+	XapiRepo rp.XapiRepository
+}
+
+func NewAttemptExpiryJob(logger echo.Logger, quizRepo rp.QuizRepository, xapiRepo rp.XapiRepository) *AttemptExpiryJob {
+	return &AttemptExpiryJob{logger, quizRepo, xapiRepo}
+}
+
+// Run closes every open attempt past its deadline and grace period
+func (job *AttemptExpiryJob) Run() error {
+	attempts, err := job.QuizRepo.GetExpiredQuizAttempts(submitDeadline(utils.TimeNowUTC()))
+	if err != nil {
+		return err
+	}
+
+	expiredCount := 0
+	for _, attempt := range attempts {
+		expired, err := closeExpiredAttempt(job.QuizRepo, job.XapiRepo, attempt.User, attempt.Quiz, attempt)
+		if err != nil {
+			job.Logger.Errorf("Failed to close expired quiz attempt %d: %v", attempt.ID, err)
+			continue
+		}
+		if expired {
+			expiredCount++
+		}
+	}
+
+	if expiredCount > 0 {
+		job.Logger.Infof("Closed %d expired quiz attempts", expiredCount)
+	}
+
+	return nil
+}
+
+// submitDeadline returns the time before which the deadline of an attempt must fall for the attempt to be expired
+func submitDeadline(now time.Time) time.Time {
+	return now.Add(-cf.QuizSubmitGracePeriod * time.Second)
+}
+
+// attemptExpired checks whether the time limit of an attempt is over, grace period included
+func attemptExpired(attempt m.QuizAttempt, now time.Time) bool {
+	return !attempt.DeadlineAt.IsZero() && attempt.DeadlineAt.Before(submitDeadline(now))
+}
+
+// closeExpiredAttempt closes an attempt whose time limit is over and records it as failed,
+// it reports false when the attempt was already closed
+func closeExpiredAttempt(quizRepo rp.QuizRepository, xapiRepo rp.XapiRepository, user m.User, quiz m.Quiz, attempt m.QuizAttempt) (bool, error) {
+	expired, err := quizRepo.ExpireQuizAttempt(attempt.ID)
+	if err != nil || !expired {
+		return expired, err
+	}
+
+	passed := false
+	statement := xapi.NewStatement(
+		xapi.NewAgent(user.Email, user.UserProfile.FirstName+" "+user.UserProfile.LastName),
+		xapi.VerbFailed,
+		xapi.NewActivity(xapi.ActivityTypeAssessment, quiz.ID, quiz.Title),
+	)
+	statement.Result = xapi.NewScoreResult(0, quiz.TotalScore, &passed, true)
+	statement.Context = &xapi.Context{Extensions: map[string]interface{}{xapi.ExtensionAttempt: attempt.Attempt}}
+
+	return true, xapiRepo.RecordStatement(user.ID, statement)
+}
+
+// newAttemptResponse returns an attempt with the time left to submit it
+func newAttemptResponse(attempt m.QuizAttempt, quiz m.Quiz, resumed bool) response.QuizAttemptResponse {
+	attemptResponse := response.QuizAttemptResponse{
+		AttemptID: attempt.ID,
+		QuizID:    attempt.QuizID,
+		Attempt:   attempt.Attempt,
+		Status:    attempt.Status,
+		StartedAt: attempt.StartedAt,
+		TimeLimit: quiz.TimeLimit,
+		Resumed:   resumed,
+	}
+
+	if !attempt.DeadlineAt.IsZero() {
+		deadlineAt := attempt.DeadlineAt
+		attemptResponse.DeadlineAt = &deadlineAt
+		if remaining := int(deadlineAt.Sub(utils.TimeNowUTC()).Seconds()); remaining > 0 {
+			attemptResponse.RemainingSeconds = remaining
+		}
+	}
+
+	return attemptResponse
+}
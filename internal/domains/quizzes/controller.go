@@ -1,6 +1,8 @@
 package quizzes
 
 import (
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
@@ -8,6 +10,12 @@ import (
 	param "orientation-training-api/internal/interfaces/requestparams"
 	"orientation-training-api/internal/interfaces/response"
 	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/quizformat"
+	"orientation-training-api/internal/platform/utils"
+	"orientation-training-api/internal/platform/xapi"
+	"sort"
+	"strings"
+	"time"
 
 	valid "github.com/asaskevich/govalidator"
 	"github.com/labstack/echo/v4"
@@ -15,11 +23,20 @@ import (
 
 type QuizController struct {
 	cm.BaseController
-	QuizRepo rp.QuizRepository
+	QuizRepo         rp.QuizRepository
+	XapiRepo         rp.XapiRepository
+	QuestionBankRepo rp.QuestionBankRepository
+	Progression      ItemProgression
 }
 
-func NewQuizController(logger echo.Logger, quizRepo rp.QuizRepository) (ctr *QuizController) {
-	ctr = &QuizController{cm.BaseController{}, quizRepo}
+// ItemProgression moves trainees past the quiz items of their courses,
+// it is implemented by the progression service which depends on this package
+type ItemProgression interface {
+	AdvancePastQuiz(userID int, quizID int) error
+}
+
+func NewQuizController(logger echo.Logger, quizRepo rp.QuizRepository, xapiRepo rp.XapiRepository, questionBankRepo rp.QuestionBankRepository, progression ItemProgression) (ctr *QuizController) {
+	ctr = &QuizController{cm.BaseController{}, quizRepo, xapiRepo, questionBankRepo, progression}
 	ctr.Init(logger)
 	return
 }
@@ -53,12 +70,20 @@ func (ctr *QuizController) CreateQuiz(c echo.Context) error {
 		})
 	}
 
+	if message := ValidatePolicyParams(createQuizParams.QuizPolicyParams, createQuizParams.TotalScore); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
 	quiz := &m.Quiz{
 		Title:      createQuizParams.Title,
 		Difficulty: createQuizParams.Difficulty,
 		TotalScore: createQuizParams.TotalScore,
 		TimeLimit:  createQuizParams.TimeLimit,
 	}
+	ApplyPolicyParams(quiz, createQuizParams.QuizPolicyParams)
 
 	err := ctr.QuizRepo.SaveQuiz(quiz)
 	if err != nil {
@@ -105,6 +130,13 @@ func (ctr *QuizController) UpdateQuiz(c echo.Context) error {
 		})
 	}
 
+	if message := ValidatePolicyParams(updateQuizParams.QuizPolicyParams, updateQuizParams.TotalScore); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
 	// Check if quiz exists
 	existingQuiz, err := ctr.QuizRepo.GetQuizByID(updateQuizParams.ID)
 	if err != nil {
@@ -120,6 +152,7 @@ func (ctr *QuizController) UpdateQuiz(c echo.Context) error {
 	existingQuiz.Difficulty = updateQuizParams.Difficulty
 	existingQuiz.TotalScore = updateQuizParams.TotalScore
 	existingQuiz.TimeLimit = updateQuizParams.TimeLimit
+	ApplyPolicyParams(&existingQuiz, updateQuizParams.QuizPolicyParams)
 
 	err = ctr.QuizRepo.SaveQuiz(&existingQuiz)
 	if err != nil {
@@ -220,13 +253,30 @@ func (ctr *QuizController) GetQuizDetail(c echo.Context) error {
 		})
 	}
 
-	// For non-admin users, remove the "is_correct" field from answers
+	// For non-admin users, remove the answer keys and the draw rules and show the questions of their attempt in its order
+	drawRules := []m.QuizDrawRule{}
 	userProfile := c.Get("user_profile").(m.User)
-	if userProfile.RoleID != cf.ManagerRoleID {
-		for i := range questions {
-			for j := range questions[i].Answers {
-				questions[i].Answers[j].IsCorrect = false
-			}
+	if userProfile.RoleID == cf.ManagerRoleID {
+		drawRules, err = ctr.QuizRepo.GetDrawRules(getQuizParams.QuizID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to fetch quiz draw rules",
+			})
+		}
+	} else {
+		attemptQuestions, err := QuestionsForUser(ctr.QuizRepo, quiz, userProfile.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to fetch quiz questions",
+			})
+		}
+
+		questions = make([]m.QuizQuestion, len(attemptQuestions))
+		for i, attemptQuestion := range attemptQuestions {
+			questions[i] = attemptQuestion.QuizQuestion
+			HideAnswerKey(&questions[i])
 		}
 	}
 
@@ -234,8 +284,9 @@ func (ctr *QuizController) GetQuizDetail(c echo.Context) error {
 		Status:  cf.SuccessResponseCode,
 		Message: "Quiz details retrieved successfully",
 		Data: map[string]interface{}{
-			"quiz":      quiz,
-			"questions": questions,
+			"quiz":       quiz,
+			"questions":  questions,
+			"draw_rules": drawRules,
 		},
 	})
 }
@@ -324,6 +375,13 @@ func (ctr *QuizController) CreateQuizQuestion(c echo.Context) error {
 		})
 	}
 
+	if message := ValidateScoringStrategy(createQuestionParams.ScoringStrategy); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
 	// Check if quiz exists
 	_, err := ctr.QuizRepo.GetQuizByID(createQuestionParams.QuizID)
 	if err != nil {
@@ -341,6 +399,10 @@ func (ctr *QuizController) CreateQuizQuestion(c echo.Context) error {
 		QuestionText:      createQuestionParams.QuestionText,
 		Weight:            createQuestionParams.Weight,
 		IsMultipleCorrect: createQuestionParams.IsMultipleCorrect,
+		ScoringStrategy:   createQuestionParams.ScoringStrategy,
+	}
+	if question.ScoringStrategy == "" {
+		question.ScoringStrategy = cf.ScoringAllOrNothing
 	}
 
 	// Create answer objects
@@ -352,6 +414,14 @@ func (ctr *QuizController) CreateQuizQuestion(c echo.Context) error {
 		}
 	}
 
+	answers, message := BuildAnswerKey(question, answers, createQuestionParams.QuestionKeyParams)
+	if message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
 	// Save question and answers
 	err = ctr.QuizRepo.SaveQuizQuestion(question, answers)
 	if err != nil {
@@ -369,12 +439,11 @@ func (ctr *QuizController) CreateQuizQuestion(c echo.Context) error {
 	})
 }
 
-// SubmitFullQuiz submits all answers for a quiz at once
-func (ctr *QuizController) SubmitFullQuiz(c echo.Context) error {
-	userProfile := c.Get("user_profile").(m.User)
-	submitParams := new(param.SubmitFullQuizParams)
-
-	if err := c.Bind(submitParams); err != nil {
+// UpdateQuizQuestion updates a question of a quiz with its answers and answer key.
+// A question already shown in attempts is replaced by a new version, the attempts keep the version they showed.
+func (ctr *QuizController) UpdateQuizQuestion(c echo.Context) error {
+	updateQuestionParams := new(param.UpdateQuizQuestionParams)
+	if err := c.Bind(updateQuestionParams); err != nil {
 		ctr.Logger.Errorf("Failed to bind params: %v", err)
 		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
@@ -383,7 +452,7 @@ func (ctr *QuizController) SubmitFullQuiz(c echo.Context) error {
 		})
 	}
 
-	if _, err := valid.ValidateStruct(submitParams); err != nil {
+	if _, err := valid.ValidateStruct(updateQuestionParams); err != nil {
 		ctr.Logger.Errorf("Validation failed: %v", err)
 		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
@@ -391,163 +460,122 @@ func (ctr *QuizController) SubmitFullQuiz(c echo.Context) error {
 		})
 	}
 
-	maxAttempt, err := ctr.QuizRepo.GetMaxQuizAttempt(userProfile.ID, submitParams.QuizID)
-	if err != nil {
-		ctr.Logger.Errorf("Error getting max attempt: %v", err)
-		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
-			Status:  cf.FailResponseCode,
-			Message: "Failed to get attempt history",
-		})
-	}
-
-	currentAttempt := maxAttempt + 1
-
-	questions, err := ctr.QuizRepo.GetQuizQuestionsWithAnswers(submitParams.QuizID)
-	if err != nil {
-		ctr.Logger.Errorf("Failed to fetch quiz questions: %v", err)
-		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+	if message := ValidateScoringStrategy(updateQuestionParams.ScoringStrategy); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Failed to fetch quiz questions",
+			Message: message,
 		})
 	}
 
-	quiz, err := ctr.QuizRepo.GetQuizByID(submitParams.QuizID)
-	if err != nil {
-		ctr.Logger.Errorf("Failed to fetch quiz details: %v", err)
-		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+	question, err := ctr.QuizRepo.GetQuizQuestionByID(updateQuestionParams.QuestionID)
+	if err != nil || question.QuizID == 0 {
+		ctr.Logger.Errorf("Quiz question %d not found: %v", updateQuestionParams.QuestionID, err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Failed to fetch quiz details",
+			Message: "Quiz question not found",
 		})
 	}
 
-	questionsMap := make(map[int]m.QuizQuestion)
-	for _, q := range questions {
-		questionsMap[q.ID] = q
+	existingAnswerIDs := make(map[int]bool, len(question.Answers))
+	for _, answer := range question.Answers {
+		existingAnswerIDs[answer.ID] = true
 	}
 
-	totalScore := 0.0
-	submissionDetails := []map[string]interface{}{}
-	essaySubmissions := []map[string]interface{}{}
-	hasEssayQuestions := false
-
-	for _, answer := range submitParams.Answers {
-		question, exists := questionsMap[answer.QuestionID]
-		if !exists {
-			ctr.Logger.Warnf("Question ID %d not found in quiz %d", answer.QuestionID, submitParams.QuizID)
-			continue
+	answers := make([]m.QuizAnswer, len(updateQuestionParams.Answers))
+	for i, answerParam := range updateQuestionParams.Answers {
+		if answerParam.AnswerID > 0 && !existingAnswerIDs[answerParam.AnswerID] {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: fmt.Sprintf("Answer %d is not an answer of this question", answerParam.AnswerID),
+			})
 		}
 
-		score := 0.0
-		isCorrect := false
-		correctAnswersList := []int{}
-		reviewed := false
-
-		if question.QuestionType == cf.QuestionTypeMultipleChoice {
-			correctAnswerIDs := make(map[int]bool)
-			for _, a := range question.Answers {
-				if a.IsCorrect {
-					correctAnswerIDs[a.ID] = true
-					correctAnswersList = append(correctAnswersList, a.ID)
-				}
-			}
-
-			isCorrect = true
-			if len(answer.SelectedAnswerIds) != len(correctAnswerIDs) {
-				isCorrect = false
-			} else {
-				for _, selectedID := range answer.SelectedAnswerIds {
-					if !correctAnswerIDs[selectedID] {
-						isCorrect = false
-						break
-					}
-				}
-			}
-
-			if isCorrect {
-				score = question.Weight * quiz.TotalScore
-			}
-
-			reviewed = true
-		} else if question.QuestionType == cf.QuestionTypeEssay {
-			hasEssayQuestions = true
-			score = 0 // Initially 0, to be updated by manager
-			reviewed = false
+		answers[i] = m.QuizAnswer{
+			AnswerText: answerParam.AnswerText,
+			IsCorrect:  answerParam.IsCorrect,
 		}
+		answers[i].ID = answerParam.AnswerID
+	}
 
-		submission := &m.QuizSubmission{
-			UserID:            userProfile.ID,
-			QuizID:            submitParams.QuizID,
-			QuizQuestionID:    answer.QuestionID,
-			AnswerText:        answer.AnswerText,
-			SelectedAnswerIds: answer.SelectedAnswerIds,
-			Score:             score,
-			Attempt:           currentAttempt,
-			Reviewed:          reviewed,
-			SubmittedAt:       submitParams.SubmittedAt,
-		}
+	if updateQuestionParams.QuestionType > 0 {
+		question.QuestionType = updateQuestionParams.QuestionType
+	}
+	question.QuestionText = updateQuestionParams.QuestionText
+	question.Explanation = updateQuestionParams.Explanation
+	question.Weight = updateQuestionParams.Weight
+	question.IsMultipleCorrect = updateQuestionParams.IsMultipleCorrect
+	question.ScoringStrategy = updateQuestionParams.ScoringStrategy
+	if question.ScoringStrategy == "" {
+		question.ScoringStrategy = cf.ScoringAllOrNothing
+	}
 
-		err = ctr.QuizRepo.SaveQuizSubmission(submission)
-		if err != nil {
-			ctr.Logger.Errorf("Failed to save quiz submission for question %d: %v", answer.QuestionID, err)
-			continue
+	if question.QuestionType == cf.QuestionTypeMultipleChoice {
+		if message := ValidateOptions(answers); message != "" {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: message,
+			})
 		}
+	}
 
-		if question.QuestionType == cf.QuestionTypeMultipleChoice {
-			submissionDetail := map[string]interface{}{
-				"question_id":      answer.QuestionID,
-				"score":            score,
-				"is_correct":       isCorrect,
-				"selected_answers": answer.SelectedAnswerIds,
-				"correct_answers":  correctAnswersList,
-				"reviewed":         reviewed,
-				"attempt":          currentAttempt,
-				"explanation":      question.Explanation,
-			}
-			submissionDetails = append(submissionDetails, submissionDetail)
-		} else if question.QuestionType == cf.QuestionTypeEssay {
-			essaySubmission := map[string]interface{}{
-				"question_id": answer.QuestionID,
-				"reviewed":    reviewed,
-				"attempt":     currentAttempt,
-			}
-			essaySubmissions = append(essaySubmissions, essaySubmission)
-		}
+	answers, message := BuildAnswerKey(&question, answers, updateQuestionParams.QuestionKeyParams)
+	if message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
 
-		totalScore += score
+	return ctr.saveEditedQuestion(c, question, answers, "Quiz question updated successfully")
+}
+
+// DeleteQuizQuestion deletes a question of a quiz, attempts that showed it keep it.
+// New attempts share the total score among the remaining questions by their weight.
+func (ctr *QuizController) DeleteQuizQuestion(c echo.Context) error {
+	deleteQuestionParams := new(param.DeleteQuizQuestionParams)
+	if err := c.Bind(deleteQuestionParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
 	}
 
-	// Determine if passed (usually 70% is passing)
-	passThreshold := quiz.TotalScore * 0.7
-	passed := totalScore >= passThreshold
+	if _, err := valid.ValidateStruct(deleteQuestionParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
 
-	responseData := map[string]interface{}{
-		"quiz_id":        submitParams.QuizID,
-		"total_score":    totalScore,
-		"max_score":      quiz.TotalScore,
-		"passed":         passed,
-		"pass_threshold": passThreshold,
-		"submissions":    submissionDetails,
-		"attempt":        currentAttempt,
+	question, err := ctr.QuizRepo.GetQuizQuestionByID(deleteQuestionParams.QuestionID)
+	if err != nil || question.QuizID == 0 {
+		ctr.Logger.Errorf("Quiz question %d not found: %v", deleteQuestionParams.QuestionID, err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz question not found",
+		})
 	}
 
-	if hasEssayQuestions {
-		responseData["passed"] = true
-		responseData["essay_submissions"] = essaySubmissions
+	if err := ctr.QuizRepo.DeleteQuizQuestion(question.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to delete quiz question",
+		})
 	}
 
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
-		Message: "Quiz answers submitted successfully",
-		Data:    responseData,
+		Message: "Quiz question deleted successfully",
 	})
 }
 
-// GetQuizResults retrieves quiz results for a user
-func (ctr *QuizController) GetQuizResults(c echo.Context) error {
-	userProfile := c.Get("user_profile").(m.User)
-	getResultsParams := new(param.GetQuizResultsParams)
-
-	if err := c.Bind(getResultsParams); err != nil {
+// ReorderQuizQuestions sets the order of the questions of a quiz, attempts already started keep their order
+func (ctr *QuizController) ReorderQuizQuestions(c echo.Context) error {
+	reorderParams := new(param.ReorderQuizQuestionsParams)
+	if err := c.Bind(reorderParams); err != nil {
 		ctr.Logger.Errorf("Failed to bind params: %v", err)
 		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
@@ -556,7 +584,7 @@ func (ctr *QuizController) GetQuizResults(c echo.Context) error {
 		})
 	}
 
-	if _, err := valid.ValidateStruct(getResultsParams); err != nil {
+	if _, err := valid.ValidateStruct(reorderParams); err != nil {
 		ctr.Logger.Errorf("Validation failed: %v", err)
 		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
@@ -564,152 +592,1018 @@ func (ctr *QuizController) GetQuizResults(c echo.Context) error {
 		})
 	}
 
-	targetUserID := userProfile.ID
-	if userProfile.RoleID == cf.ManagerRoleID && getResultsParams.UserID > 0 {
-		targetUserID = getResultsParams.UserID
+	if _, err := ctr.QuizRepo.GetQuizByID(reorderParams.QuizID); err != nil {
+		ctr.Logger.Errorf("Quiz not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz not found",
+		})
 	}
 
-	maxAttempt, err := ctr.QuizRepo.GetMaxQuizAttempt(targetUserID, getResultsParams.QuizID)
+	questions, err := ctr.QuizRepo.GetQuizQuestionsWithAnswers(reorderParams.QuizID)
 	if err != nil {
-		ctr.Logger.Errorf("Error getting max attempt: %v", err)
 		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Failed to get attempt history",
+			Message: "Failed to fetch quiz questions",
 		})
 	}
 
-	if maxAttempt == 0 {
+	questionIDs := make([]int, len(questions))
+	for i, question := range questions {
+		questionIDs[i] = question.ID
+	}
+	if !sameIDs(reorderParams.QuestionIDs, questionIDs) {
 		return c.JSON(http.StatusOK, cf.JsonResponse{
-			Status:  cf.SuccessResponseCode,
-			Message: "No quiz attempts found",
-			Data: map[string]interface{}{
-				"passed": false,
-				"results": map[string]interface{}{
-					"quiz_id": getResultsParams.QuizID,
-					"answers": []map[string]interface{}{},
-				},
-			},
+			Status:  cf.FailResponseCode,
+			Message: "question_ids must list each question of the quiz once",
 		})
 	}
 
-	quiz, err := ctr.QuizRepo.GetQuizByID(getResultsParams.QuizID)
-	if err != nil {
-		ctr.Logger.Errorf("Quiz not found: %v", err)
-		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+	if err := ctr.QuizRepo.ReorderQuizQuestions(reorderParams.QuizID, reorderParams.QuestionIDs); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Quiz not found",
+			Message: "Failed to reorder quiz questions",
 		})
 	}
 
-	questions, err := ctr.QuizRepo.GetQuizQuestionsWithAnswers(getResultsParams.QuizID)
-	if err != nil {
-		ctr.Logger.Errorf("Failed to fetch quiz questions: %v", err)
-		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Quiz questions reordered successfully",
+	})
+}
+
+// UpdateQuizAnswer updates an answer option of a multiple choice question,
+// the question is replaced by a new version when it was already shown in attempts
+func (ctr *QuizController) UpdateQuizAnswer(c echo.Context) error {
+	updateAnswerParams := new(param.UpdateQuizAnswerParams)
+	if err := c.Bind(updateAnswerParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Failed to fetch quiz questions",
+			Message: "Invalid params",
+			Data:    err,
 		})
 	}
 
-	questionsMap := make(map[int]m.QuizQuestion)
-	for _, q := range questions {
-		questionsMap[q.ID] = q
+	if _, err := valid.ValidateStruct(updateAnswerParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
 	}
 
-	submissions, err := ctr.QuizRepo.GetQuizSubmissionsByUser(targetUserID, getResultsParams.QuizID)
-	if err != nil {
-		ctr.Logger.Errorf("Failed to fetch quiz submissions: %v", err)
-		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+	question, status, message := ctr.answerQuestion(updateAnswerParams.AnswerID)
+	if message != "" {
+		return c.JSON(status, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Failed to fetch quiz submissions",
+			Message: message,
 		})
 	}
 
-	latestAttemptSubmissions := []m.QuizSubmission{}
-	for _, submission := range submissions {
-		if submission.Attempt == maxAttempt {
-			latestAttemptSubmissions = append(latestAttemptSubmissions, submission)
+	for i := range question.Answers {
+		if question.Answers[i].ID == updateAnswerParams.AnswerID {
+			question.Answers[i].AnswerText = updateAnswerParams.AnswerText
+			question.Answers[i].IsCorrect = updateAnswerParams.IsCorrect
 		}
 	}
 
-	totalScore := 0.0
-	hasEssayQuestions := false
-	allEssaysReviewed := true
-	answers := []map[string]interface{}{}
+	if message = ValidateOptions(question.Answers); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
 
-	for _, submission := range latestAttemptSubmissions {
-		question, exists := questionsMap[submission.QuizQuestionID]
-		if !exists {
-			continue
-		}
+	return ctr.saveEditedQuestion(c, question, question.Answers, "Quiz answer updated successfully")
+}
 
-		if question.QuestionType == cf.QuestionTypeEssay {
-			hasEssayQuestions = true
-			if !submission.Reviewed {
-				allEssaysReviewed = false
-			}
+// DeleteQuizAnswer deletes an answer option of a multiple choice question,
+// the question is replaced by a new version when it was already shown in attempts
+func (ctr *QuizController) DeleteQuizAnswer(c echo.Context) error {
+	deleteAnswerParams := new(param.DeleteQuizAnswerParams)
+	if err := c.Bind(deleteAnswerParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
 
-			answerData := map[string]interface{}{
-				"question_id": submission.QuizQuestionID,
-				"answer_text": submission.AnswerText,
-			}
-			answers = append(answers, answerData)
-		} else if question.QuestionType == cf.QuestionTypeMultipleChoice {
-			isCorrect := false
-			correctAnswerIDs := []int{}
-
-			for _, a := range question.Answers {
-				if a.IsCorrect {
-					correctAnswerIDs = append(correctAnswerIDs, a.ID)
-				}
-			}
+	if _, err := valid.ValidateStruct(deleteAnswerParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
 
-			if len(submission.SelectedAnswerIds) == len(correctAnswerIDs) {
-				isCorrect = true
-				selectedMap := make(map[int]bool)
-				for _, id := range submission.SelectedAnswerIds {
-					selectedMap[id] = true
-				}
-
-				for _, id := range correctAnswerIDs {
-					if !selectedMap[id] {
-						isCorrect = false
-						break
-					}
-				}
-			}
+	question, status, message := ctr.answerQuestion(deleteAnswerParams.AnswerID)
+	if message != "" {
+		return c.JSON(status, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
 
-			answerData := map[string]interface{}{
-				"question_id":         submission.QuizQuestionID,
-				"selected_answer_ids": submission.SelectedAnswerIds,
-				"is_correct":          isCorrect,
-				"correct_answer_ids":  correctAnswerIDs,
-				"explanation":         question.Explanation,
-				"points":              question.Weight * quiz.TotalScore,
-			}
-			answers = append(answers, answerData)
+	answers := []m.QuizAnswer{}
+	for _, answer := range question.Answers {
+		if answer.ID != deleteAnswerParams.AnswerID {
+			answers = append(answers, answer)
 		}
-
-		totalScore += submission.Score
 	}
 
-	passThreshold := quiz.TotalScore * 0.7
-	passed := totalScore >= passThreshold || hasEssayQuestions
-
-	resultsData := map[string]interface{}{
-		"quiz_id": getResultsParams.QuizID,
-		"answers": answers,
-		"attempt": maxAttempt,
+	if message = ValidateOptions(answers); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
+	return ctr.saveEditedQuestion(c, question, answers, "Quiz answer deleted successfully")
+}
+
+// ReorderQuizAnswers sets the order of the answer options of a question,
+// the question is replaced by a new version when it was already shown in attempts so their option order is kept
+func (ctr *QuizController) ReorderQuizAnswers(c echo.Context) error {
+	reorderParams := new(param.ReorderQuizAnswersParams)
+	if err := c.Bind(reorderParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(reorderParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	question, err := ctr.QuizRepo.GetQuizQuestionByID(reorderParams.QuestionID)
+	if err != nil {
+		ctr.Logger.Errorf("Quiz question %d not found: %v", reorderParams.QuestionID, err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz question not found",
+		})
+	}
+
+	answerIDs := make([]int, len(question.Answers))
+	for i, answer := range question.Answers {
+		answerIDs[i] = answer.ID
+	}
+	if !sameIDs(reorderParams.AnswerIDs, answerIDs) {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "answer_ids must list each answer of the question once",
+		})
+	}
+
+	answersByID := make(map[int]m.QuizAnswer, len(question.Answers))
+	for _, answer := range question.Answers {
+		answersByID[answer.ID] = answer
+	}
+	answers := make([]m.QuizAnswer, len(reorderParams.AnswerIDs))
+	for i, answerID := range reorderParams.AnswerIDs {
+		answers[i] = answersByID[answerID]
+	}
+
+	return ctr.saveEditedQuestion(c, question, answers, "Quiz answers reordered successfully")
+}
+
+// ImportQuiz creates a quiz from the questions of a GIFT, QTI 2.1 or CSV file,
+// the errors of the file are reported with their line and no quiz is created
+func (ctr *QuizController) ImportQuiz(c echo.Context) error {
+	importParams := new(param.ImportQuizParams)
+	if err := c.Bind(importParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(importParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if message := ValidatePolicyParams(importParams.QuizPolicyParams, importParams.TotalScore); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
+	format := importParams.Format
+	if format == "" {
+		format = quizformat.FormatFromFileName(importParams.FileName)
+	}
+	if _, ok := utils.FindStringInArray(cf.QuizFormats, format); !ok {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid format. Allowed values: gift, qti, csv",
+		})
+	}
+
+	base64Data := importParams.File
+	if parts := strings.SplitN(base64Data, ",", 2); len(parts) == 2 {
+		base64Data = parts[1]
+	}
+	fileData, err := base64.StdEncoding.DecodeString(base64Data)
+	if err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid file data",
+		})
+	}
+
+	questions, lineErrors := quizformat.Parse(format, fileData)
+
+	quizData := &param.QuizData{
+		QuizPolicyParams: importParams.QuizPolicyParams,
+		Difficulty:       importParams.Difficulty,
+		TotalScore:       importParams.TotalScore,
+		TimeLimit:        importParams.TimeLimit,
+	}
+	totalWeight := 0.0
+	for _, question := range questions {
+		message := ValidateScoringStrategy(question.ScoringStrategy)
+		if message == "" {
+			_, _, message = NewQuestionFromData(quizData, question.QuizQuestion)
+		}
+		if message != "" {
+			lineErrors = append(lineErrors, quizformat.LineError{Source: question.Source, Line: question.Line, Message: message})
+		}
+
+		totalWeight += question.Weight
+		quizData.Questions = append(quizData.Questions, question.QuizQuestion)
+	}
+	if len(lineErrors) == 0 && (totalWeight < 0.99 || totalWeight > 1.01) {
+		lineErrors = append(lineErrors, quizformat.LineError{Message: "Question weights must sum to 1.0"})
+	}
+
+	if len(lineErrors) > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: fmt.Sprintf("The file has %d errors, no quiz was created", len(lineErrors)),
+			Data: map[string]interface{}{
+				"errors": lineErrors,
+			},
+		})
+	}
+
+	quizID, err := ctr.QuizRepo.CreateQuizWithQuestionsAndAnswers(quizData, importParams.Title)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to import quiz: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to create quiz",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Quiz imported successfully",
+		Data: map[string]interface{}{
+			"quiz_id":        quizID,
+			"question_count": len(quizData.Questions),
+		},
+	})
+}
+
+// ExportQuiz returns the questions of a quiz as a GIFT, QTI 2.1 or CSV file in a base64 data URI, the file can be imported back.
+// The questions drawn from question banks are not exported.
+func (ctr *QuizController) ExportQuiz(c echo.Context) error {
+	exportParams := new(param.ExportQuizParams)
+	if err := c.Bind(exportParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(exportParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if _, ok := utils.FindStringInArray(cf.QuizFormats, exportParams.Format); !ok {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid format. Allowed values: gift, qti, csv",
+		})
+	}
+
+	quiz, err := ctr.QuizRepo.GetQuizByID(exportParams.QuizID)
+	if err != nil {
+		ctr.Logger.Errorf("Quiz not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz not found",
+		})
+	}
+
+	questions, err := ctr.QuizRepo.GetQuizQuestionsWithAnswers(quiz.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch quiz questions",
+		})
+	}
+
+	content, err := quizformat.Write(exportParams.Format, quiz.Title, questions)
+	if err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	contentType := quizformat.ContentType(exportParams.Format)
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Quiz exported successfully",
+		Data: map[string]interface{}{
+			"format":    exportParams.Format,
+			"file_name": fmt.Sprintf("quiz-%d%s", quiz.ID, quizformat.FileExtension(exportParams.Format)),
+			"file":      "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content),
+		},
+	})
+}
+
+// SaveQuizDrawRules replaces the rules drawing questions from question banks for each attempt on a quiz,
+// attempts already started keep their questions
+func (ctr *QuizController) SaveQuizDrawRules(c echo.Context) error {
+	drawRulesParams := new(param.SaveQuizDrawRulesParams)
+	if err := c.Bind(drawRulesParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(drawRulesParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if _, err := ctr.QuizRepo.GetQuizByID(drawRulesParams.QuizID); err != nil {
+		ctr.Logger.Errorf("Quiz not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz not found",
+		})
+	}
+
+	message, err := ValidateDrawRules(ctr.QuestionBankRepo, drawRulesParams.Rules)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to validate draw rules: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch question banks",
+		})
+	}
+	if message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
+	}
+
+	rules := make([]m.QuizDrawRule, len(drawRulesParams.Rules))
+	for i, ruleParam := range drawRulesParams.Rules {
+		rules[i] = m.QuizDrawRule{
+			QuestionBankID: ruleParam.QuestionBankID,
+			Difficulty:     ruleParam.Difficulty,
+			QuestionCount:  ruleParam.QuestionCount,
+		}
+	}
+
+	if err := ctr.QuizRepo.SaveDrawRules(drawRulesParams.QuizID, rules); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save quiz draw rules",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Quiz draw rules saved successfully",
+		Data:    rules,
+	})
+}
+
+// StartQuiz opens an attempt on a quiz, its deadline is set by the server from the time limit of the quiz.
+// The open attempt of the user is returned while it is still in time. The questions of a new attempt
+// are drawn by the draw rules of the quiz and returned with the attempt.
+func (ctr *QuizController) StartQuiz(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	startParams := new(param.StartQuizParams)
+
+	if err := c.Bind(startParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(startParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	quiz, err := ctr.QuizRepo.GetQuizByID(startParams.QuizID)
+	if err != nil {
+		ctr.Logger.Errorf("Quiz not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz not found",
+		})
+	}
+
+	attempts, err := ctr.QuizRepo.GetQuizAttemptsByUser(userProfile.ID, quiz.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to get attempt history",
+		})
+	}
+
+	now := utils.TimeNowUTC()
+	openInTime := false
+	for i, attempt := range attempts {
+		if attempt.Status != cf.AttemptStatusInProgress {
+			continue
+		}
+
+		if !attemptExpired(attempt, now) {
+			openInTime = true
+			break
+		}
+
+		if _, err := closeExpiredAttempt(ctr.QuizRepo, ctr.XapiRepo, userProfile, quiz, attempt); err != nil {
+			ctr.Logger.Errorf("Failed to close expired quiz attempt %d: %v", attempt.ID, err)
+		}
+		attempts[i].Status = cf.AttemptStatusExpired
+		attempts[i].Score = 0
+	}
+
+	// An attempt still open is resumed, the policy applies to new attempts only
+	if !openInTime {
+		if reason := AttemptBlockedReason(quiz, ClosedAttempts(attempts), now); reason != "" {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: reason,
+				Data:    attemptPolicyData(quiz, EvaluateAttempts(quiz, attempts, nil)),
+			})
+		}
+	}
+
+	attemptQuestions := []m.QuizAttemptQuestion{}
+	if !openInTime {
+		attemptQuestions, err = DrawAttemptQuestions(ctr.QuizRepo, ctr.QuestionBankRepo, quiz)
+		if err != nil {
+			ctr.Logger.Errorf("Failed to draw questions of quiz %d: %v", quiz.ID, err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to fetch quiz questions",
+			})
+		}
+
+		if len(attemptQuestions) == 0 {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "This quiz has no questions",
+			})
+		}
+	}
+
+	attempt := &m.QuizAttempt{
+		UserID:           userProfile.ID,
+		QuizID:           quiz.ID,
+		Status:           cf.AttemptStatusInProgress,
+		StartedAt:        now,
+		ShuffleQuestions: quiz.ShuffleQuestions,
+		ShuffleOptions:   quiz.ShuffleOptions,
+	}
+	if quiz.TimeLimit > 0 {
+		attempt.DeadlineAt = now.Add(time.Duration(quiz.TimeLimit) * time.Minute)
+	}
+
+	resumed, err := ctr.QuizRepo.StartQuizAttempt(attempt, attemptQuestions, submitDeadline(now))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to start quiz attempt",
+		})
+	}
+
+	message := "Quiz attempt started"
+	if resumed {
+		message = "Quiz attempt resumed"
+
+		attemptQuestions, err = ctr.QuizRepo.GetAttemptQuestions(attempt.ID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to fetch quiz questions",
+			})
+		}
+	}
+
+	ShuffleAttemptQuestions(quiz, *attempt, attemptQuestions)
+
+	attemptResponse := newAttemptResponse(*attempt, quiz, resumed)
+	attemptResponse.Questions = newAttemptQuestionsResponse(attemptQuestions)
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: message,
+		Data:    attemptResponse,
+	})
+}
+
+// SubmitFullQuiz submits all answers of an open attempt at once and grades them against the questions
+// shown in the attempt, answers sent after the time limit of the attempt close it as expired
+func (ctr *QuizController) SubmitFullQuiz(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	submitParams := new(param.SubmitFullQuizParams)
+
+	if err := c.Bind(submitParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(submitParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	attempt, err := ctr.QuizRepo.GetQuizAttemptByID(submitParams.AttemptID)
+	if err != nil || attempt.UserID != userProfile.ID || attempt.QuizID != submitParams.QuizID {
+		ctr.Logger.Errorf("Quiz attempt %d not found: %v", submitParams.AttemptID, err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz attempt not found",
+		})
+	}
+
+	if attempt.Status != cf.AttemptStatusInProgress {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "This quiz attempt is already closed",
+		})
+	}
+
+	currentAttempt := attempt.Attempt
+
+	quiz, err := ctr.QuizRepo.GetQuizByID(submitParams.QuizID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch quiz details: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch quiz details",
+		})
+	}
+
+	attempts, err := ctr.QuizRepo.GetQuizAttemptsByUser(userProfile.ID, quiz.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to get attempt history",
+		})
+	}
+
+	if quiz.MaxAttempts > 0 && len(ClosedAttempts(attempts)) >= quiz.MaxAttempts {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: fmt.Sprintf("All %d attempts of this quiz are used", quiz.MaxAttempts),
+		})
+	}
+
+	now := utils.TimeNowUTC()
+	if attemptExpired(attempt, now) {
+		if _, err := closeExpiredAttempt(ctr.QuizRepo, ctr.XapiRepo, userProfile, quiz, attempt); err != nil {
+			ctr.Logger.Errorf("Failed to close expired quiz attempt %d: %v", attempt.ID, err)
+		}
+
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "The time limit of this quiz attempt is over, the attempt was closed",
+		})
+	}
+
+	questionSet, err := attemptQuestionSet(ctr.QuizRepo, attempt.ID, quiz)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch quiz questions: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch quiz questions",
+		})
+	}
+
+	// Each question of the attempt is answered at most once, a repeated answer would be scored again
+	answeredQuestions := map[int]bool{}
+	for _, answer := range submitParams.Answers {
+		if _, exists := questionSet[answer.QuestionID]; !exists {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: fmt.Sprintf("Question %d is not part of this quiz attempt", answer.QuestionID),
+			})
+		}
+		if answeredQuestions[answer.QuestionID] {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: fmt.Sprintf("Question %d is answered more than once", answer.QuestionID),
+			})
+		}
+		answeredQuestions[answer.QuestionID] = true
+	}
+
+	totalScore := 0.0
+	submissionDetails := []map[string]interface{}{}
+	essaySubmissions := []map[string]interface{}{}
+	hasEssayQuestions := false
+	submissions := []m.QuizSubmission{}
+
+	for _, answer := range submitParams.Answers {
+		attemptQuestion := questionSet[answer.QuestionID]
+		question := attemptQuestion.QuizQuestion
+
+		matchAnswers := make([]m.MatchAnswer, len(answer.MatchAnswers))
+		for i, matchAnswer := range answer.MatchAnswers {
+			matchAnswers[i] = m.MatchAnswer{PairID: matchAnswer.PairID, MatchID: matchAnswer.MatchID}
+		}
+
+		grade, graded := GradeAnswer(question, SubmittedAnswer{answer.AnswerText, answer.SelectedAnswerIds, matchAnswers}, attemptQuestion.Points)
+		if !graded {
+			ctr.Logger.Warnf("No scorer for question type %d of question %d", question.QuestionType, question.ID)
+			continue
+		}
+
+		// Answers that are not graded on submit are scored on review
+		score := grade.Score
+		reviewed := grade.Reviewed
+		if !reviewed {
+			hasEssayQuestions = true
+		}
+
+		submissions = append(submissions, m.QuizSubmission{
+			UserID:            userProfile.ID,
+			QuizID:            submitParams.QuizID,
+			QuizQuestionID:    answer.QuestionID,
+			AnswerText:        answer.AnswerText,
+			SelectedAnswerIds: answer.SelectedAnswerIds,
+			MatchAnswers:      matchAnswers,
+			Score:             score,
+			Attempt:           currentAttempt,
+			Reviewed:          reviewed,
+			SubmittedAt:       now.Format(cf.FormatDate),
+		})
+
+		if reviewed {
+			submissionDetail := map[string]interface{}{
+				"question_id":      answer.QuestionID,
+				"score":            score,
+				"is_correct":       grade.IsCorrect,
+				"selected_answers": answer.SelectedAnswerIds,
+				"match_answers":    matchAnswers,
+				"correct_answers":  grade.CorrectAnswerIDs,
+				"correct_answer":   grade.CorrectAnswer,
+				"reviewed":         reviewed,
+				"attempt":          currentAttempt,
+				"explanation":      question.Explanation,
+				"points":           attemptQuestion.Points,
+				"scoring_strategy": question.ScoringStrategy,
+			}
+			submissionDetails = append(submissionDetails, submissionDetail)
+		} else {
+			essaySubmission := map[string]interface{}{
+				"question_id":   answer.QuestionID,
+				"question_type": question.QuestionType,
+				"points":        attemptQuestion.Points,
+				"reviewed":      reviewed,
+				"attempt":       currentAttempt,
+			}
+			essaySubmissions = append(essaySubmissions, essaySubmission)
+		}
+
+		totalScore += score
+	}
+
+	attempt.SubmittedAt = now
+	attempt.Score = totalScore
+	submitted, err := ctr.QuizRepo.SubmitQuizAttempt(&attempt, submissions, submitDeadline(now))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save quiz submissions",
+		})
+	}
+	if !submitted {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "This quiz attempt is already closed",
+		})
+	}
+
+	passThreshold := PassThreshold(quiz)
+	passed := totalScore >= passThreshold
+
+	responseData := map[string]interface{}{
+		"quiz_id":        submitParams.QuizID,
+		"total_score":    totalScore,
+		"max_score":      quiz.TotalScore,
+		"passed":         passed,
+		"pass_threshold": passThreshold,
+		"submissions":    submissionDetails,
+		"attempt":        currentAttempt,
+	}
+
+	if quizResult, err := UserAttemptResult(ctr.QuizRepo, userProfile.ID, quiz); err != nil {
+		ctr.Logger.Errorf("Failed to evaluate attempts of quiz %d: %v", quiz.ID, err)
+	} else {
+		responseData["attempt_policy"] = attemptPolicyData(quiz, quizResult)
+	}
+
+	// Essay answers are scored on review, the attempt passes or fails once they are reviewed,
+	// until then the score is the one of the auto-graded answers
+	if hasEssayQuestions {
+		responseData["pending_review"] = true
+		responseData["auto_graded_score"] = totalScore
+		responseData["essay_submissions"] = essaySubmissions
+	}
+
+	actor := xapi.NewAgent(userProfile.Email, userProfile.UserProfile.FirstName+" "+userProfile.UserProfile.LastName)
+	quizActivity := xapi.NewActivity(xapi.ActivityTypeAssessment, quiz.ID, quiz.Title)
+	attemptExtensions := map[string]interface{}{xapi.ExtensionAttempt: currentAttempt}
+
+	statement := xapi.NewStatement(actor, xapi.VerbAttempted, quizActivity)
+	statement.Context = &xapi.Context{Extensions: attemptExtensions}
+	ctr.recordStatement(userProfile.ID, statement)
+
+	if !hasEssayQuestions {
+		verb := xapi.VerbFailed
+		if passed {
+			verb = xapi.VerbPassed
+		}
+
+		statement = xapi.NewStatement(actor, verb, quizActivity)
+		statement.Result = xapi.NewScoreResult(totalScore, quiz.TotalScore, &passed, true)
+		statement.Context = &xapi.Context{Extensions: attemptExtensions}
+		ctr.recordStatement(userProfile.ID, statement)
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Quiz answers submitted successfully",
+		Data:    responseData,
+	})
+}
+
+// GetQuizResults retrieves quiz results for a user
+func (ctr *QuizController) GetQuizResults(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	getResultsParams := new(param.GetQuizResultsParams)
+
+	if err := c.Bind(getResultsParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(getResultsParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	targetUserID := userProfile.ID
+	if userProfile.RoleID == cf.ManagerRoleID && getResultsParams.UserID > 0 {
+		targetUserID = getResultsParams.UserID
+	}
+
+	maxAttempt, err := ctr.QuizRepo.GetMaxQuizAttempt(targetUserID, getResultsParams.QuizID)
+	if err != nil {
+		ctr.Logger.Errorf("Error getting max attempt: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to get attempt history",
+		})
+	}
+
+	if maxAttempt == 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.SuccessResponseCode,
+			Message: "No quiz attempts found",
+			Data: map[string]interface{}{
+				"passed": false,
+				"results": map[string]interface{}{
+					"quiz_id": getResultsParams.QuizID,
+					"answers": []map[string]interface{}{},
+				},
+			},
+		})
+	}
+
+	quiz, err := ctr.QuizRepo.GetQuizByID(getResultsParams.QuizID)
+	if err != nil {
+		ctr.Logger.Errorf("Quiz not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Quiz not found",
+		})
+	}
+
+	submissions, err := ctr.QuizRepo.GetQuizSubmissionsByUser(targetUserID, getResultsParams.QuizID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch quiz submissions: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch quiz submissions",
+		})
+	}
+
+	latestAttemptSubmissions := []m.QuizSubmission{}
+	latestAttemptID := 0
+	for _, submission := range submissions {
+		if submission.Attempt == maxAttempt {
+			latestAttemptSubmissions = append(latestAttemptSubmissions, submission)
+			latestAttemptID = submission.QuizAttemptID
+		}
+	}
+
+	// The latest attempt is graded against the questions shown in it
+	questionSet, err := attemptQuestionSet(ctr.QuizRepo, latestAttemptID, quiz)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch quiz questions: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch quiz questions",
+		})
+	}
+
+	// Answers are listed in the order the questions were shown in the attempt
+	shownQuestions := make([]m.QuizAttemptQuestion, 0, len(questionSet))
+	for _, attemptQuestion := range questionSet {
+		shownQuestions = append(shownQuestions, attemptQuestion)
+	}
+	sort.Slice(shownQuestions, func(i, j int) bool {
+		return shownQuestions[i].Position < shownQuestions[j].Position
+	})
+	// The order follows the shuffle options of the quiz when the attempt started, not the current ones
+	shownAttempt := NewQuizAttempt(quiz, targetUserID, maxAttempt)
+	if latestAttemptID > 0 {
+		shownAttempt, err = ctr.QuizRepo.GetQuizAttemptByID(latestAttemptID)
+		if err != nil {
+			ctr.Logger.Errorf("Failed to fetch quiz attempt %d: %v", latestAttemptID, err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to fetch quiz attempt",
+			})
+		}
+	}
+	ShuffleAttemptQuestions(quiz, shownAttempt, shownQuestions)
+
+	shownPositions := make(map[int]int, len(shownQuestions))
+	for _, attemptQuestion := range shownQuestions {
+		shownPositions[attemptQuestion.QuizQuestionID] = attemptQuestion.Position
+	}
+	sort.SliceStable(latestAttemptSubmissions, func(i, j int) bool {
+		return shownPositions[latestAttemptSubmissions[i].QuizQuestionID] < shownPositions[latestAttemptSubmissions[j].QuizQuestionID]
+	})
+
+	totalScore := 0.0
+	autoGradedScore := 0.0
+	hasEssayQuestions := false
+	allEssaysReviewed := true
+	answers := []map[string]interface{}{}
+
+	for _, submission := range latestAttemptSubmissions {
+		attemptQuestion, exists := questionSet[submission.QuizQuestionID]
+		if !exists {
+			continue
+		}
+		question := attemptQuestion.QuizQuestion
+
+		grade, graded := GradeAnswer(question, SubmittedAnswer{submission.AnswerText, submission.SelectedAnswerIds, submission.MatchAnswers}, attemptQuestion.Points)
+		if !graded {
+			continue
+		}
+
+		if !grade.Reviewed {
+			hasEssayQuestions = true
+			if !submission.Reviewed {
+				allEssaysReviewed = false
+			}
+
+			answerData := map[string]interface{}{
+				"question_id":   submission.QuizQuestionID,
+				"position":      shownPositions[submission.QuizQuestionID],
+				"question_type": question.QuestionType,
+				"answer_text":   submission.AnswerText,
+				"points":        attemptQuestion.Points,
+				"reviewed":      submission.Reviewed,
+			}
+			if submission.Reviewed {
+				answerData["score"] = submission.Score
+				answerData["feedback"] = submission.Feedback
+			}
+			answers = append(answers, answerData)
+		} else {
+			autoGradedScore += submission.Score
+
+			// The score is the one awarded on submit, the scoring strategy explains partial credit
+			answerData := map[string]interface{}{
+				"question_id":         submission.QuizQuestionID,
+				"position":            shownPositions[submission.QuizQuestionID],
+				"question_type":       question.QuestionType,
+				"answer_text":         submission.AnswerText,
+				"selected_answer_ids": submission.SelectedAnswerIds,
+				"match_answers":       submission.MatchAnswers,
+				"is_correct":          grade.IsCorrect,
+				"correct_answer_ids":  grade.CorrectAnswerIDs,
+				"correct_answer":      grade.CorrectAnswer,
+				"explanation":         question.Explanation,
+				"points":              attemptQuestion.Points,
+				"score":               submission.Score,
+				"scoring_strategy":    question.ScoringStrategy,
+			}
+			answers = append(answers, answerData)
+		}
+
+		totalScore += submission.Score
+	}
+
+	quizResult, err := UserAttemptResult(ctr.QuizRepo, targetUserID, quiz)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to get attempt history",
+		})
+	}
+
+	// The latest attempt is detailed, passing follows the score policy and the pass mark of the quiz
+	passed := quizResult.Passed
+
+	resultsData := map[string]interface{}{
+		"quiz_id":        getResultsParams.QuizID,
+		"answers":        answers,
+		"attempt":        maxAttempt,
+		"pass_threshold": PassThreshold(quiz),
+		"pending_review": quizResult.PendingReview,
+		"attempt_policy": attemptPolicyData(quiz, quizResult),
 	}
 
 	if !hasEssayQuestions {
-		// Case 1: Multiple choice quiz
+		// Case 1: Auto-graded quiz
 		resultsData["total_score"] = quiz.TotalScore
 		resultsData["user_score"] = totalScore
 	} else if hasEssayQuestions && !allEssaysReviewed {
-		// Case 2: Essay quiz not yet reviewed
-		// No additional fields needed
+		// Case 2: Essays not yet reviewed, the auto-graded answers of a mixed quiz are already scored
+		resultsData["total_score"] = quiz.TotalScore
+		resultsData["auto_graded_score"] = autoGradedScore
 	} else if hasEssayQuestions && allEssaysReviewed {
-		// Case 3: Essay quiz with review and feedback
+		// Case 3: Essays reviewed, the score combines the auto-graded answers and the reviewed essays
+		resultsData["total_score"] = quiz.TotalScore
 		resultsData["user_score"] = totalScore
 
 		for _, submission := range latestAttemptSubmissions {
@@ -816,7 +1710,31 @@ func (ctr *QuizController) ReviewEssaySubmission(c echo.Context) error {
 		})
 	}
 
-	err := ctr.QuizRepo.ReviewEssaySubmission(
+	submission, err := ctr.QuizRepo.GetQuizSubmissionByID(reviewParams.SubmissionID)
+	if err != nil {
+		ctr.Logger.Errorf("Essay submission %d not found: %v", reviewParams.SubmissionID, err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Essay submission not found",
+		})
+	}
+
+	// The score is out of what the question is worth in the attempt the essay was answered in
+	points := submission.QuizQuestion.Weight * submission.Quiz.TotalScore
+	if questionSet, err := attemptQuestionSet(ctr.QuizRepo, submission.QuizAttemptID, submission.Quiz); err == nil {
+		if attemptQuestion, exists := questionSet[submission.QuizQuestionID]; exists {
+			points = attemptQuestion.Points
+		}
+	}
+
+	if reviewParams.Score < 0 || reviewParams.Score > points {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: fmt.Sprintf("The score must be between 0 and %g", points),
+		})
+	}
+
+	err = ctr.QuizRepo.ReviewEssaySubmission(
 		reviewParams.SubmissionID,
 		reviewParams.Score,
 		reviewParams.Feedback,
@@ -831,8 +1749,138 @@ func (ctr *QuizController) ReviewEssaySubmission(c echo.Context) error {
 		})
 	}
 
+	statement := xapi.NewStatement(
+		xapi.NewAgent(submission.User.Email, ""),
+		xapi.VerbScored,
+		xapi.NewActivity(xapi.ActivityTypeQuestion, submission.QuizQuestionID, ""),
+	).WithParent(xapi.NewActivity(xapi.ActivityTypeAssessment, submission.QuizID, submission.Quiz.Title))
+	statement.Result = xapi.NewScoreResult(reviewParams.Score, points, nil, true)
+	statement.Result.Response = reviewParams.Feedback
+
+	instructor := xapi.NewAgent(userProfile.Email, userProfile.UserProfile.FirstName+" "+userProfile.UserProfile.LastName)
+	statement.Context.Instructor = &instructor
+	statement.Context.Extensions = map[string]interface{}{xapi.ExtensionAttempt: submission.Attempt}
+
+	ctr.recordStatement(submission.UserID, statement)
+	ctr.recordReviewedAttempt(submission)
+
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
 		Message: "Essay submission reviewed successfully",
 	})
 }
+
+// recordReviewedAttempt records whether the attempt of a reviewed essay answer passed, once every answer of the attempt is reviewed
+func (ctr *QuizController) recordReviewedAttempt(submission m.QuizSubmission) {
+	if submission.QuizAttemptID == 0 {
+		return
+	}
+
+	submissions, err := ctr.QuizRepo.GetQuizSubmissionsByUser(submission.UserID, submission.QuizID)
+	if err != nil {
+		return
+	}
+
+	for _, attemptSubmission := range submissions {
+		if attemptSubmission.QuizAttemptID == submission.QuizAttemptID && !attemptSubmission.Reviewed {
+			return
+		}
+	}
+
+	attempt, err := ctr.QuizRepo.GetQuizAttemptByID(submission.QuizAttemptID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch quiz attempt %d: %v", submission.QuizAttemptID, err)
+		return
+	}
+
+	passed := attempt.Score >= PassThreshold(submission.Quiz)
+	verb := xapi.VerbFailed
+	if passed {
+		verb = xapi.VerbPassed
+	}
+
+	statement := xapi.NewStatement(
+		xapi.NewAgent(submission.User.Email, ""),
+		verb,
+		xapi.NewActivity(xapi.ActivityTypeAssessment, submission.QuizID, submission.Quiz.Title),
+	)
+	statement.Result = xapi.NewScoreResult(attempt.Score, submission.Quiz.TotalScore, &passed, true)
+	statement.Context = &xapi.Context{Extensions: map[string]interface{}{xapi.ExtensionAttempt: attempt.Attempt}}
+
+	ctr.recordStatement(submission.UserID, statement)
+	// A passed attempt completes the quiz items of the trainee, as completed SCORM items do
+	if passed {
+		if err := ctr.Progression.AdvancePastQuiz(submission.UserID, submission.QuizID); err != nil {
+			ctr.Logger.Errorf("Failed to update user progress after essay review: %v", err)
+		}
+	}
+}
+
+// recordStatement stores an xAPI statement, logging failures without interrupting the request
+func (ctr *QuizController) recordStatement(userID int, statement *xapi.Statement) {
+	if err := ctr.XapiRepo.RecordStatement(userID, statement); err != nil {
+		ctr.Logger.Errorf("Failed to record xAPI statement: %v", err)
+	}
+}
+
+// answerQuestion returns the multiple choice question of an answer,
+// or the status and the message to respond with when there is none
+func (ctr *QuizController) answerQuestion(answerID int) (m.QuizQuestion, int, string) {
+	answer, err := ctr.QuizRepo.GetQuizAnswerByID(answerID)
+	if err != nil {
+		ctr.Logger.Errorf("Quiz answer %d not found: %v", answerID, err)
+		return m.QuizQuestion{}, http.StatusNotFound, "Quiz answer not found"
+	}
+
+	question, err := ctr.QuizRepo.GetQuizQuestionByID(answer.QuizQuestionID)
+	if err != nil {
+		ctr.Logger.Errorf("Quiz question %d not found: %v", answer.QuizQuestionID, err)
+		return m.QuizQuestion{}, http.StatusNotFound, "Quiz question not found"
+	}
+
+	// The options of the other question types are generated from their answer key
+	if question.QuestionType != cf.QuestionTypeMultipleChoice {
+		return m.QuizQuestion{}, http.StatusOK, "Answers can only be edited on multiple choice questions, update the question instead"
+	}
+
+	return question, http.StatusOK, ""
+}
+
+// saveEditedQuestion saves an edited question with its answers and responds with the saved question,
+// its version tells whether it replaced a version shown in attempts
+func (ctr *QuizController) saveEditedQuestion(c echo.Context, question m.QuizQuestion, answers []m.QuizAnswer, message string) error {
+	if err := ctr.QuizRepo.SaveQuizQuestion(&question, answers); err != nil {
+		ctr.Logger.Errorf("Failed to save quiz question %d: %v", question.ID, err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save quiz question",
+		})
+	}
+	question.Answers = answers
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: message,
+		Data:    question,
+	})
+}
+
+// sameIDs tells whether ids lists each of currentIDs once, in any order
+func sameIDs(ids []int, currentIDs []int) bool {
+	if len(ids) != len(currentIDs) {
+		return false
+	}
+
+	remaining := make(map[int]bool, len(currentIDs))
+	for _, id := range currentIDs {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+
+	return true
+}
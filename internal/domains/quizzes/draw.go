@@ -0,0 +1,224 @@
+package quizzes
+
+import (
+	"fmt"
+	"math/rand"
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	"orientation-training-api/internal/interfaces/response"
+	m "orientation-training-api/internal/models"
+)
+
+// ValidateDrawRules returns why the draw rules of a quiz are invalid, empty when they are valid.
+// The questions of a bank must cover the rules of each difficulty and the rules of any difficulty on top.
+func ValidateDrawRules(questionBankRepo rp.QuestionBankRepository, rules []param.QuizDrawRuleParam) (string, error) {
+	drawnByBank := map[int]int{}
+	drawnByDifficulty := map[int]map[int]int{}
+	for _, rule := range rules {
+		if rule.QuestionCount <= 0 {
+			return "question_count must be positive", nil
+		}
+		if _, ok := cf.DifficultyLabels[rule.Difficulty]; rule.Difficulty != 0 && !ok {
+			return fmt.Sprintf("Difficulty %d does not exist", rule.Difficulty), nil
+		}
+
+		if drawnByDifficulty[rule.QuestionBankID] == nil {
+			drawnByDifficulty[rule.QuestionBankID] = map[int]int{}
+		}
+		drawnByBank[rule.QuestionBankID] += rule.QuestionCount
+		if rule.Difficulty != 0 {
+			drawnByDifficulty[rule.QuestionBankID][rule.Difficulty] += rule.QuestionCount
+		}
+	}
+
+	for questionBankID, drawnCount := range drawnByBank {
+		questionBank, err := questionBankRepo.GetQuestionBankByID(questionBankID)
+		if err != nil {
+			return fmt.Sprintf("Question bank %d not found", questionBankID), nil
+		}
+
+		questions, err := questionBankRepo.GetBankQuestions(questionBankID, 0)
+		if err != nil {
+			return "", err
+		}
+		if len(questions) < drawnCount {
+			return fmt.Sprintf("Question bank %s has %d questions, %d are drawn", questionBank.Name, len(questions), drawnCount), nil
+		}
+
+		questionsByDifficulty := map[int]int{}
+		for _, question := range questions {
+			questionsByDifficulty[question.Difficulty]++
+		}
+		for difficulty, drawnCount := range drawnByDifficulty[questionBankID] {
+			if questionsByDifficulty[difficulty] < drawnCount {
+				return fmt.Sprintf("Question bank %s has %d %s questions, %d are drawn", questionBank.Name,
+					questionsByDifficulty[difficulty], cf.DifficultyLabels[difficulty], drawnCount), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// DrawAttemptQuestions assembles the question set of a new attempt on a quiz: the questions of the quiz followed by
+// questions drawn at random by its draw rules, rules of a difficulty draw before rules of any difficulty.
+// A question is worth its share of the total score by weight, so the set is worth the total score after questions are deleted.
+func DrawAttemptQuestions(quizRepo rp.QuizRepository, questionBankRepo rp.QuestionBankRepository, quiz m.Quiz) ([]m.QuizAttemptQuestion, error) {
+	questions, err := quizRepo.GetQuizQuestionsWithAnswers(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := quizRepo.GetDrawRules(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderedRules := []m.QuizDrawRule{}
+	for _, rule := range rules {
+		if rule.Difficulty != 0 {
+			orderedRules = append(orderedRules, rule)
+		}
+	}
+	for _, rule := range rules {
+		if rule.Difficulty == 0 {
+			orderedRules = append(orderedRules, rule)
+		}
+	}
+
+	drawn := map[int]bool{}
+	for _, question := range questions {
+		drawn[question.ID] = true
+	}
+
+	for _, rule := range orderedRules {
+		pool, err := questionBankRepo.GetBankQuestions(rule.QuestionBankID, rule.Difficulty)
+		if err != nil {
+			return nil, err
+		}
+
+		drawnCount := 0
+		for _, i := range rand.Perm(len(pool)) {
+			if drawnCount == rule.QuestionCount {
+				break
+			}
+			if drawn[pool[i].ID] {
+				continue
+			}
+			drawn[pool[i].ID] = true
+			questions = append(questions, pool[i])
+			drawnCount++
+		}
+	}
+
+	totalWeight := 0.0
+	for _, question := range questions {
+		totalWeight += question.Weight
+	}
+
+	attemptQuestions := make([]m.QuizAttemptQuestion, len(questions))
+	for i, question := range questions {
+		attemptQuestions[i] = m.QuizAttemptQuestion{
+			QuizQuestionID: question.ID,
+			Position:       i + 1,
+			Points:         questionPoints(question.Weight, totalWeight, quiz.TotalScore),
+			QuizQuestion:   question,
+		}
+	}
+
+	return attemptQuestions, nil
+}
+
+// attemptQuestionSet returns the questions shown in an attempt by ID, attempts without a question set
+// fall back to the questions of the quiz
+func attemptQuestionSet(quizRepo rp.QuizRepository, attemptID int, quiz m.Quiz) (map[int]m.QuizAttemptQuestion, error) {
+	questionSet := map[int]m.QuizAttemptQuestion{}
+
+	if attemptID > 0 {
+		attemptQuestions, err := quizRepo.GetAttemptQuestions(attemptID)
+		if err != nil {
+			return nil, err
+		}
+		for _, attemptQuestion := range attemptQuestions {
+			questionSet[attemptQuestion.QuizQuestionID] = attemptQuestion
+		}
+		if len(questionSet) > 0 {
+			return questionSet, nil
+		}
+	}
+
+	questions, err := quizRepo.GetQuizQuestionsWithAnswers(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, attemptQuestion := range quizAttemptQuestions(quiz, questions) {
+		questionSet[attemptQuestion.QuizQuestionID] = attemptQuestion
+	}
+
+	return questionSet, nil
+}
+
+// quizAttemptQuestions returns the questions of a quiz as a question set in their order, without drawn questions
+func quizAttemptQuestions(quiz m.Quiz, questions []m.QuizQuestion) []m.QuizAttemptQuestion {
+	totalWeight := 0.0
+	for _, question := range questions {
+		totalWeight += question.Weight
+	}
+
+	attemptQuestions := make([]m.QuizAttemptQuestion, len(questions))
+	for i, question := range questions {
+		attemptQuestions[i] = m.QuizAttemptQuestion{
+			QuizQuestionID: question.ID,
+			Position:       i + 1,
+			Points:         questionPoints(question.Weight, totalWeight, quiz.TotalScore),
+			QuizQuestion:   question,
+		}
+	}
+
+	return attemptQuestions
+}
+
+// newAttemptQuestionsResponse returns the questions of an attempt without their correct answers
+func newAttemptQuestionsResponse(attemptQuestions []m.QuizAttemptQuestion) []response.AttemptQuestionResponse {
+	questionsResponse := make([]response.AttemptQuestionResponse, len(attemptQuestions))
+	for i, attemptQuestion := range attemptQuestions {
+		question := attemptQuestion.QuizQuestion
+		options, prompts := QuestionOptions(question)
+
+		questionsResponse[i] = response.AttemptQuestionResponse{
+			QuestionID:        question.ID,
+			Position:          attemptQuestion.Position,
+			QuestionType:      question.QuestionType,
+			QuestionText:      question.QuestionText,
+			IsMultipleCorrect: question.IsMultipleCorrect,
+			ScoringStrategy:   question.ScoringStrategy,
+			Points:            attemptQuestion.Points,
+			Answers:           newAttemptAnswersResponse(options),
+			Prompts:           newAttemptAnswersResponse(prompts),
+		}
+	}
+
+	return questionsResponse
+}
+
+// newAttemptAnswersResponse returns the options of a question shown in an attempt
+func newAttemptAnswersResponse(options []response.QuizOptionResponse) []response.AttemptAnswerResponse {
+	answers := make([]response.AttemptAnswerResponse, len(options))
+	for i, option := range options {
+		answers[i] = response.AttemptAnswerResponse{
+			AnswerID:   option.ID,
+			AnswerText: option.Text,
+		}
+	}
+
+	return answers
+}
+
+// questionPoints returns the share of the total score of a question by its weight in a question set
+func questionPoints(weight float64, totalWeight float64, totalScore float64) float64 {
+	if totalWeight <= 0 {
+		return weight * totalScore
+	}
+	return weight / totalWeight * totalScore
+}
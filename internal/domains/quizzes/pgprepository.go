@@ -1,12 +1,15 @@
 package quizzes
 
 import (
+	"errors"
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
 	param "orientation-training-api/internal/interfaces/requestparams"
 	m "orientation-training-api/internal/models"
+	"time"
 
 	"github.com/go-pg/pg/v9"
+	"github.com/go-pg/pg/v9/orm"
 	"github.com/labstack/echo/v4"
 )
 
@@ -117,10 +120,10 @@ func (repo *PgQuizRepository) GetQuizQuestionsWithAnswers(quizID int) ([]m.QuizQ
 	// First, fetch all questions for this quiz
 	var questions []m.QuizQuestion
 
-	err := repo.DB.Model(&questions).
-		Where("quiz_id = ?", quizID).
-		Where("deleted_at IS NULL").
-		Order("id ASC").
+	err := WithAnswerKeys(repo.DB.Model(&questions)).
+		Where("quiz_question.quiz_id = ?", quizID).
+		Where("quiz_question.deleted_at IS NULL").
+		Order("quiz_question.position ASC", "quiz_question.id ASC").
 		Select()
 
 	if err != nil {
@@ -134,7 +137,7 @@ func (repo *PgQuizRepository) GetQuizQuestionsWithAnswers(quizID int) ([]m.QuizQ
 		err := repo.DB.Model(&answers).
 			Where("quiz_question_id = ?", questions[i].ID).
 			Where("deleted_at IS NULL").
-			Order("id ASC").
+			Order("position ASC", "id ASC").
 			Select()
 
 		if err != nil {
@@ -148,7 +151,9 @@ func (repo *PgQuizRepository) GetQuizQuestionsWithAnswers(quizID int) ([]m.QuizQ
 	return questions, nil
 }
 
-// SaveQuizQuestion saves a quiz question and its answers
+// SaveQuizQuestion saves a quiz question and its answers in their order, the answers with an ID are updated
+// and the other answers of the question deleted. A question already shown in attempts is replaced by a new version
+// so that the attempts keep the question they were graded against, question.ID is then the ID of the new version.
 func (repo *PgQuizRepository) SaveQuizQuestion(question *m.QuizQuestion, answers []m.QuizAnswer) error {
 	// Begin transaction
 	tx, err := repo.DB.Begin()
@@ -158,8 +163,53 @@ func (repo *PgQuizRepository) SaveQuizQuestion(question *m.QuizQuestion, answers
 	}
 	defer tx.Rollback()
 
+	if question.ID > 0 {
+		existingQuestion := m.QuizQuestion{}
+		err = tx.Model(&existingQuestion).
+			Where("id = ?", question.ID).
+			Where("deleted_at IS NULL").
+			First()
+		if err != nil {
+			repo.Logger.Errorf("Error fetching question with ID %d: %v", question.ID, err)
+			return err
+		}
+
+		if question.Position == 0 {
+			question.Position = existingQuestion.Position
+		}
+		question.Version = existingQuestion.Version
+
+		shown, err := questionShownInAttempts(tx, question.ID)
+		if err != nil {
+			repo.Logger.Errorf("Error checking attempts of question %d: %v", question.ID, err)
+			return err
+		}
+
+		// The shown version is kept deleted with its answers and answer keys
+		if shown {
+			if _, err = tx.Model(&existingQuestion).WherePK().Delete(); err != nil {
+				repo.Logger.Errorf("Error replacing question with ID %d: %v", question.ID, err)
+				return err
+			}
+
+			question.BaseModel = cm.BaseModel{}
+			question.PreviousVersionID = existingQuestion.ID
+			question.Version++
+			for i := range answers {
+				answers[i].ID = 0
+			}
+		}
+	}
+
 	// Save question
 	if question.ID == 0 {
+		if question.Position == 0 {
+			if question.Position, err = nextQuestionPosition(tx, question); err != nil {
+				repo.Logger.Errorf("Error getting position of question: %v", err)
+				return err
+			}
+		}
+
 		// Create new question
 		_, err = tx.Model(question).Insert()
 	} else {
@@ -168,6 +218,9 @@ func (repo *PgQuizRepository) SaveQuizQuestion(question *m.QuizQuestion, answers
 			WherePK().
 			Where("deleted_at IS NULL").
 			Update()
+		if err == nil {
+			err = deleteAnswersNotKept(tx, question.ID, answers)
+		}
 	}
 
 	if err != nil {
@@ -175,26 +228,33 @@ func (repo *PgQuizRepository) SaveQuizQuestion(question *m.QuizQuestion, answers
 		return err
 	}
 
-	// Delete existing answers if updating
-	if question.ID > 0 {
-		_, err = tx.Model((*m.QuizAnswer)(nil)).
-			Where("quiz_question_id = ?", question.ID).
-			Delete()
+	// Save answers in their order
+	for i := range answers {
+		answers[i].QuizQuestionID = question.ID
+		answers[i].Position = i + 1
+		if answers[i].ID > 0 {
+			var res orm.Result
+			res, err = tx.Model(&answers[i]).
+				WherePK().
+				Where("quiz_question_id = ?", question.ID).
+				Where("deleted_at IS NULL").
+				Update()
+			if err == nil && res.RowsAffected() == 0 {
+				err = pg.ErrNoRows
+			}
+		} else {
+			_, err = tx.Model(&answers[i]).Insert()
+		}
 
 		if err != nil {
-			repo.Logger.Errorf("Error deleting existing answers: %v", err)
+			repo.Logger.Errorf("Error saving answer: %v", err)
 			return err
 		}
 	}
 
-	// Insert new answers
-	for i := range answers {
-		answers[i].QuizQuestionID = question.ID
-		_, err = tx.Model(&answers[i]).Insert()
-		if err != nil {
-			repo.Logger.Errorf("Error inserting answer: %v", err)
-			return err
-		}
+	if err = saveAnswerKeys(tx, question); err != nil {
+		repo.Logger.Errorf("Error saving answer key of question: %v", err)
+		return err
 	}
 
 	// Commit transaction
@@ -207,12 +267,81 @@ func (repo *PgQuizRepository) SaveQuizQuestion(question *m.QuizQuestion, answers
 	return nil
 }
 
+// GetQuizQuestionByID returns a question with its answers and answer keys
+func (repo *PgQuizRepository) GetQuizQuestionByID(questionID int) (m.QuizQuestion, error) {
+	question := m.QuizQuestion{}
+
+	err := WithAnswerKeys(repo.DB.Model(&question)).
+		Relation("Answers", func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("deleted_at IS NULL").Order("position ASC", "id ASC"), nil
+		}).
+		Where("quiz_question.id = ?", questionID).
+		Where("quiz_question.deleted_at IS NULL").
+		First()
+
+	return question, err
+}
+
+// GetQuizAnswerByID returns an answer of a question
+func (repo *PgQuizRepository) GetQuizAnswerByID(answerID int) (m.QuizAnswer, error) {
+	answer := m.QuizAnswer{}
+
+	err := repo.DB.Model(&answer).
+		Where("id = ?", answerID).
+		Where("deleted_at IS NULL").
+		First()
+
+	return answer, err
+}
+
+// DeleteQuizQuestion soft deletes a question of a quiz, attempts that showed it keep it with its answers
+func (repo *PgQuizRepository) DeleteQuizQuestion(questionID int) error {
+	_, err := repo.DB.Model((*m.QuizQuestion)(nil)).
+		Set("deleted_at = NOW()").
+		Where("id = ?", questionID).
+		Where("quiz_id IS NOT NULL").
+		Where("deleted_at IS NULL").
+		Update()
+
+	if err != nil {
+		repo.Logger.Errorf("Error deleting question with ID %d: %v", questionID, err)
+	}
+
+	return err
+}
+
+// ReorderQuizQuestions sets the position of the questions of a quiz to their place in questionIDs,
+// attempts already started keep the order they were shown in
+func (repo *PgQuizRepository) ReorderQuizQuestions(quizID int, questionIDs []int) error {
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		for i, questionID := range questionIDs {
+			_, err := tx.Model((*m.QuizQuestion)(nil)).
+				Set("position = ?", i+1).
+				Set("updated_at = NOW()").
+				Where("id = ?", questionID).
+				Where("quiz_id = ?", quizID).
+				Where("deleted_at IS NULL").
+				Update()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error reordering questions of quiz %d: %v", quizID, err)
+	}
+
+	return err
+}
+
 // GetMaxQuizAttempt gets the maximum attempt number for a specific user and quiz
 func (repo *PgQuizRepository) GetMaxQuizAttempt(userID, quizID int) (int, error) {
 	var maxAttempt int
 
 	_, err := repo.DB.Query(pg.Scan(&maxAttempt),
-		"SELECT COALESCE(MAX(attempt), 0) FROM quiz_submissions WHERE user_id = ? AND quiz_id = ? AND deleted_at IS NULL",
+		"SELECT COALESCE(MAX(attempt), 0) FROM quiz_submissions WHERE user_id = ? AND quiz_id = ? AND archived_at IS NULL AND deleted_at IS NULL",
 		userID, quizID)
 
 	if err != nil {
@@ -235,6 +364,7 @@ func (repo *PgQuizRepository) SaveQuizSubmission(submission *m.QuizSubmission) e
 		Attempt:           submission.Attempt,
 		Reviewed:          submission.Reviewed,
 		SubmittedAt:       submission.SubmittedAt,
+		QuizAttemptID:     submission.QuizAttemptID,
 	}
 
 	_, err := repo.DB.Model(&temp).Insert()
@@ -255,6 +385,7 @@ func (repo *PgQuizRepository) GetQuizSubmissionsByUser(userID, quizID int) ([]m.
 		Where("user_id = ?", userID).
 		Where("quiz_id = ?", quizID).
 		Where("deleted_at IS NULL").
+		Where("archived_at IS NULL").
 		Order("created_at ASC").
 		Select()
 
@@ -278,6 +409,7 @@ func (repo *PgQuizRepository) CreateQuizWithQuestionsAndAnswers(quizData *param.
 			TotalScore: quizData.TotalScore,
 			TimeLimit:  quizData.TimeLimit,
 		}
+		ApplyPolicyParams(quiz, quizData.QuizPolicyParams)
 
 		if _, err := tx.Model(quiz).Insert(); err != nil {
 			repo.Logger.Errorf("Error creating quiz: %v", err)
@@ -286,34 +418,32 @@ func (repo *PgQuizRepository) CreateQuizWithQuestionsAndAnswers(quizData *param.
 
 		quizID = quiz.ID
 
-		for _, questionData := range quizData.Questions {
-			question := &m.QuizQuestion{
-				QuizID:            quiz.ID,
-				QuestionType:      quizData.QuestionType,
-				QuestionText:      questionData.QuestionText,
-				Weight:            questionData.Weight,
-				IsMultipleCorrect: questionData.AllowMultiple,
+		for i, questionData := range quizData.Questions {
+			question, answers, message := NewQuestionFromData(quizData, questionData)
+			if message != "" {
+				return errors.New(message)
 			}
+			question.QuizID = quiz.ID
+			question.Position = i + 1
 
-			if _, err := tx.Model(question).Insert(); err != nil {
+			if _, err := tx.Model(&question).Insert(); err != nil {
 				repo.Logger.Errorf("Error creating question: %v", err)
 				return err
 			}
 
-			if quizData.QuestionType == cf.QuesMultipleChoice {
-				for _, optionData := range questionData.Options {
-					answer := &m.QuizAnswer{
-						QuizQuestionID: question.ID,
-						AnswerText:     optionData.AnswersText,
-						IsCorrect:      optionData.IsCorrect,
-					}
-
-					if _, err := tx.Model(answer).Insert(); err != nil {
-						repo.Logger.Errorf("Error creating answer: %v", err)
-						return err
-					}
+			for j := range answers {
+				answers[j].QuizQuestionID = question.ID
+				answers[j].Position = j + 1
+				if _, err := tx.Model(&answers[j]).Insert(); err != nil {
+					repo.Logger.Errorf("Error creating answer: %v", err)
+					return err
 				}
 			}
+
+			if err := saveAnswerKeys(tx, &question); err != nil {
+				repo.Logger.Errorf("Error creating answer key: %v", err)
+				return err
+			}
 		}
 
 		return nil
@@ -340,6 +470,7 @@ func (repo *PgQuizRepository) GetEssaySubmissionsPendingReview() ([]m.QuizSubmis
 		Where("quiz_submission.reviewed = false").
 		Where("(quiz_submission.feedback IS NULL OR quiz_submission.feedback = '')").
 		Where("quiz_submission.deleted_at IS NULL").
+		Where("quiz_submission.archived_at IS NULL").
 		Order("quiz_submission.user_id ASC").
 		Order("quiz_submission.submitted_at DESC").
 		Select()
@@ -352,16 +483,30 @@ func (repo *PgQuizRepository) GetEssaySubmissionsPendingReview() ([]m.QuizSubmis
 	return submissions, nil
 }
 
-// ReviewEssaySubmission updates an essay submission with review information
+// ReviewEssaySubmission updates an essay submission with review information,
+// the score of its attempt is updated with the reviewed score
 func (repo *PgQuizRepository) ReviewEssaySubmission(submissionID int, score float64, feedback string, reviewerID int) error {
-	_, err := repo.DB.Model(&m.QuizSubmission{}).
-		Set("score = ?", score).
-		Set("feedback = ?", feedback).
-		Set("reviewed = true").
-		Set("reviewed_by = ?", reviewerID).
-		Where("id = ?", submissionID).
-		Where("deleted_at IS NULL").
-		Update()
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model(&m.QuizSubmission{}).
+			Set("score = ?", score).
+			Set("feedback = ?", feedback).
+			Set("reviewed = true").
+			Set("reviewed_by = ?", reviewerID).
+			Where("id = ?", submissionID).
+			Where("deleted_at IS NULL").
+			Update()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`UPDATE quiz_attempts SET score = (
+				SELECT COALESCE(SUM(qs.score), 0) FROM quiz_submissions AS qs
+				WHERE qs.quiz_attempt_id = quiz_attempts.id AND qs.deleted_at IS NULL
+			), updated_at = NOW()
+			WHERE id = (SELECT quiz_attempt_id FROM quiz_submissions WHERE id = ?)`, submissionID)
+
+		return err
+	})
 
 	if err != nil {
 		repo.Logger.Errorf("Error updating essay submission review: %v", err)
@@ -371,6 +516,20 @@ func (repo *PgQuizRepository) ReviewEssaySubmission(submissionID int, score floa
 	return nil
 }
 
+// GetQuizSubmissionByID returns a submission with its user, quiz and question
+func (repo *PgQuizRepository) GetQuizSubmissionByID(submissionID int) (m.QuizSubmission, error) {
+	submission := m.QuizSubmission{}
+	err := repo.DB.Model(&submission).
+		Relation("User").
+		Relation("Quiz").
+		Relation("QuizQuestion").
+		Where("quiz_submission.id = ?", submissionID).
+		Where("quiz_submission.deleted_at IS NULL").
+		First()
+
+	return submission, err
+}
+
 // GetPendingEssayReviewsCountForCourse returns the count of unreviewed essay quizzes for a specific course and user
 func (repo *PgQuizRepository) GetPendingEssayReviewsCountForCourse(userID int, courseID int) (int, error) {
 	count, err := repo.DB.Model(&m.QuizSubmission{}).
@@ -383,6 +542,7 @@ func (repo *PgQuizRepository) GetPendingEssayReviewsCountForCourse(userID int, c
 		Where("qc.question_type = ?", cf.QuesEssay).
 		Where("quiz_submission.reviewed = false").
 		Where("quiz_submission.deleted_at IS NULL").
+		Where("quiz_submission.archived_at IS NULL").
 		Where("qc.deleted_at IS NULL").
 		Where("q.deleted_at IS NULL").
 		Where("mi.deleted_at IS NULL").
@@ -396,3 +556,447 @@ func (repo *PgQuizRepository) GetPendingEssayReviewsCountForCourse(userID int, c
 
 	return count, nil
 }
+
+// ArchiveQuizSubmissions archives the attempts of a user on quizzes with their answers, they are kept but no longer count.
+// An open attempt is closed as expired. Attempt numbers keep increasing after an archive.
+func (repo *PgQuizRepository) ArchiveQuizSubmissions(userID int, quizIDs []int) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		return repo.ArchiveQuizSubmissionsWithTx(tx, userID, quizIDs)
+	})
+}
+
+// ArchiveQuizSubmissionsWithTx archives the attempts of a user on quizzes like ArchiveQuizSubmissions within a transaction
+func (repo *PgQuizRepository) ArchiveQuizSubmissionsWithTx(tx *pg.Tx, userID int, quizIDs []int) error {
+	if len(quizIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Model((*m.QuizSubmission)(nil)).
+		Set("archived_at = NOW()").
+		Set("updated_at = NOW()").
+		Where("user_id = ?", userID).
+		Where("quiz_id IN (?)", pg.In(quizIDs)).
+		Where("archived_at IS NULL").
+		Where("deleted_at IS NULL").
+		Update()
+
+	if err == nil {
+		_, err = tx.Model((*m.QuizAttempt)(nil)).
+			Set("archived_at = NOW()").
+			Set("status = CASE WHEN status = ? THEN ? ELSE status END", cf.AttemptStatusInProgress, cf.AttemptStatusExpired).
+			Set("updated_at = NOW()").
+			Where("user_id = ?", userID).
+			Where("quiz_id IN (?)", pg.In(quizIDs)).
+			Where("archived_at IS NULL").
+			Where("deleted_at IS NULL").
+			Update()
+	}
+
+	if err != nil {
+		repo.Logger.Errorf("Error archiving quiz submissions of user %d: %v", userID, err)
+	}
+
+	return err
+}
+
+// GetQuizAttemptsByUser gets the attempts of a user on a quiz that are not archived, in attempt order
+func (repo *PgQuizRepository) GetQuizAttemptsByUser(userID int, quizID int) ([]m.QuizAttempt, error) {
+	attempts := []m.QuizAttempt{}
+
+	err := repo.DB.Model(&attempts).
+		Where("user_id = ?", userID).
+		Where("quiz_id = ?", quizID).
+		Where("archived_at IS NULL").
+		Where("deleted_at IS NULL").
+		Order("attempt ASC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching quiz attempts for user %d and quiz %d: %v", userID, quizID, err)
+	}
+
+	return attempts, err
+}
+
+// StartQuizAttempt opens a new attempt of a user on a quiz, the open attempt of the user is returned
+// instead while it is still in time and reports true. The attempt number is assigned under a lock
+// on the user and the quiz so that concurrent starts never share a number.
+// An open attempt with a deadline before expiredBefore is closed as expired first.
+// The questions are saved as the question set of a new attempt, a resumed attempt keeps its own.
+func (repo *PgQuizRepository) StartQuizAttempt(attempt *m.QuizAttempt, questions []m.QuizAttemptQuestion, expiredBefore time.Time) (bool, error) {
+	resumed := false
+
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", attempt.UserID, attempt.QuizID); err != nil {
+			return err
+		}
+
+		openAttempt := m.QuizAttempt{}
+		err := tx.Model(&openAttempt).
+			Where("user_id = ?", attempt.UserID).
+			Where("quiz_id = ?", attempt.QuizID).
+			Where("status = ?", cf.AttemptStatusInProgress).
+			Where("deleted_at IS NULL").
+			First()
+
+		if err == nil {
+			if openAttempt.DeadlineAt.IsZero() || !openAttempt.DeadlineAt.Before(expiredBefore) {
+				*attempt = openAttempt
+				resumed = true
+				return nil
+			}
+
+			_, err = tx.Model((*m.QuizAttempt)(nil)).
+				Set("status = ?", cf.AttemptStatusExpired).
+				Set("score = 0").
+				Set("updated_at = NOW()").
+				Where("id = ?", openAttempt.ID).
+				Update()
+		}
+		if err != nil && err != pg.ErrNoRows {
+			return err
+		}
+
+		_, err = tx.QueryOne(pg.Scan(&attempt.Attempt),
+			"SELECT COALESCE(MAX(attempt), 0) + 1 FROM quiz_attempts WHERE user_id = ? AND quiz_id = ?",
+			attempt.UserID, attempt.QuizID)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Model(attempt).Insert(); err != nil {
+			return err
+		}
+
+		for i := range questions {
+			questions[i].QuizAttemptID = attempt.ID
+		}
+		if len(questions) > 0 {
+			_, err = tx.Model(&questions).Insert()
+		}
+
+		return err
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error starting attempt of user %d on quiz %d: %v", attempt.UserID, attempt.QuizID, err)
+	}
+
+	return resumed, err
+}
+
+// GetQuizAttemptByID fetches a quiz attempt by ID
+func (repo *PgQuizRepository) GetQuizAttemptByID(attemptID int) (m.QuizAttempt, error) {
+	attempt := m.QuizAttempt{}
+
+	err := repo.DB.Model(&attempt).
+		Where("id = ?", attemptID).
+		Where("deleted_at IS NULL").
+		First()
+
+	return attempt, err
+}
+
+// SubmitQuizAttempt closes an open attempt with its score and saves its answers in a single transaction.
+// It reports false without saving anything when the attempt is already closed or its deadline is before expiredBefore.
+func (repo *PgQuizRepository) SubmitQuizAttempt(attempt *m.QuizAttempt, submissions []m.QuizSubmission, expiredBefore time.Time) (bool, error) {
+	submitted := false
+
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		result, err := tx.Model((*m.QuizAttempt)(nil)).
+			Set("status = ?", cf.AttemptStatusSubmitted).
+			Set("submitted_at = ?", attempt.SubmittedAt).
+			Set("score = ?", attempt.Score).
+			Set("updated_at = NOW()").
+			Where("id = ?", attempt.ID).
+			Where("status = ?", cf.AttemptStatusInProgress).
+			Where("(deadline_at IS NULL OR deadline_at >= ?)", expiredBefore).
+			Where("deleted_at IS NULL").
+			Update()
+		if err != nil || result.RowsAffected() == 0 {
+			return err
+		}
+
+		for i := range submissions {
+			submissions[i].QuizAttemptID = attempt.ID
+			submissions[i].Attempt = attempt.Attempt
+			if _, err := tx.Model(&submissions[i]).Insert(); err != nil {
+				return err
+			}
+		}
+
+		submitted = true
+		return nil
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error submitting quiz attempt %d: %v", attempt.ID, err)
+		return false, err
+	}
+
+	if submitted {
+		attempt.Status = cf.AttemptStatusSubmitted
+	}
+
+	return submitted, nil
+}
+
+// ExpireQuizAttempt closes an open attempt whose time limit is over with a score of 0,
+// it reports false when the attempt was already closed
+func (repo *PgQuizRepository) ExpireQuizAttempt(attemptID int) (bool, error) {
+	result, err := repo.DB.Model((*m.QuizAttempt)(nil)).
+		Set("status = ?", cf.AttemptStatusExpired).
+		Set("score = 0").
+		Set("updated_at = NOW()").
+		Where("id = ?", attemptID).
+		Where("status = ?", cf.AttemptStatusInProgress).
+		Where("deleted_at IS NULL").
+		Update()
+
+	if err != nil {
+		repo.Logger.Errorf("Error expiring quiz attempt %d: %v", attemptID, err)
+		return false, err
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// GetExpiredQuizAttempts returns the open attempts with a deadline before expiredBefore, with their user and quiz
+func (repo *PgQuizRepository) GetExpiredQuizAttempts(expiredBefore time.Time) ([]m.QuizAttempt, error) {
+	attempts := []m.QuizAttempt{}
+
+	err := repo.DB.Model(&attempts).
+		Relation("User").
+		Relation("User.UserProfile").
+		Relation("Quiz").
+		Where("quiz_attempt.status = ?", cf.AttemptStatusInProgress).
+		Where("quiz_attempt.deadline_at < ?", expiredBefore).
+		Where("quiz_attempt.deleted_at IS NULL").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching expired quiz attempts: %v", err)
+	}
+
+	return attempts, err
+}
+
+// GetAttemptQuestions returns the question set of an attempt in the order it was shown, with the answers of each question.
+// Questions deleted since the attempt was started are still returned.
+func (repo *PgQuizRepository) GetAttemptQuestions(attemptID int) ([]m.QuizAttemptQuestion, error) {
+	var attemptQuestions []m.QuizAttemptQuestion
+
+	err := repo.DB.Model(&attemptQuestions).
+		Where("quiz_attempt_id = ?", attemptID).
+		Where("deleted_at IS NULL").
+		Order("position ASC").
+		Select()
+	if err != nil {
+		repo.Logger.Errorf("Error fetching questions of attempt %d: %v", attemptID, err)
+		return nil, err
+	}
+	if len(attemptQuestions) == 0 {
+		return attemptQuestions, nil
+	}
+
+	questionIDs := make([]int, len(attemptQuestions))
+	for i, attemptQuestion := range attemptQuestions {
+		questionIDs[i] = attemptQuestion.QuizQuestionID
+	}
+
+	var questions []m.QuizQuestion
+	err = WithAnswerKeys(repo.DB.Model(&questions)).
+		Relation("Answers", func(q *orm.Query) (*orm.Query, error) {
+			return q.Where("deleted_at IS NULL").Order("position ASC", "id ASC"), nil
+		}).
+		AllWithDeleted().
+		Where("quiz_question.id IN (?)", pg.In(questionIDs)).
+		Select()
+	if err != nil {
+		repo.Logger.Errorf("Error fetching question set of attempt %d: %v", attemptID, err)
+		return nil, err
+	}
+
+	questionsMap := make(map[int]m.QuizQuestion, len(questions))
+	for _, question := range questions {
+		questionsMap[question.ID] = question
+	}
+	for i := range attemptQuestions {
+		attemptQuestions[i].QuizQuestion = questionsMap[attemptQuestions[i].QuizQuestionID]
+	}
+
+	return attemptQuestions, nil
+}
+
+// GetDrawRules returns the draw rules of a quiz with their question bank
+func (repo *PgQuizRepository) GetDrawRules(quizID int) ([]m.QuizDrawRule, error) {
+	rules := []m.QuizDrawRule{}
+
+	err := repo.DB.Model(&rules).
+		Relation("QuestionBank").
+		Where("quiz_draw_rule.quiz_id = ?", quizID).
+		Where("quiz_draw_rule.deleted_at IS NULL").
+		Order("quiz_draw_rule.id ASC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching draw rules of quiz %d: %v", quizID, err)
+	}
+
+	return rules, err
+}
+
+// SaveDrawRules replaces the draw rules of a quiz, attempts already started keep their question set
+func (repo *PgQuizRepository) SaveDrawRules(quizID int, rules []m.QuizDrawRule) error {
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Exec("DELETE FROM quiz_draw_rules WHERE quiz_id = ?", quizID)
+		if err != nil || len(rules) == 0 {
+			return err
+		}
+
+		for i := range rules {
+			rules[i].QuizID = quizID
+		}
+		_, err = tx.Model(&rules).Insert()
+		return err
+	})
+
+	if err != nil {
+		repo.Logger.Errorf("Error saving draw rules of quiz %d: %v", quizID, err)
+	}
+
+	return err
+}
+
+// GetCurrentAttemptNumber returns the number of the open attempt of a user on a quiz,
+// or the number the next attempt gets when none is open
+func (repo *PgQuizRepository) GetCurrentAttemptNumber(userID int, quizID int) (int, error) {
+	var attemptNumber int
+
+	_, err := repo.DB.QueryOne(pg.Scan(&attemptNumber), `SELECT COALESCE(
+			(SELECT attempt FROM quiz_attempts WHERE user_id = ?0 AND quiz_id = ?1 AND status = ?2 AND deleted_at IS NULL LIMIT 1),
+			(SELECT COALESCE(MAX(attempt), 0) + 1 FROM quiz_attempts WHERE user_id = ?0 AND quiz_id = ?1)
+		)`, userID, quizID, cf.AttemptStatusInProgress)
+
+	if err != nil {
+		repo.Logger.Errorf("Error getting current attempt for user %d and quiz %d: %v", userID, quizID, err)
+	}
+
+	return attemptNumber, err
+}
+
+// WithAnswerKeys loads the answer keys of the question types graded without answer options with the questions,
+// replaced answer keys are left out even when the questions are queried with the deleted ones
+func WithAnswerKeys(query *orm.Query) *orm.Query {
+	current := func(q *orm.Query) (*orm.Query, error) {
+		return q.Where("deleted_at IS NULL").Order("id ASC"), nil
+	}
+	currentByPosition := func(q *orm.Query) (*orm.Query, error) {
+		return q.Where("deleted_at IS NULL").Order("position ASC"), nil
+	}
+
+	return query.
+		Relation("AcceptedAnswers", current).
+		Relation("MatchPairs", currentByPosition).
+		Relation("OrderItems", currentByPosition).
+		Relation("NumericAnswers", current)
+}
+
+// saveAnswerKeys replaces the answer keys of a question with the ones it holds, they are inserted as new rows
+func saveAnswerKeys(tx *pg.Tx, question *m.QuizQuestion) error {
+	for _, model := range []interface{}{
+		(*m.QuizAcceptedAnswer)(nil),
+		(*m.QuizMatchPair)(nil),
+		(*m.QuizOrderItem)(nil),
+		(*m.QuizNumericAnswer)(nil),
+	} {
+		_, err := tx.Model(model).
+			Where("quiz_question_id = ?", question.ID).
+			Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range question.AcceptedAnswers {
+		question.AcceptedAnswers[i].ID = 0
+		question.AcceptedAnswers[i].QuizQuestionID = question.ID
+	}
+	for i := range question.MatchPairs {
+		question.MatchPairs[i].ID = 0
+		question.MatchPairs[i].QuizQuestionID = question.ID
+	}
+	for i := range question.OrderItems {
+		question.OrderItems[i].ID = 0
+		question.OrderItems[i].QuizQuestionID = question.ID
+	}
+	for i := range question.NumericAnswers {
+		question.NumericAnswers[i].ID = 0
+		question.NumericAnswers[i].QuizQuestionID = question.ID
+	}
+
+	var err error
+	if len(question.AcceptedAnswers) > 0 {
+		_, err = tx.Model(&question.AcceptedAnswers).Insert()
+	}
+	if err == nil && len(question.MatchPairs) > 0 {
+		_, err = tx.Model(&question.MatchPairs).Insert()
+	}
+	if err == nil && len(question.OrderItems) > 0 {
+		_, err = tx.Model(&question.OrderItems).Insert()
+	}
+	if err == nil && len(question.NumericAnswers) > 0 {
+		_, err = tx.Model(&question.NumericAnswers).Insert()
+	}
+
+	return err
+}
+
+// questionShownInAttempts tells whether a question was shown in an attempt or answered
+func questionShownInAttempts(tx *pg.Tx, questionID int) (bool, error) {
+	shown, err := tx.Model((*m.QuizAttemptQuestion)(nil)).
+		Where("quiz_question_id = ?", questionID).
+		Exists()
+	if err != nil || shown {
+		return shown, err
+	}
+
+	return tx.Model((*m.QuizSubmission)(nil)).
+		Where("quiz_question_id = ?", questionID).
+		Exists()
+}
+
+// nextQuestionPosition returns the position after the last question of the quiz or the question bank of a question
+func nextQuestionPosition(tx *pg.Tx, question *m.QuizQuestion) (int, error) {
+	var position int
+
+	query := tx.Model((*m.QuizQuestion)(nil)).
+		ColumnExpr("COALESCE(MAX(position), 0) + 1")
+	if question.QuizID > 0 {
+		query.Where("quiz_id = ?", question.QuizID)
+	} else {
+		query.Where("question_bank_id = ?", question.QuestionBankID)
+	}
+
+	err := query.Select(pg.Scan(&position))
+	return position, err
+}
+
+// deleteAnswersNotKept deletes the answers of a question that are not among the answers saved with it
+func deleteAnswersNotKept(tx *pg.Tx, questionID int, answers []m.QuizAnswer) error {
+	keptIDs := []int{}
+	for _, answer := range answers {
+		if answer.ID > 0 {
+			keptIDs = append(keptIDs, answer.ID)
+		}
+	}
+
+	query := tx.Model((*m.QuizAnswer)(nil)).
+		Where("quiz_question_id = ?", questionID)
+	if len(keptIDs) > 0 {
+		query.Where("id NOT IN (?)", pg.In(keptIDs))
+	}
+
+	_, err := query.Delete()
+	return err
+}
@@ -0,0 +1,249 @@
+package quizzes
+
+import (
+	"fmt"
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"time"
+)
+
+// AttemptResult is the outcome of the closed attempts of a user on a quiz under the attempt policy of the quiz
+// Reviews only raise the score of an attempt, a kept score below the pass mark is not final while PendingReview is set.
+// PendingReview only looks at the attempts whose score is kept.
+type AttemptResult struct {
+	KeptScore     float64
+	Passed        bool
+	AttemptsUsed  int
+	LatestAttempt int
+	PendingReview bool
+	NextAttemptAt time.Time
+}
+
+// ValidatePolicyParams returns why the attempt policy or the pass mark of a quiz is invalid, empty when they are valid
+func ValidatePolicyParams(policyParams param.QuizPolicyParams, totalScore float64) string {
+	if policyParams.MaxAttempts < 0 {
+		return "max_attempts can not be negative"
+	}
+
+	if policyParams.AttemptCooldown < 0 {
+		return "attempt_cooldown can not be negative"
+	}
+
+	if policyParams.ScorePolicy != "" {
+		if _, ok := utils.FindStringInArray(cf.ScorePolicies, policyParams.ScorePolicy); !ok {
+			return "score_policy must be highest, latest or average"
+		}
+	}
+
+	if policyParams.PassMarkType != "" {
+		if _, ok := utils.FindStringInArray(cf.PassMarkTypes, policyParams.PassMarkType); !ok {
+			return "pass_mark_type must be percentage or score"
+		}
+	}
+
+	if policyParams.PassMark != nil {
+		passMark := *policyParams.PassMark
+		if passMark < 0 {
+			return "pass_mark can not be negative"
+		}
+		if policyParams.PassMarkType == cf.PassMarkScore && passMark > totalScore {
+			return "pass_mark can not be above the total score"
+		}
+		if policyParams.PassMarkType != cf.PassMarkScore && passMark > 100 {
+			return "pass_mark can not be above 100 percent"
+		}
+	}
+
+	return ""
+}
+
+// ApplyPolicyParams sets the attempt policy, the shuffle options and the pass mark of a quiz, the latest score counts by default.
+// A new quiz without a pass mark gets the default percentage, an existing one keeps its pass mark.
+func ApplyPolicyParams(quiz *m.Quiz, policyParams param.QuizPolicyParams) {
+	quiz.MaxAttempts = policyParams.MaxAttempts
+	quiz.AttemptCooldown = policyParams.AttemptCooldown
+	quiz.ShuffleQuestions = policyParams.ShuffleQuestions
+	quiz.ShuffleOptions = policyParams.ShuffleOptions
+	quiz.ScorePolicy = policyParams.ScorePolicy
+	if quiz.ScorePolicy == "" {
+		quiz.ScorePolicy = cf.ScorePolicyLatest
+	}
+
+	if policyParams.PassMark != nil {
+		quiz.PassMark = *policyParams.PassMark
+		quiz.PassMarkType = policyParams.PassMarkType
+	} else if quiz.ID == 0 {
+		quiz.PassMark = cf.DefaultPassMark
+		quiz.PassMarkType = ""
+	}
+
+	if quiz.PassMarkType == "" {
+		quiz.PassMarkType = cf.PassMarkPercentage
+	}
+}
+
+// PassThreshold returns the score an attempt needs to pass a quiz
+func PassThreshold(quiz m.Quiz) float64 {
+	if quiz.PassMarkType == cf.PassMarkScore {
+		return quiz.PassMark
+	}
+
+	return quiz.TotalScore * quiz.PassMark / 100
+}
+
+// ClosedAttempts returns the submitted and expired attempts, in attempt order
+func ClosedAttempts(attempts []m.QuizAttempt) []m.QuizAttempt {
+	closedAttempts := []m.QuizAttempt{}
+	for _, attempt := range attempts {
+		if attempt.Status != cf.AttemptStatusInProgress {
+			closedAttempts = append(closedAttempts, attempt)
+		}
+	}
+
+	return closedAttempts
+}
+
+// KeptScore returns the score that counts for a quiz from its closed attempts following its score policy
+func KeptScore(quiz m.Quiz, closedAttempts []m.QuizAttempt) float64 {
+	keptAttempts := KeptAttempts(quiz, closedAttempts)
+	if len(keptAttempts) == 0 {
+		return 0
+	}
+
+	totalScore := float64(0)
+	for _, attempt := range keptAttempts {
+		totalScore += attempt.Score
+	}
+
+	return totalScore / float64(len(keptAttempts))
+}
+
+// KeptAttempts returns the closed attempts whose score counts for a quiz following its score policy:
+// the first attempt with the highest score, the latest attempt, or every attempt for an average
+func KeptAttempts(quiz m.Quiz, closedAttempts []m.QuizAttempt) []m.QuizAttempt {
+	if len(closedAttempts) == 0 {
+		return closedAttempts
+	}
+
+	switch quiz.ScorePolicy {
+	case cf.ScorePolicyHighest:
+		highestAttempt := closedAttempts[0]
+		for _, attempt := range closedAttempts {
+			if attempt.Score > highestAttempt.Score {
+				highestAttempt = attempt
+			}
+		}
+		return []m.QuizAttempt{highestAttempt}
+	case cf.ScorePolicyAverage:
+		return closedAttempts
+	default:
+		return closedAttempts[len(closedAttempts)-1:]
+	}
+}
+
+// NextAttemptAt returns when the cooldown after the last closed attempt is over, zero without a cooldown
+func NextAttemptAt(quiz m.Quiz, closedAttempts []m.QuizAttempt) time.Time {
+	if quiz.AttemptCooldown <= 0 || len(closedAttempts) == 0 {
+		return time.Time{}
+	}
+
+	return attemptClosedAt(closedAttempts[len(closedAttempts)-1]).Add(time.Duration(quiz.AttemptCooldown) * time.Minute)
+}
+
+// AttemptBlockedReason returns why a new attempt can not be started yet, empty when it can
+func AttemptBlockedReason(quiz m.Quiz, closedAttempts []m.QuizAttempt, now time.Time) string {
+	if quiz.MaxAttempts > 0 && len(closedAttempts) >= quiz.MaxAttempts {
+		return fmt.Sprintf("All %d attempts of this quiz are used", quiz.MaxAttempts)
+	}
+
+	if nextAttemptAt := NextAttemptAt(quiz, closedAttempts); now.Before(nextAttemptAt) {
+		return fmt.Sprintf("The next attempt of this quiz opens at %s", nextAttemptAt.Format(cf.FormatDate))
+	}
+
+	return ""
+}
+
+// EvaluateAttempts applies the attempt policy of a quiz to the attempts of a user and their answers
+func EvaluateAttempts(quiz m.Quiz, attempts []m.QuizAttempt, submissions []m.QuizSubmission) AttemptResult {
+	closedAttempts := ClosedAttempts(attempts)
+
+	result := AttemptResult{
+		KeptScore:     KeptScore(quiz, closedAttempts),
+		AttemptsUsed:  len(closedAttempts),
+		NextAttemptAt: NextAttemptAt(quiz, closedAttempts),
+	}
+	if len(closedAttempts) > 0 {
+		result.LatestAttempt = closedAttempts[len(closedAttempts)-1].Attempt
+	}
+
+	keptAttemptIDs := map[int]bool{}
+	for _, attempt := range KeptAttempts(quiz, closedAttempts) {
+		keptAttemptIDs[attempt.ID] = true
+	}
+	for _, submission := range submissions {
+		if !submission.Reviewed && keptAttemptIDs[submission.QuizAttemptID] {
+			result.PendingReview = true
+			break
+		}
+	}
+
+	result.Passed = result.AttemptsUsed > 0 && result.KeptScore >= PassThreshold(quiz)
+
+	return result
+}
+
+// UserAttemptResult fetches the attempts of a user on a quiz and applies the attempt policy of the quiz
+func UserAttemptResult(quizRepo rp.QuizRepository, userID int, quiz m.Quiz) (AttemptResult, error) {
+	attempts, err := quizRepo.GetQuizAttemptsByUser(userID, quiz.ID)
+	if err != nil {
+		return AttemptResult{}, err
+	}
+
+	submissions, err := quizRepo.GetQuizSubmissionsByUser(userID, quiz.ID)
+	if err != nil {
+		return AttemptResult{}, err
+	}
+
+	return EvaluateAttempts(quiz, attempts, submissions), nil
+}
+
+// attemptPolicyData returns the attempt policy of a quiz with the attempts a user has used and left
+func attemptPolicyData(quiz m.Quiz, result AttemptResult) map[string]interface{} {
+	policyData := map[string]interface{}{
+		"score_policy":     quiz.ScorePolicy,
+		"kept_score":       result.KeptScore,
+		"attempts_used":    result.AttemptsUsed,
+		"max_attempts":     quiz.MaxAttempts,
+		"attempt_cooldown": quiz.AttemptCooldown,
+		"next_attempt_at":  nil,
+	}
+
+	if quiz.MaxAttempts > 0 {
+		attemptsLeft := quiz.MaxAttempts - result.AttemptsUsed
+		if attemptsLeft < 0 {
+			attemptsLeft = 0
+		}
+		policyData["attempts_left"] = attemptsLeft
+	}
+
+	if result.NextAttemptAt.After(utils.TimeNowUTC()) {
+		policyData["next_attempt_at"] = result.NextAttemptAt
+	}
+
+	return policyData
+}
+
+// attemptClosedAt returns when an attempt was submitted, or its deadline when it expired
+func attemptClosedAt(attempt m.QuizAttempt) time.Time {
+	if !attempt.SubmittedAt.IsZero() {
+		return attempt.SubmittedAt
+	}
+	if !attempt.DeadlineAt.IsZero() {
+		return attempt.DeadlineAt
+	}
+
+	return attempt.UpdatedAt
+}
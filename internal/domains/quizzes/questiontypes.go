@@ -0,0 +1,430 @@
+package quizzes
+
+import (
+	"math"
+	"math/rand"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	"orientation-training-api/internal/interfaces/response"
+	m "orientation-training-api/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterScorer(cf.QuestionTypeTrueFalse, MultipleChoiceScorer{})
+	RegisterScorer(cf.QuestionTypeShortAnswer, ShortAnswerScorer{})
+	RegisterScorer(cf.QuestionTypeMatching, MatchingScorer{})
+	RegisterScorer(cf.QuestionTypeOrdering, OrderingScorer{})
+	RegisterScorer(cf.QuestionTypeNumeric, NumericScorer{})
+}
+
+// BuildAnswerKey sets the answer key of a question from its params following its question type and returns its answer options,
+// the true and false options of a true/false question are generated. The message tells why the answer key is invalid.
+func BuildAnswerKey(question *m.QuizQuestion, answers []m.QuizAnswer, key param.QuestionKeyParams) ([]m.QuizAnswer, string) {
+	question.AcceptedAnswers = nil
+	question.MatchPairs = nil
+	question.OrderItems = nil
+	question.NumericAnswers = nil
+
+	switch question.QuestionType {
+	case cf.QuestionTypeMultipleChoice:
+		if len(answers) == 0 {
+			return nil, "Multiple choice questions need answers"
+		}
+		return answers, ""
+	case cf.QuestionTypeEssay:
+		return answers, ""
+	case cf.QuestionTypeTrueFalse:
+		if key.CorrectBoolean == nil {
+			return nil, "correct_boolean is required for true/false questions"
+		}
+		question.IsMultipleCorrect = false
+		return []m.QuizAnswer{
+			{AnswerText: "True", IsCorrect: *key.CorrectBoolean},
+			{AnswerText: "False", IsCorrect: !*key.CorrectBoolean},
+		}, ""
+	case cf.QuestionTypeShortAnswer:
+		for _, acceptedAnswer := range key.AcceptedAnswers {
+			if strings.TrimSpace(acceptedAnswer.AnswerText) == "" {
+				return nil, "Accepted answers can not be empty"
+			}
+			question.AcceptedAnswers = append(question.AcceptedAnswers, m.QuizAcceptedAnswer{
+				AnswerText:       acceptedAnswer.AnswerText,
+				CaseSensitive:    acceptedAnswer.CaseSensitive,
+				IgnoreWhitespace: acceptedAnswer.IgnoreWhitespace,
+			})
+		}
+		if len(question.AcceptedAnswers) == 0 {
+			return nil, "Short answer questions need accepted answers"
+		}
+	case cf.QuestionTypeMatching:
+		optionKeys := newOptionKeys(len(key.MatchPairs))
+		for i, matchPair := range key.MatchPairs {
+			if strings.TrimSpace(matchPair.PromptText) == "" || strings.TrimSpace(matchPair.MatchText) == "" {
+				return nil, "Match pairs need a prompt and a match"
+			}
+			question.MatchPairs = append(question.MatchPairs, m.QuizMatchPair{
+				PromptText: matchPair.PromptText,
+				MatchText:  matchPair.MatchText,
+				Position:   i + 1,
+				OptionKey:  optionKeys[i],
+			})
+		}
+		if len(question.MatchPairs) < 2 {
+			return nil, "Matching questions need at least two match pairs"
+		}
+	case cf.QuestionTypeOrdering:
+		optionKeys := newOptionKeys(len(key.OrderItems))
+		for i, itemText := range key.OrderItems {
+			if strings.TrimSpace(itemText) == "" {
+				return nil, "Order items can not be empty"
+			}
+			question.OrderItems = append(question.OrderItems, m.QuizOrderItem{
+				ItemText:  itemText,
+				Position:  i + 1,
+				OptionKey: optionKeys[i],
+			})
+		}
+		if len(question.OrderItems) < 2 {
+			return nil, "Ordering questions need at least two order items"
+		}
+	case cf.QuestionTypeNumeric:
+		for _, numericAnswer := range key.NumericAnswers {
+			if numericAnswer.Tolerance < 0 {
+				return nil, "tolerance can not be negative"
+			}
+			question.NumericAnswers = append(question.NumericAnswers, m.QuizNumericAnswer{
+				Value:     numericAnswer.Value,
+				Tolerance: numericAnswer.Tolerance,
+			})
+		}
+		if len(question.NumericAnswers) == 0 {
+			return nil, "Numeric questions need numeric answers"
+		}
+	default:
+		return nil, "Invalid question type"
+	}
+
+	return []m.QuizAnswer{}, ""
+}
+
+// NewQuestionFromData builds a question of a quiz created with a module item and its answer options,
+// the question type of the quiz data applies when the question has none
+func NewQuestionFromData(quizData *param.QuizData, questionData param.QuizQuestion) (m.QuizQuestion, []m.QuizAnswer, string) {
+	question := m.QuizQuestion{
+		QuestionType:      questionData.QuestionType,
+		QuestionText:      questionData.QuestionText,
+		Explanation:       questionData.Explanation,
+		Weight:            questionData.Weight,
+		IsMultipleCorrect: questionData.AllowMultiple,
+		ScoringStrategy:   questionData.ScoringStrategy,
+	}
+	if question.QuestionType == 0 {
+		question.QuestionType = quizData.QuestionType
+	}
+	if question.ScoringStrategy == "" {
+		question.ScoringStrategy = cf.ScoringAllOrNothing
+	}
+
+	answers := []m.QuizAnswer{}
+	for _, option := range questionData.Options {
+		answers = append(answers, m.QuizAnswer{
+			AnswerText: option.AnswersText,
+			IsCorrect:  option.IsCorrect,
+		})
+	}
+
+	switch question.QuestionType {
+	case cf.QuestionTypeMultipleChoice:
+		if message := ValidateOptions(answers); message != "" {
+			return question, nil, message
+		}
+	case cf.QuestionTypeEssay:
+		answers = []m.QuizAnswer{}
+	}
+
+	answers, message := BuildAnswerKey(&question, answers, questionData.QuestionKeyParams)
+	return question, answers, message
+}
+
+// ValidateOptions checks that a multiple choice question has at least two answer options with a correct one
+func ValidateOptions(answers []m.QuizAnswer) string {
+	if len(answers) < 2 {
+		return "Multiple choice questions must have at least two options"
+	}
+
+	for _, answer := range answers {
+		if answer.IsCorrect {
+			return ""
+		}
+	}
+
+	return "Multiple choice questions must have at least one correct answer"
+}
+
+// QuizType returns the quiz type of a quiz from the question types of its questions
+func QuizType(questions []m.QuizQuestion) string {
+	essayCount := 0
+	for _, question := range questions {
+		if question.QuestionType == cf.QuestionTypeEssay {
+			essayCount++
+		}
+	}
+
+	switch {
+	case essayCount == 0:
+		return cf.QuizTypeMultipleChoice
+	case essayCount == len(questions):
+		return cf.QuizTypeEssay
+	default:
+		return cf.QuizTypeMixed
+	}
+}
+
+// HideAnswerKey removes what gives away the answer of a question for trainees, the items of ordering questions
+// and the matches of matching questions are listed by text with their option keys as ids
+func HideAnswerKey(question *m.QuizQuestion) {
+	for i := range question.Answers {
+		question.Answers[i].IsCorrect = false
+	}
+
+	question.AcceptedAnswers = nil
+	question.NumericAnswers = nil
+
+	for i, item := range question.OrderItems {
+		question.OrderItems[i] = m.QuizOrderItem{
+			BaseModel:      cm.BaseModel{ID: item.OptionKey},
+			QuizQuestionID: item.QuizQuestionID,
+			ItemText:       item.ItemText,
+		}
+	}
+	sort.SliceStable(question.OrderItems, func(i, j int) bool {
+		return question.OrderItems[i].ItemText < question.OrderItems[j].ItemText
+	})
+
+	question.MatchOptions = nil
+	for i, pair := range question.MatchPairs {
+		question.MatchOptions = append(question.MatchOptions, m.QuizMatchPair{
+			BaseModel:      cm.BaseModel{ID: pair.OptionKey},
+			QuizQuestionID: pair.QuizQuestionID,
+			MatchText:      pair.MatchText,
+		})
+		question.MatchPairs[i].MatchText = ""
+		question.MatchPairs[i].OptionKey = 0
+	}
+	sort.SliceStable(question.MatchOptions, func(i, j int) bool {
+		return question.MatchOptions[i].MatchText < question.MatchOptions[j].MatchText
+	})
+}
+
+// QuestionOptions returns what a trainee answers a question with: the answer options, the items of an ordering question
+// or the matches of a matching question listed by text with their option keys as ids, with the prompts of a matching question
+func QuestionOptions(question m.QuizQuestion) ([]response.QuizOptionResponse, []response.QuizOptionResponse) {
+	options := []response.QuizOptionResponse{}
+	prompts := []response.QuizOptionResponse{}
+
+	switch question.QuestionType {
+	case cf.QuestionTypeOrdering:
+		for _, item := range question.OrderItems {
+			options = append(options, response.QuizOptionResponse{ID: item.OptionKey, Text: item.ItemText})
+		}
+	case cf.QuestionTypeMatching:
+		for _, pair := range question.MatchPairs {
+			prompts = append(prompts, response.QuizOptionResponse{ID: pair.ID, Text: pair.PromptText})
+			options = append(options, response.QuizOptionResponse{ID: pair.OptionKey, Text: pair.MatchText})
+		}
+	default:
+		for _, answer := range question.Answers {
+			options = append(options, response.QuizOptionResponse{ID: answer.ID, Text: answer.AnswerText})
+		}
+		return options, prompts
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Text < options[j].Text
+	})
+
+	return options, prompts
+}
+
+// newOptionKeys returns count distinct random option keys
+func newOptionKeys(count int) []int {
+	optionKeys := make([]int, 0, count)
+	used := map[int]bool{}
+	for len(optionKeys) < count {
+		optionKey := rand.Intn(cf.OptionKeyMax) + 1
+		if !used[optionKey] {
+			used[optionKey] = true
+			optionKeys = append(optionKeys, optionKey)
+		}
+	}
+
+	return optionKeys
+}
+
+// NewQuizQuestionResponse returns a question as trainees answer it
+func NewQuizQuestionResponse(question m.QuizQuestion, points float64) response.QuizQuestionResponse {
+	options, prompts := QuestionOptions(question)
+
+	return response.QuizQuestionResponse{
+		QuestionID:    question.ID,
+		QuestionType:  question.QuestionType,
+		QuestionText:  question.QuestionText,
+		AllowMultiple: question.IsMultipleCorrect,
+		Points:        points,
+		Options:       options,
+		Prompts:       prompts,
+	}
+}
+
+// partialScore returns the score of an answer with right and wrong parts out of total parts following a scoring strategy
+func partialScore(scoringStrategy string, points float64, rightCount int, wrongCount int, totalCount int) float64 {
+	if totalCount == 0 {
+		return 0
+	}
+
+	switch scoringStrategy {
+	case cf.ScoringProportional:
+		return points * float64(rightCount) / math.Max(float64(totalCount), float64(rightCount+wrongCount))
+	case cf.ScoringRightMinusWrong:
+		return math.Max(0, points*float64(rightCount-wrongCount)/float64(totalCount))
+	default:
+		if rightCount == totalCount && wrongCount == 0 {
+			return points
+		}
+		return 0
+	}
+}
+
+// ShortAnswerScorer grades short answers against the accepted answers of the question
+type ShortAnswerScorer struct{}
+
+func (ShortAnswerScorer) Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade {
+	grade := AnswerGrade{Reviewed: true}
+
+	acceptedTexts := []string{}
+	for _, acceptedAnswer := range question.AcceptedAnswers {
+		acceptedTexts = append(acceptedTexts, acceptedAnswer.AnswerText)
+		if normalizeShortAnswer(answer.AnswerText, acceptedAnswer) == normalizeShortAnswer(acceptedAnswer.AnswerText, acceptedAnswer) {
+			grade.IsCorrect = true
+		}
+	}
+	grade.CorrectAnswer = acceptedTexts
+
+	if grade.IsCorrect {
+		grade.Score = points
+	}
+
+	return grade
+}
+
+// normalizeShortAnswer applies the case and whitespace rules of an accepted answer to a text
+func normalizeShortAnswer(text string, acceptedAnswer m.QuizAcceptedAnswer) string {
+	if acceptedAnswer.IgnoreWhitespace {
+		text = strings.Join(strings.Fields(text), "")
+	} else {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+
+	if !acceptedAnswer.CaseSensitive {
+		text = strings.ToLower(text)
+	}
+
+	return text
+}
+
+// MatchingScorer grades the prompts matched with their own match following the scoring strategy of the question
+type MatchingScorer struct{}
+
+func (MatchingScorer) Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade {
+	grade := AnswerGrade{Reviewed: true}
+
+	pairIDs := map[int]int{}
+	correctMatches := []m.MatchAnswer{}
+	for _, pair := range question.MatchPairs {
+		pairIDs[pair.ID] = pair.OptionKey
+		correctMatches = append(correctMatches, m.MatchAnswer{PairID: pair.ID, MatchID: pair.OptionKey})
+	}
+	grade.CorrectAnswer = correctMatches
+
+	matched := map[int]bool{}
+	rightCount, wrongCount := 0, 0
+	for _, matchAnswer := range answer.MatchAnswers {
+		optionKey, ok := pairIDs[matchAnswer.PairID]
+		if !ok || matched[matchAnswer.PairID] {
+			continue
+		}
+		matched[matchAnswer.PairID] = true
+
+		if matchAnswer.MatchID == optionKey {
+			rightCount++
+		} else {
+			wrongCount++
+		}
+	}
+
+	grade.IsCorrect = len(pairIDs) > 0 && rightCount == len(pairIDs)
+	grade.Score = partialScore(question.ScoringStrategy, points, rightCount, wrongCount, len(pairIDs))
+
+	return grade
+}
+
+// OrderingScorer grades the items put in their place following the scoring strategy of the question,
+// the answer lists the option keys of the items in order
+type OrderingScorer struct{}
+
+func (OrderingScorer) Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade {
+	grade := AnswerGrade{Reviewed: true}
+
+	items := append([]m.QuizOrderItem{}, question.OrderItems...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Position < items[j].Position
+	})
+
+	correctOrder := make([]int, len(items))
+	for i, item := range items {
+		correctOrder[i] = item.OptionKey
+	}
+	grade.CorrectAnswer = correctOrder
+
+	rightCount := 0
+	for i, itemID := range answer.SelectedAnswerIDs {
+		if i < len(correctOrder) && correctOrder[i] == itemID {
+			rightCount++
+		}
+	}
+	wrongCount := len(correctOrder) - rightCount
+
+	grade.IsCorrect = len(correctOrder) > 0 && rightCount == len(correctOrder) && len(answer.SelectedAnswerIDs) == len(correctOrder)
+	grade.Score = partialScore(question.ScoringStrategy, points, rightCount, wrongCount, len(correctOrder))
+	if grade.IsCorrect {
+		grade.Score = points
+	}
+
+	return grade
+}
+
+// NumericScorer grades a number within the tolerance of one of the numeric answers of the question
+type NumericScorer struct{}
+
+func (NumericScorer) Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade {
+	grade := AnswerGrade{Reviewed: true, CorrectAnswer: question.NumericAnswers}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(answer.AnswerText), 64)
+	if err != nil {
+		return grade
+	}
+
+	for _, numericAnswer := range question.NumericAnswers {
+		if math.Abs(value-numericAnswer.Value) <= numericAnswer.Tolerance {
+			grade.IsCorrect = true
+			grade.Score = points
+			break
+		}
+	}
+
+	return grade
+}
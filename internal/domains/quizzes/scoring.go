@@ -0,0 +1,118 @@
+package quizzes
+
+import (
+	cf "orientation-training-api/configs"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"sync"
+)
+
+// SubmittedAnswer is the answer of a user to a question
+type SubmittedAnswer struct {
+	AnswerText        string
+	SelectedAnswerIDs []int
+	MatchAnswers      []m.MatchAnswer
+}
+
+// AnswerGrade is the grade of an answer, an answer that is not Reviewed is scored later by a manager.
+// CorrectAnswerIDs lists the correct answer options, CorrectAnswer the answer key of the other question types.
+type AnswerGrade struct {
+	Score            float64
+	IsCorrect        bool
+	Reviewed         bool
+	CorrectAnswerIDs []int
+	CorrectAnswer    interface{}
+}
+
+// Scorer grades the answers to the questions of a question type, points is what the question is worth in the attempt
+type Scorer interface {
+	Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade
+}
+
+var (
+	scorersMutex sync.RWMutex
+	scorers      = map[int]Scorer{}
+)
+
+func init() {
+	RegisterScorer(cf.QuestionTypeMultipleChoice, MultipleChoiceScorer{})
+	RegisterScorer(cf.QuestionTypeEssay, EssayScorer{})
+}
+
+// RegisterScorer sets the scorer grading the answers to the questions of a question type
+func RegisterScorer(questionType int, scorer Scorer) {
+	scorersMutex.Lock()
+	defer scorersMutex.Unlock()
+
+	scorers[questionType] = scorer
+}
+
+// GradeAnswer grades an answer with the scorer of the question type, it reports false for a question type without scorer
+func GradeAnswer(question m.QuizQuestion, answer SubmittedAnswer, points float64) (AnswerGrade, bool) {
+	scorersMutex.RLock()
+	scorer, ok := scorers[question.QuestionType]
+	scorersMutex.RUnlock()
+
+	if !ok {
+		return AnswerGrade{}, false
+	}
+
+	return scorer.Grade(question, answer, points), true
+}
+
+// ValidateScoringStrategy returns why a scoring strategy is invalid, empty when it is valid or empty
+func ValidateScoringStrategy(scoringStrategy string) string {
+	if scoringStrategy == "" {
+		return ""
+	}
+
+	if _, ok := utils.FindStringInArray(cf.ScoringStrategies, scoringStrategy); !ok {
+		return "scoring_strategy must be all_or_nothing, proportional or right_minus_wrong"
+	}
+
+	return ""
+}
+
+// MultipleChoiceScorer grades multiple choice answers following the scoring strategy of the question:
+// all or nothing, the share of the correct options selected out of the correct or selected options when more are selected,
+// or the correct options selected minus the wrong ones out of the correct options with a floor at zero
+type MultipleChoiceScorer struct{}
+
+func (MultipleChoiceScorer) Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade {
+	grade := AnswerGrade{Reviewed: true, CorrectAnswerIDs: []int{}}
+
+	correctAnswerIDs := map[int]bool{}
+	for _, a := range question.Answers {
+		if a.IsCorrect {
+			correctAnswerIDs[a.ID] = true
+			grade.CorrectAnswerIDs = append(grade.CorrectAnswerIDs, a.ID)
+		}
+	}
+
+	selectedAnswerIDs := map[int]bool{}
+	for _, selectedID := range answer.SelectedAnswerIDs {
+		selectedAnswerIDs[selectedID] = true
+	}
+
+	rightCount, wrongCount := 0, 0
+	for selectedID := range selectedAnswerIDs {
+		if correctAnswerIDs[selectedID] {
+			rightCount++
+		} else {
+			wrongCount++
+		}
+	}
+
+	correctCount := len(correctAnswerIDs)
+	grade.IsCorrect = correctCount > 0 && rightCount == correctCount && wrongCount == 0
+	grade.Score = partialScore(question.ScoringStrategy, points, rightCount, wrongCount, correctCount)
+
+	return grade
+}
+
+// EssayScorer leaves essay answers at a score of 0 until a manager reviews them
+type EssayScorer struct{}
+
+func (EssayScorer) Grade(question m.QuizQuestion, answer SubmittedAnswer, points float64) AnswerGrade {
+	return AnswerGrade{}
+}
@@ -0,0 +1,111 @@
+package quizzes
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	m "orientation-training-api/internal/models"
+)
+
+// attemptSeed returns the shuffle seed of an attempt of a user on a quiz, it only depends on the attempt number
+// so the order shown before an attempt starts is the order of the attempt
+func attemptSeed(quizID int, userID int, attempt int) int64 {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d:%d:%d", quizID, userID, attempt)
+	return int64(hash.Sum64())
+}
+
+// ShuffleQuestions reorders questions and their answer options for an attempt of a user, following the shuffle
+// options of the quiz. The order only depends on the attempt and on the questions, answer options of a question
+// keep their order across the question sets of an attempt.
+func ShuffleQuestions(quiz m.Quiz, userID int, attempt int, questions []m.QuizQuestion) {
+	seed := attemptSeed(quiz.ID, userID, attempt)
+
+	if quiz.ShuffleQuestions {
+		random := rand.New(rand.NewSource(seed))
+		random.Shuffle(len(questions), func(i, j int) {
+			questions[i], questions[j] = questions[j], questions[i]
+		})
+	}
+
+	if quiz.ShuffleOptions {
+		for i := range questions {
+			answers := questions[i].Answers
+			random := rand.New(rand.NewSource(seed ^ int64(questions[i].ID)))
+			random.Shuffle(len(answers), func(a, b int) {
+				answers[a], answers[b] = answers[b], answers[a]
+			})
+		}
+	}
+}
+
+// ShuffleAttemptQuestions reorders the question set of an attempt like ShuffleQuestions and renumbers its positions,
+// following the shuffle options of the quiz when the attempt started
+func ShuffleAttemptQuestions(quiz m.Quiz, attempt m.QuizAttempt, attemptQuestions []m.QuizAttemptQuestion) {
+	questions := make([]m.QuizQuestion, len(attemptQuestions))
+	attemptQuestionsMap := make(map[int]m.QuizAttemptQuestion, len(attemptQuestions))
+	for i, attemptQuestion := range attemptQuestions {
+		questions[i] = attemptQuestion.QuizQuestion
+		attemptQuestionsMap[attemptQuestion.QuizQuestionID] = attemptQuestion
+	}
+
+	quiz.ShuffleQuestions = attempt.ShuffleQuestions
+	quiz.ShuffleOptions = attempt.ShuffleOptions
+	ShuffleQuestions(quiz, attempt.UserID, attempt.Attempt, questions)
+
+	for i, question := range questions {
+		attemptQuestions[i] = attemptQuestionsMap[question.ID]
+		attemptQuestions[i].QuizQuestion = question
+		attemptQuestions[i].Position = i + 1
+	}
+}
+
+// QuestionsForUser returns the questions a user is shown on a quiz in their order: the question set drawn for the open
+// attempt of the user, or the questions of the quiz in the order of the next attempt when none is open
+func QuestionsForUser(quizRepo rp.QuizRepository, quiz m.Quiz, userID int) ([]m.QuizAttemptQuestion, error) {
+	attempts, err := quizRepo.GetQuizAttemptsByUser(userID, quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attempt := range attempts {
+		if attempt.Status != cf.AttemptStatusInProgress {
+			continue
+		}
+
+		attemptQuestions, err := quizRepo.GetAttemptQuestions(attempt.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(attemptQuestions) > 0 {
+			ShuffleAttemptQuestions(quiz, attempt, attemptQuestions)
+			return attemptQuestions, nil
+		}
+	}
+
+	questions, err := quizRepo.GetQuizQuestionsWithAnswers(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+	attemptNumber, err := quizRepo.GetCurrentAttemptNumber(userID, quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptQuestions := quizAttemptQuestions(quiz, questions)
+	ShuffleAttemptQuestions(quiz, NewQuizAttempt(quiz, userID, attemptNumber), attemptQuestions)
+	return attemptQuestions, nil
+}
+
+// NewQuizAttempt returns an attempt of a user on a quiz, following the current shuffle options of the quiz
+func NewQuizAttempt(quiz m.Quiz, userID int, attempt int) m.QuizAttempt {
+	return m.QuizAttempt{
+		UserID:           userID,
+		QuizID:           quiz.ID,
+		Attempt:          attempt,
+		ShuffleQuestions: quiz.ShuffleQuestions,
+		ShuffleOptions:   quiz.ShuffleOptions,
+	}
+}
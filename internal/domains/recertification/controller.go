@@ -0,0 +1,249 @@
+package recertification
+
+import (
+	"net/http"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	"strconv"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type RecertificationController struct {
+	cm.BaseController
+
+	RecertificationRepo rp.RecertificationRepository
+	CourseRepo          rp.CourseRepository
+	UserProgressRepo    rp.UserProgressRepository
+}
+
+func NewRecertificationController(logger echo.Logger, recertificationRepo rp.RecertificationRepository, courseRepo rp.CourseRepository, upRepo rp.UserProgressRepository) (ctr *RecertificationController) {
+	ctr = &RecertificationController{cm.BaseController{}, recertificationRepo, courseRepo, upRepo}
+	ctr.Init(logger)
+	return
+}
+
+// GetPolicy : get the recertification policy of a course
+// Params : echo.Context
+// Returns : return error
+func (ctr *RecertificationController) GetPolicy(c echo.Context) error {
+	policyParams := new(param.RecertificationPolicyParams)
+
+	if err := c.Bind(policyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(policyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	policy, err := ctr.RecertificationRepo.GetPolicyByCourseID(policyParams.CourseID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.SuccessResponseCode,
+				Message: "Course has no recertification policy",
+				Data:    nil,
+			})
+		}
+
+		ctr.Logger.Errorf("Failed to fetch recertification policy: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data:    policy,
+	})
+}
+
+// SavePolicy : create or update the recertification policy of a compliance course
+// Params : echo.Context
+// Returns : return error
+func (ctr *RecertificationController) SavePolicy(c echo.Context) error {
+	savePolicyParams := new(param.SaveRecertificationPolicyParams)
+
+	if err := c.Bind(savePolicyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(savePolicyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if savePolicyParams.ValidityDays <= 0 || savePolicyParams.GraceDays < 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "validity_days must be positive and grace_days can not be negative",
+		})
+	}
+
+	course, err := ctr.CourseRepo.GetCourseByID(savePolicyParams.CourseID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Course not found",
+			})
+		}
+
+		ctr.Logger.Errorf("Failed to fetch course: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	if !isComplianceCourse(course) {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Recertification is only available for Compliance courses",
+		})
+	}
+
+	policy := &m.RecertificationPolicy{
+		CourseID:     savePolicyParams.CourseID,
+		ValidityDays: savePolicyParams.ValidityDays,
+		GraceDays:    savePolicyParams.GraceDays,
+	}
+
+	if err := ctr.RecertificationRepo.SavePolicy(policy); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save recertification policy",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Recertification policy saved",
+		Data:    policy,
+	})
+}
+
+// DeletePolicy : stop recertifying a course, existing histories are kept
+// Params : echo.Context
+// Returns : return error
+func (ctr *RecertificationController) DeletePolicy(c echo.Context) error {
+	policyParams := new(param.RecertificationPolicyParams)
+
+	if err := c.Bind(policyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(policyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if err := ctr.RecertificationRepo.DeletePolicyByCourseID(policyParams.CourseID); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to delete recertification policy",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Recertification policy deleted",
+	})
+}
+
+// GetProgressHistory : get the current and archived cycles of a user in a course
+// Trainees only see their own history, managers can pass user_id
+// Params : echo.Context
+// Returns : return error
+func (ctr *RecertificationController) GetProgressHistory(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	historyParams := new(param.ProgressHistoryParams)
+
+	if err := c.Bind(historyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(historyParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	targetUserID := userProfile.ID
+	if userProfile.RoleID != cf.EmployeeRoleID && historyParams.UserID > 0 {
+		targetUserID = historyParams.UserID
+	}
+
+	histories, err := ctr.RecertificationRepo.GetProgressHistories(targetUserID, historyParams.CourseID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch progress history",
+		})
+	}
+
+	var currentCycle interface{}
+	userProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(targetUserID, historyParams.CourseID)
+	if err == nil {
+		currentCycle = userProgress
+	} else if err.Error() != pg.ErrNoRows.Error() {
+		ctr.Logger.Errorf("Failed to fetch user progress: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch progress history",
+		})
+	}
+
+	if histories == nil {
+		histories = []m.UserProgressHistory{}
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data: map[string]interface{}{
+			"current":   currentCycle,
+			"histories": histories,
+		},
+	})
+}
+
+// isComplianceCourse accepts the category stored either as its code or its label
+func isComplianceCourse(course m.Course) bool {
+	category := strings.TrimSpace(course.Category)
+
+	return category == strconv.Itoa(cf.Compliance) || strings.EqualFold(category, cf.CourseCategoryList[cf.Compliance])
+}
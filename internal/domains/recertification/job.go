@@ -0,0 +1,88 @@
+package recertification
+
+import (
+	"fmt"
+	cf "orientation-training-api/configs"
+	"orientation-training-api/internal/domains/progression"
+	rp "orientation-training-api/internal/interfaces/repository"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+// RecertificationJob reopens enrollments whose completion is no longer valid,
+// keeping the finished cycle and its quiz scores as history
+type RecertificationJob struct {
+	Logger              echo.Logger
+	RecertificationRepo rp.RecertificationRepository
+	Progression         *progression.Service
+	NotificationRepo    rp.NotificationRepository
+}
+
+func NewRecertificationJob(logger echo.Logger, recertificationRepo rp.RecertificationRepository, progressionService *progression.Service, notificationRepo rp.NotificationRepository) *RecertificationJob {
+	return &RecertificationJob{logger, recertificationRepo, progressionService, notificationRepo}
+}
+
+// Run opens a new cycle for every enrollment whose validity period has ended
+func (job *RecertificationJob) Run() error {
+	userProgressList, err := job.RecertificationRepo.GetExpiredUserProgresses()
+	if err != nil {
+		return err
+	}
+
+	policies := make(map[int]m.RecertificationPolicy)
+
+	for _, userProgress := range userProgressList {
+		policy, exists := policies[userProgress.CourseID]
+		if !exists {
+			policy, err = job.RecertificationRepo.GetPolicyByCourseID(userProgress.CourseID)
+			if err != nil {
+				job.Logger.Errorf("Failed to fetch recertification policy of course %d: %v", userProgress.CourseID, err)
+				continue
+			}
+			policies[userProgress.CourseID] = policy
+		}
+
+		expiredAt := userProgress.CompletedAt.AddDate(0, 0, policy.ValidityDays)
+		dueDate := expiredAt.AddDate(0, 0, policy.GraceDays).Format(cf.FormatDateDatabase)
+
+		history := job.Progression.NewProgressHistory(userProgress, cf.HistoryReasonRecertification, 0, "")
+		history.ExpiredAt = expiredAt
+
+		// Quiz attempts and SCORM completion of the finished cycle no longer count in the new one
+		archiveCycle := func(tx *pg.Tx) error {
+			if err := job.Progression.ArchiveQuizAttemptsWithTx(tx, userProgress.UserID, userProgress.CourseID); err != nil {
+				return err
+			}
+			return job.Progression.ResetScormRuntimesWithTx(tx, userProgress.UserID, userProgress.CourseID)
+		}
+
+		if err := job.RecertificationRepo.StartNewCycle(&userProgress, history, dueDate, archiveCycle); err != nil {
+			job.Logger.Errorf("Failed to start a new cycle for enrollment %d: %v", userProgress.ID, err)
+			continue
+		}
+
+		courseTitle := fmt.Sprintf("Course %d", userProgress.CourseID)
+		if userProgress.Course != nil {
+			courseTitle = userProgress.Course.Title
+		}
+
+		err := job.NotificationRepo.CreateNotification(&m.Notification{
+			UserID:  userProgress.UserID,
+			Type:    cf.NotificationTypeRecertificationDue,
+			Title:   "Recertification required",
+			Message: fmt.Sprintf("Your certification for \"%s\" has expired. Please retake the course before %s.", courseTitle, dueDate),
+			Data: map[string]interface{}{
+				"course_id": userProgress.CourseID,
+				"cycle":     userProgress.Cycle + 1,
+				"due_date":  dueDate,
+			},
+		})
+		if err != nil {
+			job.Logger.Errorf("Failed to notify recertification of enrollment %d: %v", userProgress.ID, err)
+		}
+	}
+
+	return nil
+}
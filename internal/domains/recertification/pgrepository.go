@@ -0,0 +1,140 @@
+package recertification
+
+import (
+	cm "orientation-training-api/internal/common"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type PgRecertificationRepository struct {
+	cm.AppRepository
+}
+
+func NewPgRecertificationRepository(logger echo.Logger) (repo *PgRecertificationRepository) {
+	repo = &PgRecertificationRepository{}
+	repo.Init(logger)
+	return
+}
+
+// GetPolicyByCourseID : get the recertification policy of a course
+func (repo *PgRecertificationRepository) GetPolicyByCourseID(courseID int) (m.RecertificationPolicy, error) {
+	policy := m.RecertificationPolicy{}
+
+	err := repo.DB.Model(&policy).
+		Where("course_id = ?", courseID).
+		Where("deleted_at IS NULL").
+		First()
+
+	return policy, err
+}
+
+// SavePolicy : create or update the recertification policy of a course
+func (repo *PgRecertificationRepository) SavePolicy(policy *m.RecertificationPolicy) error {
+	_, err := repo.DB.Model(policy).
+		OnConflict("(course_id) DO UPDATE").
+		Set("validity_days = EXCLUDED.validity_days").
+		Set("grace_days = EXCLUDED.grace_days").
+		Set("updated_at = NOW()").
+		Set("deleted_at = NULL").
+		Returning("*").
+		Insert()
+
+	if err != nil {
+		repo.Logger.Errorf("Error saving recertification policy for course %d: %v", policy.CourseID, err)
+	}
+
+	return err
+}
+
+// DeletePolicyByCourseID : remove the recertification policy of a course
+func (repo *PgRecertificationRepository) DeletePolicyByCourseID(courseID int) error {
+	_, err := repo.DB.Exec("DELETE FROM recertification_policies WHERE course_id = ?", courseID)
+	if err != nil {
+		repo.Logger.Errorf("Error deleting recertification policy for course %d: %v", courseID, err)
+	}
+
+	return err
+}
+
+// GetExpiredUserProgresses : get completed enrollments whose validity period has ended
+func (repo *PgRecertificationRepository) GetExpiredUserProgresses() ([]m.UserProgress, error) {
+	var userProgressList []m.UserProgress
+
+	err := repo.DB.Model(&userProgressList).
+		Relation("Course").
+		Join("JOIN recertification_policies AS rp ON rp.course_id = user_progress.course_id AND rp.deleted_at IS NULL").
+		Where("user_progress.completed = TRUE").
+		Where("user_progress.completed_at IS NOT NULL").
+		Where("user_progress.completed_at + make_interval(days => rp.validity_days) <= NOW()").
+		Where("user_progress.deleted_at IS NULL").
+		Order("user_progress.completed_at ASC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching expired user progress: %v", err)
+		return nil, err
+	}
+
+	return userProgressList, nil
+}
+
+// StartNewCycle : archive the finished cycle of an enrollment and reopen it from the beginning,
+// archiveCycle sets aside the quiz attempts and SCORM data of the finished cycle in the same transaction
+func (repo *PgRecertificationRepository) StartNewCycle(userProgress *m.UserProgress, history *m.UserProgressHistory, dueDate string, archiveCycle func(tx *pg.Tx) error) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if err := archiveCycle(tx); err != nil {
+			repo.Logger.Errorf("Error archiving quiz attempts and SCORM data of enrollment %d: %v", userProgress.ID, err)
+			return err
+		}
+
+		if _, err := tx.Model(history).Insert(); err != nil {
+			repo.Logger.Errorf("Error archiving cycle %d of user %d in course %d: %v", history.Cycle, history.UserID, history.CourseID, err)
+			return err
+		}
+
+		_, err := tx.Model((*m.UserProgress)(nil)).
+			Set("cycle = cycle + 1").
+			Set("module_position = 1").
+			Set("module_item_position = 1").
+			Set("completed = FALSE").
+			Set("completed_date = NULL").
+			Set("completed_at = NULL").
+			Set("item_started_at = NOW()").
+			Set("performance_rating = NULL").
+			Set("performance_comment = NULL").
+			Set("reviewed_by = NULL").
+			Set("due_date = ?", dueDate).
+			Set("assigned_at = NOW()").
+			Set("overdue_at = NULL").
+			Set("overdue_notified_at = NULL").
+			Set("updated_at = NOW()").
+			Where("id = ?", userProgress.ID).
+			Where("cycle = ?", userProgress.Cycle).
+			Update()
+		if err != nil {
+			repo.Logger.Errorf("Error reopening enrollment %d: %v", userProgress.ID, err)
+		}
+
+		return err
+	})
+}
+
+// GetProgressHistories : get the archived cycles of a user in a course, latest first
+func (repo *PgRecertificationRepository) GetProgressHistories(userID int, courseID int) ([]m.UserProgressHistory, error) {
+	var histories []m.UserProgressHistory
+
+	err := repo.DB.Model(&histories).
+		Where("user_id = ?", userID).
+		Where("course_id = ?", courseID).
+		Where("deleted_at IS NULL").
+		Order("cycle DESC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching progress history of user %d in course %d: %v", userID, courseID, err)
+	}
+
+	return histories, err
+}
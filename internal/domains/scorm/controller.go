@@ -0,0 +1,338 @@
+package scorm
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/progression"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	cld "orientation-training-api/internal/platform/cloud"
+	"orientation-training-api/internal/platform/scorm"
+	"orientation-training-api/internal/platform/utils"
+	"orientation-training-api/internal/platform/xapi"
+	"path"
+	"strconv"
+	"strings"
+
+	valid "github.com/asaskevich/govalidator"
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type ScormController struct {
+	cm.BaseController
+
+	ScormRepo   rp.ScormRepository
+	Progression *progression.Service
+	XapiRepo    rp.XapiRepository
+	cloud       cld.StorageUtility
+}
+
+func NewScormController(logger echo.Logger, scormRepo rp.ScormRepository, progressionService *progression.Service, xapiRepo rp.XapiRepository, cloud cld.StorageUtility) (ctr *ScormController) {
+	ctr = &ScormController{cm.BaseController{}, scormRepo, progressionService, xapiRepo, cloud}
+	ctr.Init(logger)
+	return
+}
+
+// GetScormRuntime : return the CMI data model to initialize a SCORM session
+// Params : echo.Context
+// Returns : return error
+func (ctr *ScormController) GetScormRuntime(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	getScormRuntimeParams := new(param.GetScormRuntimeParams)
+
+	if err := c.Bind(getScormRuntimeParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(getScormRuntimeParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if reason, err := ctr.Progression.UnlockedItemReason(userProfile.ID, getScormRuntimeParams.ModuleItemID); err != nil || reason != "" {
+		return ctr.lockedResponse(c, reason, err)
+	}
+
+	scormPackage, err := ctr.ScormRepo.GetScormPackageByModuleItemID(getScormRuntimeParams.ModuleItemID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "SCORM package not found",
+			})
+		}
+
+		ctr.Logger.Errorf("Failed to fetch SCORM package: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	scormRuntime, err := ctr.ScormRepo.GetScormRuntime(userProfile.ID, getScormRuntimeParams.ModuleItemID)
+	if err != nil && err.Error() != pg.ErrNoRows.Error() {
+		ctr.Logger.Errorf("Failed to fetch SCORM runtime: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	// Initializing starts a new session, the session time of the previous one is already in the total time
+	if scormRuntime.ID > 0 && scormRuntime.SessionTime > 0 {
+		scormRuntime.SessionTime = 0
+		if err := ctr.ScormRepo.SaveScormRuntime(&scormRuntime); err != nil {
+			ctr.Logger.Errorf("Failed to start SCORM session: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+	}
+
+	learnerName := strings.TrimSpace(userProfile.UserProfile.FirstName + " " + userProfile.UserProfile.LastName)
+	cmiValues := scorm.InitialCMI(scormPackage.Version, runtimeState(scormRuntime), userProfile.ID, learnerName)
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data: map[string]interface{}{
+			"version": scormPackage.Version,
+			"values":  cmiValues,
+		},
+	})
+}
+
+// CommitScormRuntime : persist the CMI data model sent by a SCORM package (LMSCommit / Commit)
+// Params : echo.Context
+// Returns : return error
+func (ctr *ScormController) CommitScormRuntime(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	commitScormRuntimeParams := new(param.CommitScormRuntimeParams)
+
+	if err := c.Bind(commitScormRuntimeParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(commitScormRuntimeParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if reason, err := ctr.Progression.UnlockedItemReason(userProfile.ID, commitScormRuntimeParams.ModuleItemID); err != nil || reason != "" {
+		return ctr.lockedResponse(c, reason, err)
+	}
+
+	scormPackage, err := ctr.ScormRepo.GetScormPackageByModuleItemID(commitScormRuntimeParams.ModuleItemID)
+	if err != nil {
+		if err.Error() == pg.ErrNoRows.Error() {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "SCORM package not found",
+			})
+		}
+
+		ctr.Logger.Errorf("Failed to fetch SCORM package: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	scormRuntime, err := ctr.ScormRepo.GetScormRuntime(userProfile.ID, commitScormRuntimeParams.ModuleItemID)
+	if err != nil {
+		if err.Error() != pg.ErrNoRows.Error() {
+			ctr.Logger.Errorf("Failed to fetch SCORM runtime: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+
+		scormRuntime = m.ScormRuntime{
+			UserID:       userProfile.ID,
+			ModuleItemID: commitScormRuntimeParams.ModuleItemID,
+		}
+	}
+
+	wasCompleted := !scormRuntime.CompletedAt.IsZero()
+
+	state := runtimeState(scormRuntime)
+	scorm.ApplyCMI(scormPackage.Version, &state, commitScormRuntimeParams.Values)
+
+	if scormRuntime.CmiData == nil {
+		scormRuntime.CmiData = make(map[string]string)
+	}
+	for key, value := range commitScormRuntimeParams.Values {
+		scormRuntime.CmiData[key] = value
+	}
+
+	scormRuntime.LessonStatus = state.LessonStatus
+	scormRuntime.CompletionStatus = state.CompletionStatus
+	scormRuntime.SuccessStatus = state.SuccessStatus
+	scormRuntime.ScoreRaw = state.ScoreRaw
+	scormRuntime.ScoreMin = state.ScoreMin
+	scormRuntime.ScoreMax = state.ScoreMax
+	scormRuntime.ScoreScaled = state.ScoreScaled
+	scormRuntime.SuspendData = state.SuspendData
+	scormRuntime.LessonLocation = state.LessonLocation
+	scormRuntime.TotalTime = state.TotalTime
+	scormRuntime.SessionTime = state.SessionTime
+
+	isCompleted := scorm.IsCompleted(scormPackage.Version, state)
+	if isCompleted && !wasCompleted {
+		scormRuntime.CompletedAt = utils.TimeNowUTC()
+	}
+
+	if err := ctr.ScormRepo.SaveScormRuntime(&scormRuntime); err != nil {
+		ctr.Logger.Errorf("Failed to save SCORM runtime: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to save SCORM data",
+		})
+	}
+
+	if isCompleted && !wasCompleted {
+		if err := ctr.Progression.AdvancePastItem(userProfile.ID, commitScormRuntimeParams.ModuleItemID); err != nil {
+			ctr.Logger.Errorf("Failed to update user progress after SCORM completion: %v", err)
+		}
+
+		statement := xapi.NewStatement(
+			xapi.NewAgent(userProfile.Email, userProfile.UserProfile.FirstName+" "+userProfile.UserProfile.LastName),
+			xapi.VerbCompleted,
+			xapi.NewActivity(xapi.ActivityTypeLesson, commitScormRuntimeParams.ModuleItemID, scormPackage.Title),
+		)
+		if scormRuntime.ScoreMax > 0 {
+			statement.Result = xapi.NewScoreResult(scormRuntime.ScoreRaw, scormRuntime.ScoreMax, nil, true)
+		}
+		if err := ctr.XapiRepo.RecordStatement(userProfile.ID, statement); err != nil {
+			ctr.Logger.Errorf("Failed to record xAPI statement: %v", err)
+		}
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "SCORM data saved",
+		Data: map[string]interface{}{
+			"completed": isCompleted,
+		},
+	})
+}
+
+// GetScormContent : serve a file of a SCORM package from the API origin, the package then reaches the runtime API
+// of the player frame. The launch URL signs the access of the user in its path, the item must still be unlocked.
+// Params : echo.Context
+// Returns : return error
+func (ctr *ScormController) GetScormContent(c echo.Context) error {
+	moduleItemID, err := strconv.Atoi(c.Param("module_item_id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "SCORM package not found",
+		})
+	}
+
+	userID, ok := scorm.ParseContentToken(utils.GetKeyToken(), c.Param("token"), moduleItemID, utils.TimeNowUTC())
+	if !ok {
+		return c.JSON(http.StatusForbidden, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "The launch URL is invalid or has expired",
+		})
+	}
+
+	if reason, err := ctr.Progression.UnlockedItemReason(userID, moduleItemID); err != nil || reason != "" {
+		if err != nil {
+			ctr.Logger.Errorf("Failed to check access to module item %d: %v", moduleItemID, err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "System Error",
+			})
+		}
+		return c.JSON(http.StatusForbidden, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: reason,
+		})
+	}
+
+	scormPackage, err := ctr.ScormRepo.GetScormPackageByModuleItemID(moduleItemID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "SCORM package not found",
+		})
+	}
+
+	// Only the files extracted from the package are served
+	filePath, err := url.PathUnescape(c.Param("*"))
+	if _, found := utils.FindStringInArray(scormPackage.Files, filePath); err != nil || !found {
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "File not found",
+		})
+	}
+
+	data, err := ctr.cloud.GetFileByFileName(filePath, cf.ScormFolderGCS+scormPackage.BasePath+"/")
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch SCORM file %s: %v", filePath, err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "File not found",
+		})
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(filePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// lockedResponse : respond that the SCORM item can not be accessed, or with a system error
+func (ctr *ScormController) lockedResponse(c echo.Context, reason string, err error) error {
+	if err != nil {
+		ctr.Logger.Errorf("Failed to check access to SCORM item: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "System Error",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.FailResponseCode,
+		Message: reason,
+	})
+}
+
+func runtimeState(scormRuntime m.ScormRuntime) scorm.RuntimeState {
+	return scorm.RuntimeState{
+		LessonStatus:     scormRuntime.LessonStatus,
+		CompletionStatus: scormRuntime.CompletionStatus,
+		SuccessStatus:    scormRuntime.SuccessStatus,
+		ScoreRaw:         scormRuntime.ScoreRaw,
+		ScoreMin:         scormRuntime.ScoreMin,
+		ScoreMax:         scormRuntime.ScoreMax,
+		ScoreScaled:      scormRuntime.ScoreScaled,
+		SuspendData:      scormRuntime.SuspendData,
+		LessonLocation:   scormRuntime.LessonLocation,
+		TotalTime:        scormRuntime.TotalTime,
+		SessionTime:      scormRuntime.SessionTime,
+	}
+}
@@ -0,0 +1,92 @@
+package scorm
+
+import (
+	cm "orientation-training-api/internal/common"
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+type PgScormRepository struct {
+	cm.AppRepository
+}
+
+func NewPgScormRepository(logger echo.Logger) (repo *PgScormRepository) {
+	repo = &PgScormRepository{}
+	repo.Init(logger)
+	return
+}
+
+// SaveScormPackage : insert the manifest information of a SCORM module item
+func (repo *PgScormRepository) SaveScormPackage(scormPackage *m.ScormPackage) error {
+	_, err := repo.DB.Model(scormPackage).Insert()
+	return err
+}
+
+// GetScormPackageByModuleItemID : get the SCORM package attached to a module item
+func (repo *PgScormRepository) GetScormPackageByModuleItemID(moduleItemID int) (m.ScormPackage, error) {
+	scormPackage := m.ScormPackage{}
+	err := repo.DB.Model(&scormPackage).
+		Where("module_item_id = ?", moduleItemID).
+		Where("deleted_at is null").
+		First()
+
+	return scormPackage, err
+}
+
+// DeleteScormPackageByModuleItemID : delete the SCORM package attached to a module item
+func (repo *PgScormRepository) DeleteScormPackageByModuleItemID(moduleItemID int) error {
+	_, err := repo.DB.Model((*m.ScormPackage)(nil)).
+		Where("module_item_id = ?", moduleItemID).
+		Delete()
+
+	return err
+}
+
+// GetScormRuntime : get the CMI data of a user for a SCORM module item
+func (repo *PgScormRepository) GetScormRuntime(userID int, moduleItemID int) (m.ScormRuntime, error) {
+	scormRuntime := m.ScormRuntime{}
+	err := repo.DB.Model(&scormRuntime).
+		Where("user_id = ?", userID).
+		Where("module_item_id = ?", moduleItemID).
+		Where("deleted_at is null").
+		First()
+
+	return scormRuntime, err
+}
+
+// SaveScormRuntime : create or update the CMI data of a user for a SCORM module item
+func (repo *PgScormRepository) SaveScormRuntime(scormRuntime *m.ScormRuntime) error {
+	if scormRuntime.ID > 0 {
+		_, err := repo.DB.Model(scormRuntime).
+			Column("lesson_status", "completion_status", "success_status",
+				"score_raw", "score_min", "score_max", "score_scaled",
+				"suspend_data", "lesson_location", "total_time", "session_time", "cmi_data",
+				"completed_at", "updated_at").
+			WherePK().
+			Update()
+		return err
+	}
+
+	_, err := repo.DB.Model(scormRuntime).Insert()
+	return err
+}
+
+// ResetScormRuntimesWithTx : set aside the CMI data of a user for SCORM module items so that they start over,
+// the previous data is kept as deleted rows
+func (repo *PgScormRepository) ResetScormRuntimesWithTx(tx *pg.Tx, userID int, moduleItemIDs []int) error {
+	if len(moduleItemIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Model((*m.ScormRuntime)(nil)).
+		Where("user_id = ?", userID).
+		Where("module_item_id IN (?)", pg.In(moduleItemIDs)).
+		Delete()
+	if err != nil {
+		repo.Logger.Errorf("Error resetting SCORM runtimes of user %d: %v", userID, err)
+	}
+
+	return err
+}
@@ -1,13 +1,16 @@
 package templatepaths
 
 import (
+	"fmt"
 	"net/http"
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
 	rp "orientation-training-api/internal/interfaces/repository"
 	param "orientation-training-api/internal/interfaces/requestparams"
+	"strings"
 
 	valid "github.com/asaskevich/govalidator"
+	"github.com/go-pg/pg/v9"
 	"github.com/labstack/echo/v4"
 )
 
@@ -71,15 +74,24 @@ func (ctr *TemplatePathController) GetTemplatePath(c echo.Context) error {
 	}
 
 	courseDetails := []map[string]interface{}{}
-	for _, courseID := range templatePath.CourseIds {
-		course, err := ctr.CourseRepo.GetCourseByID(courseID)
-		if err != nil {
-			ctr.Logger.Warnf("Course ID %d in path %d not found: %v", courseID, templatePath.ID, err)
+	for _, pathCourse := range templatePath.PathCourses {
+		course, err := ctr.CourseRepo.GetCourseByID(pathCourse.CourseID)
+		if err != nil && err.Error() == pg.ErrNoRows.Error() {
+			ctr.Logger.Warnf("Course ID %d in path %d not found: %v", pathCourse.CourseID, templatePath.ID, err)
 			continue
 		}
+		if err != nil {
+			ctr.Logger.Errorf("Failed to fetch course %d of path %d: %v", pathCourse.CourseID, templatePath.ID, err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to fetch template path courses",
+			})
+		}
 
 		courseDetails = append(courseDetails, map[string]interface{}{
 			"course_id":   course.ID,
+			"stage_id":    pathCourse.StageID,
+			"position":    pathCourse.Position,
 			"title":       course.Title,
 			"thumbnail":   course.Thumbnail,
 			"category":    course.Category,
@@ -93,6 +105,7 @@ func (ctr *TemplatePathController) GetTemplatePath(c echo.Context) error {
 		"name":        templatePath.Name,
 		"description": templatePath.Description,
 		"course_ids":  templatePath.CourseIds,
+		"stages":      templatePath.Stages,
 		"course_list": courseDetails,
 		"duration":    templatePath.Duration,
 	}
@@ -123,21 +136,14 @@ func (ctr *TemplatePathController) CreateTemplatePath(c echo.Context) error {
 		})
 	}
 
-	totalDuration := 0
-	for _, courseID := range createTemplatePathParams.CourseIds {
-		course, err := ctr.CourseRepo.GetCourseByID(courseID)
-		if err != nil {
-			ctr.Logger.Errorf("Course ID %d not found: %v", courseID, err)
-			return c.JSON(http.StatusOK, cf.JsonResponse{
-				Status:  cf.FailResponseCode,
-				Message: "One or more courses do not exist",
-			})
-		}
-		totalDuration += course.Duration
+	createTemplatePathParams.Stages = normalizePathStages(createTemplatePathParams.CourseIds, createTemplatePathParams.Stages)
+	if message := ctr.validatePathStages(createTemplatePathParams.Stages); message != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: message,
+		})
 	}
 
-	createTemplatePathParams.Duration = totalDuration
-
 	templatePath, err := ctr.TempPathRepo.CreateTemplatePath(createTemplatePathParams)
 	if err != nil {
 		ctr.Logger.Errorf("Failed to create template path: %v", err)
@@ -182,21 +188,14 @@ func (ctr *TemplatePathController) UpdateTemplatePath(c echo.Context) error {
 		})
 	}
 
-	if len(updatePathParams.CourseIds) > 0 {
-		totalDuration := 0
-		for _, courseID := range updatePathParams.CourseIds {
-			course, err := ctr.CourseRepo.GetCourseByID(courseID)
-			if err != nil {
-				ctr.Logger.Errorf("Course ID %d not found: %v", courseID, err)
-				return c.JSON(http.StatusOK, cf.JsonResponse{
-					Status:  cf.FailResponseCode,
-					Message: "One or more courses do not exist",
-				})
-			}
-			totalDuration += course.Duration
+	if len(updatePathParams.CourseIds) > 0 || len(updatePathParams.Stages) > 0 {
+		updatePathParams.Stages = normalizePathStages(updatePathParams.CourseIds, updatePathParams.Stages)
+		if message := ctr.validatePathStages(updatePathParams.Stages); message != "" {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: message,
+			})
 		}
-
-		updatePathParams.Duration = totalDuration
 	}
 
 	if updatePathParams.Name == "" {
@@ -264,3 +263,82 @@ func (ctr *TemplatePathController) DeleteTemplatePath(c echo.Context) error {
 		Message: "Template path deleted successfully",
 	})
 }
+
+// normalizePathStages turns the course_ids of a path without stages into a single required stage
+// and fills in the default stage names and types
+func normalizePathStages(courseIDs []int, stages []param.TemplatePathStageParams) []param.TemplatePathStageParams {
+	if len(stages) == 0 && len(courseIDs) > 0 {
+		stages = []param.TemplatePathStageParams{{CourseIds: courseIDs}}
+	}
+
+	for i := range stages {
+		stages[i].Name = strings.TrimSpace(stages[i].Name)
+		if stages[i].Name == "" {
+			stages[i].Name = fmt.Sprintf("Stage %d", i+1)
+		}
+
+		if stages[i].StageType == "" {
+			stages[i].StageType = cf.StageTypeRequired
+		}
+
+		if stages[i].StageType == cf.StageTypeRequired {
+			stages[i].RequiredCount = 0
+		}
+	}
+
+	return stages
+}
+
+// validatePathStages checks the stages of a path, a path needs a required stage
+// because its assignment is tracked through the enrollments of the required courses
+func (ctr *TemplatePathController) validatePathStages(stages []param.TemplatePathStageParams) string {
+	if len(stages) == 0 {
+		return "A template path needs at least one stage"
+	}
+
+	courseIDs := []int{}
+	hasRequiredStage := false
+	for _, stage := range stages {
+		if stage.StageType != cf.StageTypeRequired && stage.StageType != cf.StageTypeElective {
+			return "Stage type must be required or elective"
+		}
+
+		if len(stage.CourseIds) == 0 {
+			return "Every stage needs at least one course"
+		}
+
+		if stage.StageType == cf.StageTypeElective && (stage.RequiredCount < 1 || stage.RequiredCount > len(stage.CourseIds)) {
+			return "required_count of an elective stage must be between 1 and its number of courses"
+		}
+
+		if stage.StageType == cf.StageTypeRequired {
+			hasRequiredStage = true
+		}
+
+		courseIDs = append(courseIDs, stage.CourseIds...)
+	}
+
+	if !hasRequiredStage {
+		return "A template path needs at least one required stage"
+	}
+
+	return ctr.validatePathCourses(courseIDs)
+}
+
+// validatePathCourses checks that the courses of a path exist and are not repeated
+func (ctr *TemplatePathController) validatePathCourses(courseIDs []int) string {
+	seen := make(map[int]bool)
+	for _, courseID := range courseIDs {
+		if seen[courseID] {
+			return "A course can only appear once in a template path"
+		}
+		seen[courseID] = true
+
+		if _, err := ctr.CourseRepo.GetCourseByID(courseID); err != nil {
+			ctr.Logger.Errorf("Course ID %d not found: %v", courseID, err)
+			return "One or more courses do not exist"
+		}
+	}
+
+	return ""
+}
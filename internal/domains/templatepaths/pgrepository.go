@@ -5,6 +5,8 @@ import (
 	param "orientation-training-api/internal/interfaces/requestparams"
 	m "orientation-training-api/internal/models"
 
+	"github.com/go-pg/pg/v9"
+	"github.com/go-pg/pg/v9/orm"
 	"github.com/labstack/echo/v4"
 )
 
@@ -18,15 +20,19 @@ func NewPgTemplatePathRepository(logger echo.Logger) (repo *PgTemplatePathReposi
 	return
 }
 
-// GetTemplatePathByID retrieves a template path by its ID
+// GetTemplatePathByID retrieves a template path by its ID with its stages and courses in path order
 func (repo *PgTemplatePathRepository) GetTemplatePathByID(tempPathID int) (m.TemplatePath, error) {
 	tempPath := m.TemplatePath{}
 
 	err := repo.DB.Model(&tempPath).
-		Where("id = ?", tempPathID).
-		Where("deleted_at IS NULL").
+		Relation("Stages", orderByPosition).
+		Relation("PathCourses", orderByPosition).
+		Where("template_path.id = ?", tempPathID).
+		Where("template_path.deleted_at IS NULL").
 		First()
 
+	setCourseIDs(&tempPath)
+
 	return tempPath, err
 }
 
@@ -35,8 +41,10 @@ func (repo *PgTemplatePathRepository) GetTemplatePathList() ([]m.TemplatePath, e
 	var tempPaths []m.TemplatePath
 
 	err := repo.DB.Model(&tempPaths).
-		Where("deleted_at IS NULL").
-		Order("created_at DESC").
+		Relation("Stages", orderByPosition).
+		Relation("PathCourses", orderByPosition).
+		Where("template_path.deleted_at IS NULL").
+		Order("template_path.created_at DESC").
 		Select()
 
 	if err != nil {
@@ -44,39 +52,65 @@ func (repo *PgTemplatePathRepository) GetTemplatePathList() ([]m.TemplatePath, e
 		return nil, err
 	}
 
+	for i := range tempPaths {
+		setCourseIDs(&tempPaths[i])
+	}
+
 	return tempPaths, nil
 }
 
-// CreateTemplatePath creates a new template path
+// CreateTemplatePath creates a new template path, its duration is maintained by database triggers
 func (repo *PgTemplatePathRepository) CreateTemplatePath(createTemplatePathParams *param.CreateTemplatePathParams) (m.TemplatePath, error) {
 	templatePath := m.TemplatePath{
 		Name:        createTemplatePathParams.Name,
 		Description: createTemplatePathParams.Description,
-		CourseIds:   createTemplatePathParams.CourseIds,
-		Duration:    createTemplatePathParams.Duration,
 	}
 
-	err := repo.DB.Insert(&templatePath)
-	return templatePath, err
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if err := tx.Insert(&templatePath); err != nil {
+			return err
+		}
+
+		return replacePathStages(tx, templatePath.ID, createTemplatePathParams.Stages)
+	})
+	if err != nil {
+		repo.Logger.Errorf("Error creating template path: %v", err)
+		return templatePath, err
+	}
+
+	return repo.GetTemplatePathByID(templatePath.ID)
 }
 
-// UpdateTemplatePath updates an existing template path
+// UpdateTemplatePath updates an existing template path, the stages are replaced when stages are given
 func (repo *PgTemplatePathRepository) UpdateTemplatePath(updateTemplatePathParams *param.UpdateTemplatePathParams) (m.TemplatePath, error) {
 	tempPath := m.TemplatePath{
 		ID:          updateTemplatePathParams.TempPathID,
 		Name:        updateTemplatePathParams.Name,
 		Description: updateTemplatePathParams.Description,
-		CourseIds:   updateTemplatePathParams.CourseIds,
-		Duration:    updateTemplatePathParams.Duration,
 	}
 
-	_, err := repo.DB.Model(&tempPath).
-		Where("id = ?", tempPath.ID).
-		Where("deleted_at IS NULL").
-		Column("name", "description", "course_ids", "duration").
-		Update()
+	err := repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		_, err := tx.Model(&tempPath).
+			Where("id = ?", tempPath.ID).
+			Where("deleted_at IS NULL").
+			Column("name", "description").
+			Update()
+		if err != nil {
+			return err
+		}
+
+		if len(updateTemplatePathParams.Stages) == 0 {
+			return nil
+		}
+
+		return replacePathStages(tx, tempPath.ID, updateTemplatePathParams.Stages)
+	})
+	if err != nil {
+		repo.Logger.Errorf("Error updating template path %d: %v", tempPath.ID, err)
+		return tempPath, err
+	}
 
-	return tempPath, err
+	return repo.GetTemplatePathByID(tempPath.ID)
 }
 
 // DeleteTemplatePath soft deletes a template path
@@ -88,3 +122,67 @@ func (repo *PgTemplatePathRepository) DeleteTemplatePath(tempPathID int) error {
 		Update()
 	return err
 }
+
+// replacePathStages replaces the stages and courses of a path, course positions are numbered
+// across the whole path following the order of the stages
+func replacePathStages(tx *pg.Tx, tempPathID int, stages []param.TemplatePathStageParams) error {
+	if _, err := tx.Exec("DELETE FROM template_path_courses WHERE template_path_id = ?", tempPathID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM template_path_stages WHERE template_path_id = ?", tempPathID); err != nil {
+		return err
+	}
+
+	position := 0
+	for i, stageParams := range stages {
+		stage := m.TemplatePathStage{
+			TemplatePathID: tempPathID,
+			Name:           stageParams.Name,
+			Position:       i + 1,
+			StageType:      stageParams.StageType,
+			RequiredCount:  stageParams.RequiredCount,
+		}
+
+		if err := tx.Insert(&stage); err != nil {
+			return err
+		}
+
+		for _, courseID := range stageParams.CourseIds {
+			position++
+			pathCourse := m.TemplatePathCourse{
+				TemplatePathID: tempPathID,
+				StageID:        stage.ID,
+				CourseID:       courseID,
+				Position:       position,
+			}
+
+			if err := tx.Insert(&pathCourse); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func orderByPosition(q *orm.Query) (*orm.Query, error) {
+	return q.Order("position ASC"), nil
+}
+
+func setCourseIDs(tempPath *m.TemplatePath) {
+	stageIndexes := make(map[int]int)
+	for i := range tempPath.Stages {
+		tempPath.Stages[i].CourseIds = []int{}
+		stageIndexes[tempPath.Stages[i].ID] = i
+	}
+
+	tempPath.CourseIds = []int{}
+	for _, pathCourse := range tempPath.PathCourses {
+		tempPath.CourseIds = append(tempPath.CourseIds, pathCourse.CourseID)
+
+		if i, ok := stageIndexes[pathCourse.StageID]; ok {
+			tempPath.Stages[i].CourseIds = append(tempPath.Stages[i].CourseIds, pathCourse.CourseID)
+		}
+	}
+}
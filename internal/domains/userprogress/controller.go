@@ -4,11 +4,17 @@ import (
 	"net/http"
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/enrollment"
+	"orientation-training-api/internal/domains/progression"
 	rp "orientation-training-api/internal/interfaces/repository"
 	param "orientation-training-api/internal/interfaces/requestparams"
+	"orientation-training-api/internal/interfaces/response"
 	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"orientation-training-api/internal/platform/xapi"
 
 	valid "github.com/asaskevich/govalidator"
+	"github.com/go-pg/pg/v9"
 	"github.com/labstack/echo/v4"
 )
 
@@ -19,16 +25,21 @@ type UserProgressController struct {
 	ModuleRepo       rp.ModuleRepository
 	ModuleItemRepo   rp.ModuleItemRepository
 	UserRepo         rp.UserRepository
+	XapiRepo         rp.XapiRepository
+	PrerequisiteRepo rp.CoursePrerequisiteRepository
+	TemplatePathRepo rp.TemplatePathRepository
+	Enrollment       *enrollment.Service
+	Progression      *progression.Service
 }
 
-func NewUserProgressController(logger echo.Logger, userProgressRepo rp.UserProgressRepository, moduleRepo rp.ModuleRepository, moduleItemRepo rp.ModuleItemRepository, userRepo rp.UserRepository) (ctr *UserProgressController) {
-	ctr = &UserProgressController{cm.BaseController{}, userProgressRepo, moduleRepo, moduleItemRepo, userRepo}
+func NewUserProgressController(logger echo.Logger, userProgressRepo rp.UserProgressRepository, moduleRepo rp.ModuleRepository, moduleItemRepo rp.ModuleItemRepository, userRepo rp.UserRepository, xapiRepo rp.XapiRepository, prerequisiteRepo rp.CoursePrerequisiteRepository, templatePathRepo rp.TemplatePathRepository, enrollmentService *enrollment.Service, progressionService *progression.Service) (ctr *UserProgressController) {
+	ctr = &UserProgressController{cm.BaseController{}, userProgressRepo, moduleRepo, moduleItemRepo, userRepo, xapiRepo, prerequisiteRepo, templatePathRepo, enrollmentService, progressionService}
 	ctr.Init(logger)
 	return
 }
 
-// UpdateUserProgress updates a user's progress in a course
-// Can both track progress and mark a course as completed
+// UpdateUserProgress moves a user's progress in a course to the next item
+// The current item must be verified server-side, completion is derived from the last item
 // Params: echo.Context
 // Returns: error
 func (ctr *UserProgressController) UpdateUserProgress(c echo.Context) error {
@@ -59,53 +70,173 @@ func (ctr *UserProgressController) UpdateUserProgress(c echo.Context) error {
 		targetUserID = updateUserProgressParams.UserID
 	}
 
-	userProgress := &m.UserProgress{
-		UserID:         targetUserID,
-		CourseID:       updateUserProgressParams.CourseID,
-		CoursePosition: updateUserProgressParams.CoursePosition,
+	userProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(targetUserID, updateUserProgressParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch existing progress: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "User progress not found",
+		})
 	}
 
-	if updateUserProgressParams.Completed {
-		currentProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(targetUserID, updateUserProgressParams.CourseID)
+	// Positions at or before the cursor are revisits and leave the progress untouched,
+	// the cursor moves at most one item per request once the current item is verified.
+	// A given module item is completed instead when it is unlocked, whatever the cursor.
+	requestsAdvance := updateUserProgressParams.Completed ||
+		updateUserProgressParams.ModuleItemID > 0 ||
+		updateUserProgressParams.ModulePosition > userProgress.ModulePosition ||
+		(updateUserProgressParams.ModulePosition == userProgress.ModulePosition && updateUserProgressParams.ModuleItemPosition > userProgress.ModuleItemPosition)
+
+	if requestsAdvance && !userProgress.Completed {
+		var reason string
+		if updateUserProgressParams.ModuleItemID > 0 {
+			reason, err = ctr.Progression.CompleteUnlockedItem(&userProgress, updateUserProgressParams.ModuleItemID)
+		} else {
+			reason, err = ctr.Progression.AdvanceUserProgress(&userProgress)
+		}
 		if err != nil {
-			ctr.Logger.Errorf("Failed to fetch existing progress: %v", err)
+			ctr.Logger.Errorf("Failed to advance user progress: %v", err)
 			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
 				Status:  cf.FailResponseCode,
-				Message: "Failed to fetch user progress",
+				Message: "Failed to update progress",
+			})
+		}
+
+		if reason != "" {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: reason,
+				Data:    progressPositions(userProgress),
 			})
 		}
 
-		userProgress.ModulePosition = currentProgress.ModulePosition
-		userProgress.ModuleItemPosition = currentProgress.ModuleItemPosition
-		userProgress.Completed = true
-		userProgress.CompletedDate = updateUserProgressParams.CompletedDate
-	} else {
-		userProgress.ModulePosition = updateUserProgressParams.ModulePosition
-		userProgress.ModuleItemPosition = updateUserProgressParams.ModuleItemPosition
-		userProgress.Completed = false
+		ctr.recordProgressStatement(userProfile, &userProgress)
 	}
 
-	err := ctr.UserProgressRepo.SaveUserProgress(userProgress)
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Progress updated successfully",
+		Data:    progressPositions(userProgress),
+	})
+}
+
+// ViewItem records that the user opened a module item, locked items are rejected
+// Params: echo.Context
+// Returns: error
+func (ctr *UserProgressController) ViewItem(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	viewItemParams := new(param.ViewModuleItemParams)
+
+	if err := c.Bind(viewItemParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(viewItemParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	reason, err := ctr.Progression.ViewItem(userProfile.ID, viewItemParams.ModuleItemID)
 	if err != nil {
-		ctr.Logger.Errorf("Failed to save user progress: %v", err)
+		ctr.Logger.Errorf("Failed to record item view: %v", err)
 		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "Failed to update progress",
+			Message: "Failed to record item view",
+		})
+	}
+
+	if reason != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: reason,
 		})
 	}
 
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
-		Message: "Progress updated successfully",
-		Data: map[string]interface{}{
-			"course_position":      userProgress.CoursePosition,
-			"module_position":      userProgress.ModulePosition,
-			"module_item_position": userProgress.ModuleItemPosition,
-			"completed":            userProgress.Completed,
-		},
+		Message: "Item view recorded",
 	})
 }
 
+// RecordHeartbeat records a heartbeat of the lecture player and returns the time tracked on the lecture
+// Params: echo.Context
+// Returns: error
+func (ctr *UserProgressController) RecordHeartbeat(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	heartbeatParams := new(param.HeartbeatParams)
+
+	if err := c.Bind(heartbeatParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(heartbeatParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if heartbeatParams.Position < 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "position can not be negative",
+		})
+	}
+
+	heartbeat, reason, err := ctr.Progression.RecordHeartbeat(userProfile.ID, heartbeatParams.ModuleItemID, heartbeatParams.Position)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to record heartbeat: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to record heartbeat",
+		})
+	}
+
+	if reason != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: reason,
+		})
+	}
+
+	if heartbeat.Flagged {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "Playback moved faster than allowed, this interval was not counted",
+			Data:    heartbeat,
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Heartbeat recorded",
+		Data:    heartbeat,
+	})
+}
+
+// progressPositions returns the cursor of a progress as sent back to the lecture page
+func progressPositions(userProgress m.UserProgress) map[string]interface{} {
+	return map[string]interface{}{
+		"course_position":      userProgress.CoursePosition,
+		"module_position":      userProgress.ModulePosition,
+		"module_item_position": userProgress.ModuleItemPosition,
+		"completed":            userProgress.Completed,
+		"completed_date":       userProgress.CompletedDate,
+	}
+}
+
 // GetAllUserProgressByUserID is now modified to get progress for ALL courses of a user
 // Params: echo.Context
 // Returns: error
@@ -224,39 +355,42 @@ func (ctr *UserProgressController) AddUserProgress(c echo.Context) error {
 	}
 
 	targetUserID := createUserProgressParams.UserID
-	successCourses := []int{}
-
-	for i, courseID := range createUserProgressParams.CourseIDs {
-		coursePosition := i + 1
-
-		existingProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(targetUserID, courseID)
-		if err == nil && existingProgress.ID != 0 {
-			ctr.Logger.Infof("Progress already exists for user %d and course %d", targetUserID, courseID)
-			continue
-		}
-
-		userProgress := &m.UserProgress{
-			UserID:             targetUserID,
-			CourseID:           courseID,
-			CoursePosition:     coursePosition,
-			ModulePosition:     1,
-			ModuleItemPosition: 1,
-			Completed:          false,
-		}
 
-		err = ctr.UserProgressRepo.SaveUserProgress(userProgress)
-		if err != nil {
-			ctr.Logger.Errorf("Failed to create user progress for course %d: %v", courseID, err)
-			continue
-		}
+	dueDate, err := ctr.Enrollment.ResolveDueDate(createUserProgressParams.DueDateParams, targetUserID)
+	if err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
 
-		successCourses = append(successCourses, courseID)
+	successCourses, blockedEnrollments, err := ctr.Enrollment.EnrollCourses(targetUserID, createUserProgressParams.CourseIDs, dueDate, userProfile.ID, 0)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to check course prerequisites: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to check course prerequisites",
+		})
 	}
 
 	if len(successCourses) == 0 {
 		return c.JSON(http.StatusOK, cf.JsonResponse{
 			Status:  cf.FailResponseCode,
-			Message: "No courses were added. Courses may already be assigned or an error occurred.",
+			Message: "No courses were added. Courses may already be assigned, have missing prerequisites or an error occurred.",
+			Data: map[string]interface{}{
+				"blocked_courses": blockedEnrollments,
+			},
+		})
+	}
+
+	if len(blockedEnrollments) > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "Some courses were not added because their prerequisites are not completed",
+			Data: map[string]interface{}{
+				"added_courses":   successCourses,
+				"blocked_courses": blockedEnrollments,
+			},
 		})
 	}
 
@@ -314,12 +448,16 @@ func (ctr *UserProgressController) GetListTraineeByCourseID(c echo.Context) erro
 
 	for _, trainee := range trainees {
 		status := cf.NotAssigned
+		dueDate := ""
+		overdue := false
 		if progress, exists := userProgressMap[trainee.ID]; exists {
 			if progress.Completed {
 				status = cf.Completed
 			} else {
 				status = cf.InProgress
 			}
+			dueDate = utils.FormatDueDate(progress.DueDate)
+			overdue = utils.IsOverdue(progress.DueDate, progress.Completed)
 		}
 
 		traineeInfo := map[string]interface{}{
@@ -328,6 +466,8 @@ func (ctr *UserProgressController) GetListTraineeByCourseID(c echo.Context) erro
 			"email":      trainee.UserProfile.PersonalEmail,
 			"department": trainee.UserProfile.Department,
 			"status":     status,
+			"due_date":   dueDate,
+			"overdue":    overdue,
 		}
 
 		traineeInfoList = append(traineeInfoList, traineeInfo)
@@ -342,6 +482,7 @@ func (ctr *UserProgressController) GetListTraineeByCourseID(c echo.Context) erro
 
 // AddListTraineeToCourse adds multiple trainees to a course
 func (ctr *UserProgressController) AddListTraineeToCourse(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
 	addListTraineeToCourseParams := new(param.AddListTraineeToCourseParams)
 	if err := c.Bind(addListTraineeToCourseParams); err != nil {
 		return c.JSON(http.StatusBadRequest, cf.JsonResponse{
@@ -357,19 +498,48 @@ func (ctr *UserProgressController) AddListTraineeToCourse(c echo.Context) error
 		})
 	}
 
+	if err := enrollment.ValidateDueDateParams(addListTraineeToCourseParams.DueDateParams); err != nil {
+		return c.JSON(http.StatusBadRequest, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	blockedEnrollments := []response.BlockedEnrollmentResponse{}
+	dueDateErrors := map[int]string{}
+	skippedTrainees := []int{}
+
+	// Trainees already enrolled keep their progress, trainees enrolled again start a new cycle
 	for _, traineeID := range addListTraineeToCourseParams.Trainees {
-		progress := &m.UserProgress{
-			UserID:             traineeID,
-			CourseID:           addListTraineeToCourseParams.CourseID,
-			ModulePosition:     1,
-			ModuleItemPosition: 1,
-			Completed:          false,
+		dueDate, err := ctr.Enrollment.ResolveDueDate(addListTraineeToCourseParams.DueDateParams, traineeID)
+		if err != nil {
+			dueDateErrors[traineeID] = err.Error()
+			continue
 		}
 
-		if err := ctr.UserProgressRepo.SaveUserProgress(progress); err != nil {
-			ctr.Logger.Errorf("Failed to add trainee %d to course %d: %v", traineeID, addListTraineeToCourseParams.CourseID, err)
+		addedCourses, blocked, err := ctr.Enrollment.EnrollCourses(traineeID, []int{addListTraineeToCourseParams.CourseID}, dueDate, userProfile.ID, 0)
+		if err != nil {
+			ctr.Logger.Errorf("Failed to check prerequisites of course %d for trainee %d: %v", addListTraineeToCourseParams.CourseID, traineeID, err)
+			skippedTrainees = append(skippedTrainees, traineeID)
 			continue
 		}
+
+		blockedEnrollments = append(blockedEnrollments, blocked...)
+		if len(addedCourses) == 0 && len(blocked) == 0 {
+			skippedTrainees = append(skippedTrainees, traineeID)
+		}
+	}
+
+	if len(blockedEnrollments) > 0 || len(dueDateErrors) > 0 || len(skippedTrainees) > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "Some trainees were not added because they are already enrolled, miss prerequisites or have an invalid due date",
+			Data: map[string]interface{}{
+				"blocked_trainees": blockedEnrollments,
+				"due_date_errors":  dueDateErrors,
+				"skipped_trainees": skippedTrainees,
+			},
+		})
 	}
 
 	return c.JSON(http.StatusOK, cf.JsonResponse{
@@ -378,6 +548,223 @@ func (ctr *UserProgressController) AddListTraineeToCourse(c echo.Context) error
 	})
 }
 
+// AssignTemplatePath enrolls one or many trainees in the courses of a template path
+// The course position follows the order of the path
+// Params: echo.Context
+// Returns: error
+func (ctr *UserProgressController) AssignTemplatePath(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	assignParams := new(param.AssignTemplatePathParams)
+
+	if err := c.Bind(assignParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(assignParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if err := enrollment.ValidateDueDateParams(assignParams.DueDateParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	templatePath, err := ctr.TemplatePathRepo.GetTemplatePathByID(assignParams.TempPathID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch template path %d: %v", assignParams.TempPathID, err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Template path not found",
+		})
+	}
+
+	if len(enrollment.PathRequiredCourseIDs(templatePath)) == 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Template path has no required courses",
+		})
+	}
+
+	assignments := []response.PathAssignmentResponse{}
+	hasFailure := false
+
+	for _, userID := range assignParams.UserIDs {
+		assignment := ctr.Enrollment.AssignTemplatePath(userID, templatePath, assignParams.DueDateParams, userProfile.ID)
+		if assignment.Error != "" || len(assignment.BlockedCourses) > 0 {
+			hasFailure = true
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	if hasFailure {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "Template path assigned with some courses not added",
+			Data:    assignments,
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Template path assigned successfully",
+		Data:    assignments,
+	})
+}
+
+// GetTemplatePathProgress returns the stages of an assigned template path with their completion
+// and the electives the trainee can still choose
+// Params: echo.Context
+// Returns: error
+func (ctr *UserProgressController) GetTemplatePathProgress(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	pathProgressParams := new(param.TemplatePathProgressParams)
+
+	if err := c.Bind(pathProgressParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(pathProgressParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	targetUserID := userProfile.ID
+
+	if userProfile.RoleID == cf.ManagerRoleID && pathProgressParams.UserID > 0 {
+		targetUserID = pathProgressParams.UserID
+	}
+
+	templatePath, err := ctr.TemplatePathRepo.GetTemplatePathByID(pathProgressParams.TempPathID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch template path %d: %v", pathProgressParams.TempPathID, err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Template path not found",
+		})
+	}
+
+	userProgresses, err := ctr.UserRepo.GetUserProgressByUserID(targetUserID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch user progress list: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch user progress list",
+		})
+	}
+
+	assigned := false
+	for _, progress := range userProgresses {
+		if progress.TemplatePathID == templatePath.ID {
+			assigned = true
+			break
+		}
+	}
+
+	if !assigned {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Template path is not assigned to this user",
+		})
+	}
+
+	stages := enrollment.EvaluatePathStages(templatePath, userProgresses)
+	completed := true
+	for _, stage := range stages {
+		if !stage.Completed {
+			completed = false
+			break
+		}
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Template path progress retrieved successfully",
+		Data: map[string]interface{}{
+			"template_path_id": templatePath.ID,
+			"name":             templatePath.Name,
+			"user_id":          targetUserID,
+			"completed":        completed,
+			"stages":           stages,
+		},
+	})
+}
+
+// ChooseElective enrolls the trainee in an elective course of an assigned template path
+// Params: echo.Context
+// Returns: error
+func (ctr *UserProgressController) ChooseElective(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	chooseElectiveParams := new(param.ChooseElectiveParams)
+
+	if err := c.Bind(chooseElectiveParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(chooseElectiveParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	templatePath, err := ctr.TemplatePathRepo.GetTemplatePathByID(chooseElectiveParams.TempPathID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch template path %d: %v", chooseElectiveParams.TempPathID, err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Template path not found",
+		})
+	}
+
+	assignment, err := ctr.Enrollment.ChooseElective(userProfile.ID, templatePath, chooseElectiveParams.CourseID)
+	if err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if assignment.Error != "" || len(assignment.BlockedCourses) > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Elective could not be added",
+			Data:    assignment,
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Elective added successfully",
+		Data:    assignment,
+	})
+}
+
 // ReviewProgress allows managers to review the progress of trainees
 func (ctr *UserProgressController) ReviewProgress(c echo.Context) error {
 	userProfile := c.Get("user_profile").(m.User)
@@ -430,3 +817,314 @@ func (ctr *UserProgressController) ReviewProgress(c echo.Context) error {
 		Message: "User progress reviewed successfully",
 	})
 }
+
+// ResetProgress restarts the enrollment of a trainee from the beginning in a new cycle,
+// the current cycle is kept in the progress history
+func (ctr *UserProgressController) ResetProgress(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+
+	resetProgressParams := new(param.ResetProgressParams)
+
+	if err := c.Bind(resetProgressParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(resetProgressParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	userProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(resetProgressParams.UserID, resetProgressParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("User progress not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "User progress not found",
+		})
+	}
+
+	dueDate, err := ctr.Enrollment.ResolveDueDate(resetProgressParams.DueDateParams, userProgress.UserID)
+	if err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+	if dueDate == "" {
+		dueDate = utils.FormatDueDate(userProgress.DueDate)
+	}
+
+	history := ctr.Progression.NewProgressHistory(userProgress, cf.HistoryReasonReset, userProfile.ID, resetProgressParams.Note)
+
+	// SCORM completion of the reset cycle no longer counts, the quiz attempts only when they are archived
+	archiveCycle := func(tx *pg.Tx) error {
+		if resetProgressParams.ArchiveQuizAttempts {
+			if err := ctr.Progression.ArchiveQuizAttemptsWithTx(tx, userProgress.UserID, userProgress.CourseID); err != nil {
+				return err
+			}
+		}
+		return ctr.Progression.ResetScormRuntimesWithTx(tx, userProgress.UserID, userProgress.CourseID)
+	}
+
+	if err := ctr.UserProgressRepo.ResetUserProgress(&userProgress, history, dueDate, archiveCycle); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to reset user progress",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "User progress reset successfully",
+	})
+}
+
+// ReopenProgress sets a completed enrollment back in progress from an item,
+// the completed cycle is kept in the progress history
+func (ctr *UserProgressController) ReopenProgress(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+
+	reopenProgressParams := new(param.ReopenProgressParams)
+
+	if err := c.Bind(reopenProgressParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(reopenProgressParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	userProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(reopenProgressParams.UserID, reopenProgressParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("User progress not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "User progress not found",
+		})
+	}
+
+	if !userProgress.Completed {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Only a completed course can be reopened",
+		})
+	}
+
+	history := ctr.Progression.NewProgressHistory(userProgress, cf.HistoryReasonReopen, userProfile.ID, reopenProgressParams.Note)
+
+	reason, err := ctr.Progression.ReopenProgress(&userProgress, reopenProgressParams.ModuleItemID, history)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to reopen user progress: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to reopen user progress",
+		})
+	}
+	if reason != "" {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: reason,
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "User progress reopened successfully",
+		Data:    progressPositions(userProgress),
+	})
+}
+
+// UnassignCourse removes a course from a trainee, the enrollment is kept in the progress history
+func (ctr *UserProgressController) UnassignCourse(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+
+	unassignCourseParams := new(param.UnassignCourseParams)
+
+	if err := c.Bind(unassignCourseParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(unassignCourseParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	userProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(unassignCourseParams.UserID, unassignCourseParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("User progress not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "User progress not found",
+		})
+	}
+
+	history := ctr.Progression.NewProgressHistory(userProgress, cf.HistoryReasonUnassign, userProfile.ID, unassignCourseParams.Note)
+
+	// SCORM completion of the unassigned cycle no longer counts, the quiz attempts only when they are archived
+	archiveCycle := func(tx *pg.Tx) error {
+		if unassignCourseParams.ArchiveQuizAttempts {
+			if err := ctr.Progression.ArchiveQuizAttemptsWithTx(tx, userProgress.UserID, userProgress.CourseID); err != nil {
+				return err
+			}
+		}
+		return ctr.Progression.ResetScormRuntimesWithTx(tx, userProgress.UserID, userProgress.CourseID)
+	}
+
+	if err := ctr.UserProgressRepo.UnassignUserProgress(&userProgress, history, archiveCycle); err != nil {
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to unassign course",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Course unassigned successfully",
+	})
+}
+
+// TransferProgress moves a trainee from a course to another one in one transaction,
+// the new enrollment keeps the due date, the position and the path of the previous one
+// along with the records of the items found in both courses
+func (ctr *UserProgressController) TransferProgress(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+
+	transferProgressParams := new(param.TransferProgressParams)
+
+	if err := c.Bind(transferProgressParams); err != nil {
+		ctr.Logger.Errorf("Failed to bind params: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if _, err := valid.ValidateStruct(transferProgressParams); err != nil {
+		ctr.Logger.Errorf("Validation failed: %v", err)
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: err.Error(),
+		})
+	}
+
+	if transferProgressParams.ToCourseID == transferProgressParams.CourseID {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "The trainee can not be transferred to the same course",
+		})
+	}
+
+	userProgress, err := ctr.UserProgressRepo.GetSingleUserProgress(transferProgressParams.UserID, transferProgressParams.CourseID)
+	if err != nil {
+		ctr.Logger.Errorf("User progress not found: %v", err)
+		return c.JSON(http.StatusNotFound, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "User progress not found",
+		})
+	}
+
+	newProgress, blockedEnrollments, err := ctr.Enrollment.NewEnrollment(userProgress.UserID, transferProgressParams.ToCourseID, userProgress.CoursePosition, utils.FormatDueDate(userProgress.DueDate), userProfile.ID, userProgress.TemplatePathID)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to check course prerequisites: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to check course prerequisites",
+		})
+	}
+
+	if len(blockedEnrollments) > 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.WarningResponseCode,
+			Message: "The trainee was not transferred because the prerequisites of the course are not completed",
+			Data: map[string]interface{}{
+				"blocked_courses": blockedEnrollments,
+			},
+		})
+	}
+
+	if newProgress == nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "The trainee was not transferred because the course is already assigned",
+		})
+	}
+
+	history := ctr.Progression.NewProgressHistory(userProgress, cf.HistoryReasonTransfer, userProfile.ID, transferProgressParams.Note)
+	history.TransferredToCourseID = transferProgressParams.ToCourseID
+	if err := ctr.Progression.TransferProgress(userProgress, newProgress, history); err != nil {
+		ctr.Logger.Errorf("Failed to transfer progress: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to transfer the trainee",
+		})
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Trainee transferred successfully",
+	})
+}
+
+// recordProgressStatement emits a progressed or completed xAPI statement for the trainee
+func (ctr *UserProgressController) recordProgressStatement(userProfile m.User, userProgress *m.UserProgress) {
+	trainee := userProfile
+	if userProgress.UserID != userProfile.ID {
+		var err error
+		trainee, err = ctr.UserRepo.GetUserProfile(userProgress.UserID)
+		if err != nil {
+			ctr.Logger.Errorf("Failed to fetch trainee for xAPI statement: %v", err)
+			return
+		}
+	}
+
+	verb := xapi.VerbProgressed
+	if userProgress.Completed {
+		verb = xapi.VerbCompleted
+	}
+
+	statement := xapi.NewStatement(
+		xapi.NewAgent(trainee.Email, trainee.UserProfile.FirstName+" "+trainee.UserProfile.LastName),
+		verb,
+		xapi.NewActivity(xapi.ActivityTypeCourse, userProgress.CourseID, ""),
+	)
+	statement.Context = &xapi.Context{
+		Extensions: map[string]interface{}{
+			xapi.ExtensionModulePosition:     userProgress.ModulePosition,
+			xapi.ExtensionModuleItemPosition: userProgress.ModuleItemPosition,
+		},
+	}
+	if userProgress.Completed {
+		completion := true
+		statement.Result = &xapi.Result{Completion: &completion}
+	}
+
+	if err := ctr.XapiRepo.RecordStatement(trainee.ID, statement); err != nil {
+		ctr.Logger.Errorf("Failed to record xAPI statement: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package userprogress
+
+import (
+	"fmt"
+	cf "orientation-training-api/configs"
+	rp "orientation-training-api/internal/interfaces/repository"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OverdueJob marks enrollments past their due date as overdue and notifies
+// the trainee and the managers of the trainee's department
+type OverdueJob struct {
+	Logger           echo.Logger
+	UserProgressRepo rp.UserProgressRepository
+	NotificationRepo rp.NotificationRepository
+	UserRepo         rp.UserRepository
+}
+
+func NewOverdueJob(logger echo.Logger, userProgressRepo rp.UserProgressRepository, notificationRepo rp.NotificationRepository, userRepo rp.UserRepository) *OverdueJob {
+	return &OverdueJob{logger, userProgressRepo, notificationRepo, userRepo}
+}
+
+// Run escalates every enrollment that became overdue since the last run
+func (job *OverdueJob) Run() error {
+	userProgressList, err := job.UserProgressRepo.GetNewlyOverdueUserProgresses()
+	if err != nil {
+		return err
+	}
+
+	for _, userProgress := range userProgressList {
+		if err := job.notify(userProgress); err != nil {
+			job.Logger.Errorf("Failed to notify overdue enrollment %d: %v", userProgress.ID, err)
+			continue
+		}
+
+		if err := job.UserProgressRepo.MarkUserProgressOverdue(userProgress.ID); err != nil {
+			job.Logger.Errorf("Failed to mark enrollment %d as overdue: %v", userProgress.ID, err)
+		}
+	}
+
+	if len(userProgressList) > 0 {
+		job.Logger.Infof("Escalated %d overdue enrollments", len(userProgressList))
+	}
+
+	return nil
+}
+
+func (job *OverdueJob) notify(userProgress m.UserProgress) error {
+	courseTitle := fmt.Sprintf("Course %d", userProgress.CourseID)
+	if userProgress.Course != nil {
+		courseTitle = userProgress.Course.Title
+	}
+
+	traineeName := fmt.Sprintf("User %d", userProgress.UserID)
+	if userProgress.User != nil {
+		traineeName = strings.TrimSpace(userProgress.User.UserProfile.FirstName + " " + userProgress.User.UserProfile.LastName)
+	}
+
+	dueDate := utils.FormatDueDate(userProgress.DueDate)
+	data := map[string]interface{}{
+		"user_id":   userProgress.UserID,
+		"course_id": userProgress.CourseID,
+		"due_date":  dueDate,
+	}
+
+	err := job.NotificationRepo.CreateNotification(&m.Notification{
+		UserID:  userProgress.UserID,
+		Type:    cf.NotificationTypeCourseOverdue,
+		Title:   "Course overdue",
+		Message: fmt.Sprintf("The course \"%s\" was due on %s. Please complete it as soon as possible.", courseTitle, dueDate),
+		Data:    data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, managerID := range job.managerIDs(userProgress) {
+		err := job.NotificationRepo.CreateNotification(&m.Notification{
+			UserID:  managerID,
+			Type:    cf.NotificationTypeTraineeCourseOverdue,
+			Title:   "Trainee course overdue",
+			Message: fmt.Sprintf("%s has not completed the course \"%s\" which was due on %s.", traineeName, courseTitle, dueDate),
+			Data:    data,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// managerIDs returns the managers of the trainee's department,
+// or the user who assigned the course when the department has no manager
+func (job *OverdueJob) managerIDs(userProgress m.UserProgress) []int {
+	managerIDs := []int{}
+
+	if userProgress.User != nil && strings.TrimSpace(userProgress.User.UserProfile.Department) != "" {
+		managers, err := job.UserRepo.GetDepartmentManagers(userProgress.User.UserProfile.Department)
+		if err != nil {
+			job.Logger.Errorf("Failed to fetch managers of user %d: %v", userProgress.UserID, err)
+		}
+		for _, manager := range managers {
+			if manager.ID != userProgress.UserID {
+				managerIDs = append(managerIDs, manager.ID)
+			}
+		}
+	}
+
+	if len(managerIDs) == 0 && userProgress.AssignedBy != 0 && userProgress.AssignedBy != userProgress.UserID {
+		managerIDs = append(managerIDs, userProgress.AssignedBy)
+	}
+
+	return managerIDs
+}
@@ -3,7 +3,9 @@ package userprogress
 import (
 	cm "orientation-training-api/internal/common"
 	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
 
+	"github.com/go-pg/pg/v9"
 	"github.com/labstack/echo/v4"
 )
 
@@ -47,17 +49,25 @@ func (repo *PgUserProgressRepository) SaveUserProgress(userProgress *m.UserProgr
 			Set("module_position = ?", userProgress.ModulePosition).
 			Set("module_item_position = ?", userProgress.ModuleItemPosition).
 			Set("completed = ?", userProgress.Completed).
+			Set("completed_at = CASE WHEN ? THEN COALESCE(completed_at, NOW()) ELSE NULL END", userProgress.Completed).
 			Set("updated_at = NOW()")
 
 		if userProgress.CompletedDate != "" {
 			query = query.Set("completed_date = ?", userProgress.CompletedDate)
 		}
 
+		if !userProgress.ItemStartedAt.IsZero() {
+			query = query.Set("item_started_at = ?", userProgress.ItemStartedAt)
+		}
+
 		_, err = query.Where("user_id = ?", userProgress.UserID).
 			Where("course_id = ?", userProgress.CourseID).
 			Where("deleted_at IS NULL").
 			Update()
 	} else {
+		if userProgress.Completed && userProgress.CompletedAt.IsZero() {
+			userProgress.CompletedAt = utils.TimeNowUTC()
+		}
 		_, err = repo.DB.Model(userProgress).Insert()
 	}
 
@@ -89,6 +99,9 @@ func (repo *PgUserProgressRepository) GetAllUserProgressByUserID(userID int) ([]
 		Relation("Reviewer").
 		Relation("Reviewer.UserProfile").
 		Relation("Course").
+		Relation("TemplatePath").
+		Relation("TemplatePath.Stages").
+		Relation("TemplatePath.PathCourses").
 		Where("user_progress.user_id = ?", userID).
 		Where("user_progress.deleted_at IS NULL").
 		Order("user_progress.course_position ASC").
@@ -120,3 +133,195 @@ func (repo *PgUserProgressRepository) ReviewUserProgress(userID int, courseID in
 
 	return nil
 }
+
+// GetNewlyOverdueUserProgresses retrieves uncompleted enrollments past their due date
+// that have not been escalated yet, with the trainee and course loaded
+func (repo *PgUserProgressRepository) GetNewlyOverdueUserProgresses() ([]m.UserProgress, error) {
+	var userProgressList []m.UserProgress
+
+	err := repo.DB.Model(&userProgressList).
+		Relation("User").
+		Relation("User.UserProfile").
+		Relation("Course").
+		Where("user_progress.completed = FALSE").
+		Where("user_progress.due_date < CURRENT_DATE").
+		Where("user_progress.overdue_notified_at IS NULL").
+		Where("user_progress.deleted_at IS NULL").
+		Order("user_progress.due_date ASC").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error fetching overdue user progress: %v", err)
+		return nil, err
+	}
+
+	return userProgressList, nil
+}
+
+// MarkUserProgressOverdue flags an enrollment as overdue and records that its escalation was sent
+func (repo *PgUserProgressRepository) MarkUserProgressOverdue(userProgressID int) error {
+	_, err := repo.DB.Model((*m.UserProgress)(nil)).
+		Set("overdue_at = COALESCE(overdue_at, NOW())").
+		Set("overdue_notified_at = NOW()").
+		Set("updated_at = NOW()").
+		Where("id = ?", userProgressID).
+		Update()
+
+	if err != nil {
+		repo.Logger.Errorf("Error marking user progress %d as overdue: %v", userProgressID, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetLatestCycle returns the latest cycle of a user in a course, unassigned enrollments included,
+// so that a new enrollment does not reuse the item records of an earlier one
+func (repo *PgUserProgressRepository) GetLatestCycle(userID int, courseID int) (int, error) {
+	var latestCycle int
+
+	_, err := repo.DB.Query(pg.Scan(&latestCycle),
+		"SELECT COALESCE(MAX(cycle), 0) FROM user_progresses WHERE user_id = ? AND course_id = ?",
+		userID, courseID)
+
+	if err != nil {
+		repo.Logger.Errorf("Error getting latest cycle of user %d in course %d: %v", userID, courseID, err)
+	}
+
+	return latestCycle, err
+}
+
+// ResetUserProgress archives the current cycle of an enrollment and restarts it from the beginning,
+// archiveCycle archives the quiz attempts and SCORM data of the cycle within the same transaction
+func (repo *PgUserProgressRepository) ResetUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, dueDate string, archiveCycle func(tx *pg.Tx) error) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if err := archiveCycle(tx); err != nil {
+			repo.Logger.Errorf("Error archiving quiz attempts and SCORM data of enrollment %d: %v", userProgress.ID, err)
+			return err
+		}
+
+		if _, err := tx.Model(history).Insert(); err != nil {
+			repo.Logger.Errorf("Error archiving cycle %d of user %d in course %d: %v", history.Cycle, history.UserID, history.CourseID, err)
+			return err
+		}
+
+		var dueDateValue interface{}
+		if dueDate != "" {
+			dueDateValue = dueDate
+		}
+
+		_, err := tx.Model((*m.UserProgress)(nil)).
+			Set("cycle = cycle + 1").
+			Set("module_position = 1").
+			Set("module_item_position = 1").
+			Set("completed = FALSE").
+			Set("completed_date = NULL").
+			Set("completed_at = NULL").
+			Set("item_started_at = NOW()").
+			Set("performance_rating = NULL").
+			Set("performance_comment = NULL").
+			Set("reviewed_by = NULL").
+			Set("due_date = ?", dueDateValue).
+			Set("overdue_at = NULL").
+			Set("overdue_notified_at = NULL").
+			Set("updated_at = NOW()").
+			Where("id = ?", userProgress.ID).
+			Where("deleted_at IS NULL").
+			Update()
+		if err != nil {
+			repo.Logger.Errorf("Error resetting enrollment %d: %v", userProgress.ID, err)
+		}
+
+		return err
+	})
+}
+
+// ReopenUserProgress archives a completed enrollment and saves it back in progress at its new position,
+// reopenItems sets its items back in progress within the same transaction
+func (repo *PgUserProgressRepository) ReopenUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, reopenItems func(tx *pg.Tx) error) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if err := reopenItems(tx); err != nil {
+			repo.Logger.Errorf("Error reopening items of enrollment %d: %v", userProgress.ID, err)
+			return err
+		}
+
+		if _, err := tx.Model(history).Insert(); err != nil {
+			repo.Logger.Errorf("Error archiving cycle %d of user %d in course %d: %v", history.Cycle, history.UserID, history.CourseID, err)
+			return err
+		}
+
+		_, err := tx.Model((*m.UserProgress)(nil)).
+			Set("module_position = ?", userProgress.ModulePosition).
+			Set("module_item_position = ?", userProgress.ModuleItemPosition).
+			Set("completed = FALSE").
+			Set("completed_date = NULL").
+			Set("completed_at = NULL").
+			Set("item_started_at = NOW()").
+			Set("performance_rating = NULL").
+			Set("performance_comment = NULL").
+			Set("reviewed_by = NULL").
+			Set("updated_at = NOW()").
+			Where("id = ?", userProgress.ID).
+			Where("deleted_at IS NULL").
+			Update()
+		if err != nil {
+			repo.Logger.Errorf("Error reopening enrollment %d: %v", userProgress.ID, err)
+		}
+
+		return err
+	})
+}
+
+// UnassignUserProgress archives an enrollment and removes it from the trainee,
+// archiveCycle archives the quiz attempts and SCORM data of the enrollment within the same transaction
+func (repo *PgUserProgressRepository) UnassignUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, archiveCycle func(tx *pg.Tx) error) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if err := archiveCycle(tx); err != nil {
+			repo.Logger.Errorf("Error archiving quiz attempts and SCORM data of enrollment %d: %v", userProgress.ID, err)
+			return err
+		}
+
+		return repo.unassignWithTx(tx, userProgress, history)
+	})
+}
+
+// TransferUserProgress replaces an enrollment by the enrollment of another course in one transaction,
+// carryOver copies the activity of the previous course to the new one within the same transaction
+func (repo *PgUserProgressRepository) TransferUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, newProgress *m.UserProgress, carryOver func(tx *pg.Tx) error) error {
+	return repo.DB.RunInTransaction(func(tx *pg.Tx) error {
+		if err := repo.unassignWithTx(tx, userProgress, history); err != nil {
+			return err
+		}
+
+		if _, err := tx.Model(newProgress).Insert(); err != nil {
+			repo.Logger.Errorf("Error enrolling user %d in course %d: %v", newProgress.UserID, newProgress.CourseID, err)
+			return err
+		}
+
+		if err := carryOver(tx); err != nil {
+			repo.Logger.Errorf("Error carrying over the activity of enrollment %d: %v", userProgress.ID, err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (repo *PgUserProgressRepository) unassignWithTx(tx *pg.Tx, userProgress *m.UserProgress, history *m.UserProgressHistory) error {
+	if _, err := tx.Model(history).Insert(); err != nil {
+		repo.Logger.Errorf("Error archiving cycle %d of user %d in course %d: %v", history.Cycle, history.UserID, history.CourseID, err)
+		return err
+	}
+
+	_, err := tx.Model((*m.UserProgress)(nil)).
+		Set("deleted_at = NOW()").
+		Set("updated_at = NOW()").
+		Where("id = ?", userProgress.ID).
+		Where("deleted_at IS NULL").
+		Update()
+	if err != nil {
+		repo.Logger.Errorf("Error unassigning enrollment %d: %v", userProgress.ID, err)
+	}
+
+	return err
+}
@@ -8,6 +8,9 @@ import (
 
 	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/domains/enrollment"
+	"orientation-training-api/internal/domains/progression"
+	"orientation-training-api/internal/domains/quizzes"
 	rp "orientation-training-api/internal/interfaces/repository"
 	param "orientation-training-api/internal/interfaces/requestparams"
 	resp "orientation-training-api/internal/interfaces/response"
@@ -32,6 +35,8 @@ type UserController struct {
 	ModuleItemRepo         rp.ModuleItemRepository
 	QuizRepo               rp.QuizRepository
 	CourseSkillKeywordRepo rp.CourseSkillKeywordRepository
+	Enrollment             *enrollment.Service
+	Progression            *progression.Service
 	cloud                  gc.StorageUtility
 }
 
@@ -44,6 +49,8 @@ func NewUserController(
 	moduleItemRepo rp.ModuleItemRepository,
 	quizRepo rp.QuizRepository,
 	courseSkillKeywordRepo rp.CourseSkillKeywordRepository,
+	enrollmentService *enrollment.Service,
+	progressionService *progression.Service,
 	cloud gc.StorageUtility,
 
 ) (ctr *UserController) {
@@ -56,6 +63,8 @@ func NewUserController(
 		moduleItemRepo,
 		quizRepo,
 		courseSkillKeywordRepo,
+		enrollmentService,
+		progressionService,
 		cloud,
 	}
 	ctr.Init(logger)
@@ -220,11 +229,19 @@ func (ctr *UserController) Register(c echo.Context) error {
 		})
 	}
 
+	pathAssignments := []resp.PathAssignmentResponse{}
+	if createdUser, err := ctr.UserRepo.GetUserProfile(userID); err == nil {
+		pathAssignments = ctr.Enrollment.ApplyPathRules(createdUser, 0)
+	} else {
+		ctr.Logger.Errorf("Failed to apply path assignment rules to user %d: %v", userID, err)
+	}
+
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
 		Message: "User registered successfully",
 		Data: map[string]interface{}{
-			"user_id": userID,
+			"user_id":          userID,
+			"path_assignments": pathAssignments,
 		},
 	})
 }
@@ -397,7 +414,7 @@ func (ctr *UserController) EmployeeDetail(c echo.Context) error {
 			Message: "Failed to fetch user progress",
 		})
 	}
-	response := buildEmployeeDetailResponse(employee, userProgresses, ctr.CourseRepo, ctr.ModuleRepo, ctr.ModuleItemRepo, ctr.QuizRepo, ctr.CourseSkillKeywordRepo, ctr.Logger)
+	response := buildEmployeeDetailResponse(employee, userProgresses, ctr.CourseRepo, ctr.ModuleRepo, ctr.ModuleItemRepo, ctr.QuizRepo, ctr.CourseSkillKeywordRepo, ctr.Progression, ctr.Logger)
 
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
@@ -414,6 +431,7 @@ func buildEmployeeDetailResponse(
 	moduleItemRepo rp.ModuleItemRepository,
 	quizRepo rp.QuizRepository,
 	courseSkillKeywordRepo rp.CourseSkillKeywordRepository,
+	progressionService *progression.Service,
 	logger echo.Logger,
 ) resp.EmployeeDetail {
 	userInfo := resp.UserInfo{
@@ -435,6 +453,8 @@ func buildEmployeeDetailResponse(
 
 	totalPerformanceRating := float64(0)
 	ratedCourses := 0
+	overdueCourses := 0
+	totalTimeSpent := 0
 
 	courseMap := make(map[int]m.Course)
 
@@ -468,8 +488,14 @@ func buildEmployeeDetailResponse(
 			continue
 		}
 		courseInfo := resp.CourseInfo{
-			CourseID:    course.ID,
-			CourseTitle: course.Title,
+			CourseID:       course.ID,
+			CourseTitle:    course.Title,
+			DueDate:        utils.FormatDueDate(progress.DueDate),
+			Overdue:        utils.IsOverdue(progress.DueDate, progress.Completed),
+			TemplatePathID: progress.TemplatePathID,
+		}
+		if courseInfo.Overdue {
+			overdueCourses++
 		}
 
 		// Get skill keywords for this course
@@ -491,6 +517,13 @@ func buildEmployeeDetailResponse(
 		} else {
 			courseInfo.PendingReviews = pendingReviews
 		}
+		timeSpent, err := progressionService.CourseTimeSpent(progress)
+		if err != nil {
+			logger.Errorf("Failed to get time spent on course %d, user %d: %v", course.ID, employee.ID, err)
+		}
+		courseInfo.TimeSpent = timeSpent
+		totalTimeSpent += timeSpent
+
 		if progress.Completed {
 			completedCourses++
 			courseInfo.Status = "completed"
@@ -526,7 +559,7 @@ func buildEmployeeDetailResponse(
 			}
 		} else {
 			courseInfo.Status = "in_progress"
-			progressPercent := calculateCourseProgress(progress, moduleRepo, moduleItemRepo, logger)
+			progressPercent := calculateCourseProgress(progress, progressionService, logger)
 			courseInfo.Progress = progressPercent
 
 			if progressPercent > 0 && progress.ModulePosition > 0 {
@@ -559,6 +592,8 @@ func buildEmployeeDetailResponse(
 		TotalScore:               totalMaxScore,
 		UserScore:                totalUserScore,
 		CompletedDate:            latestCompletionDate,
+		OverdueCourses:           overdueCourses,
+		TotalTimeSpent:           totalTimeSpent,
 		AveragePerformanceRating: averagePerformanceRating,
 		UserSkills:               userSkills,
 	}
@@ -567,9 +602,48 @@ func buildEmployeeDetailResponse(
 		UserInfo:     userInfo,
 		ProcessStats: processStats,
 		ProcessInfo:  processInfo,
+		PathInfo:     buildPathInfo(userProgresses),
 	}
 }
 
+// buildPathInfo computes the completion of every template path the employee was assigned,
+// a course of the path counts as completed even when it was assigned separately
+func buildPathInfo(userProgresses []m.UserProgress) []resp.PathInfo {
+	templatePaths := []m.TemplatePath{}
+	seenPaths := make(map[int]bool)
+
+	for _, progress := range userProgresses {
+		if progress.TemplatePath != nil && progress.TemplatePath.ID > 0 && !seenPaths[progress.TemplatePath.ID] {
+			seenPaths[progress.TemplatePath.ID] = true
+			templatePaths = append(templatePaths, *progress.TemplatePath)
+		}
+	}
+
+	pathInfo := []resp.PathInfo{}
+	for _, templatePath := range templatePaths {
+		info := resp.PathInfo{
+			TemplatePathID: templatePath.ID,
+			Name:           templatePath.Name,
+			Stages:         enrollment.EvaluatePathStages(templatePath, userProgresses),
+		}
+
+		// Elective stages only count the number of courses they require
+		for _, stage := range info.Stages {
+			info.TotalCourses += stage.RequiredCount
+			info.CompletedCourses += stage.CompletedCount
+		}
+
+		if info.TotalCourses > 0 {
+			info.Progress = info.CompletedCourses * 100 / info.TotalCourses
+			info.Completed = info.CompletedCourses == info.TotalCourses
+		}
+
+		pathInfo = append(pathInfo, info)
+	}
+
+	return pathInfo
+}
+
 func calculateCourseQuizScores(courseID int, userID int, moduleRepo rp.ModuleRepository, moduleItemRepo rp.ModuleItemRepository, quizRepo rp.QuizRepository, logger echo.Logger) (float64, float64) {
 	userScore := float64(0)
 	maxScore := float64(0)
@@ -596,142 +670,43 @@ func calculateCourseQuizScores(courseID int, userID int, moduleRepo rp.ModuleRep
 
 	for _, item := range moduleItems {
 		if item.ItemType == "quiz" && item.QuizID > 0 {
-			submissions, err := quizRepo.GetQuizSubmissionsByUser(userID, item.QuizID)
-			if err != nil {
-				logger.Errorf("Error getting submissions for user %d, quiz %d: %v", userID, item.QuizID, err)
-				continue
-			}
-
-			if len(submissions) == 0 {
-				continue
-			}
-
-			maxAttempt := 0
-			for _, submission := range submissions {
-				if submission.Attempt > maxAttempt {
-					maxAttempt = submission.Attempt
-				}
-			}
-
-			latestAttemptScore := float64(0)
-			for _, submission := range submissions {
-				if submission.Attempt == maxAttempt {
-					latestAttemptScore += submission.Score
-				}
-			}
-
-			var quizTotalScore float64
-
+			quiz := m.Quiz{}
 			if item.Quiz != nil {
-				quizTotalScore = item.Quiz.TotalScore
+				quiz = *item.Quiz
 			} else {
-				quiz, err := quizRepo.GetQuizByID(item.QuizID)
+				quiz, err = quizRepo.GetQuizByID(item.QuizID)
 				if err != nil {
 					logger.Errorf("Error getting quiz %d: %v", item.QuizID, err)
 					continue
 				}
-				quizTotalScore = quiz.TotalScore
 			}
 
-			userScore += latestAttemptScore
-			maxScore += quizTotalScore
+			quizResult, err := quizzes.UserAttemptResult(quizRepo, userID, quiz)
+			if err != nil {
+				logger.Errorf("Error getting attempts for user %d, quiz %d: %v", userID, item.QuizID, err)
+				continue
+			}
+
+			if quizResult.AttemptsUsed == 0 {
+				continue
+			}
+
+			userScore += quizResult.KeptScore
+			maxScore += quiz.TotalScore
 		}
 	}
 
 	return userScore, maxScore
 }
 
-func calculateCourseProgress(progress m.UserProgress, moduleRepo rp.ModuleRepository, moduleItemRepo rp.ModuleItemRepository, logger echo.Logger) int {
-	if progress.Completed {
-		return 100
-	}
-
-	if progress.ModulePosition <= 0 {
-		return 0
-	}
-	modules, err := moduleRepo.GetModulesByCourseID(progress.CourseID)
+// calculateCourseProgress returns the share of completed items of a course from the item completion records
+func calculateCourseProgress(progress m.UserProgress, progressionService *progression.Service, logger echo.Logger) int {
+	percentage, err := progressionService.CourseCompletionRate(progress)
 	if err != nil {
-		logger.Errorf("Error getting modules for course %d: %v", progress.CourseID, err)
+		logger.Errorf("Error calculating progress of course %d for user %d: %v", progress.CourseID, progress.UserID, err)
 		return 0
 	}
 
-	totalModules := len(modules)
-	if totalModules == 0 {
-		return 0
-	}
-
-	totalItems := 0
-	completedItems := 0
-
-	moduleIDs := make([]int, len(modules))
-	for i, module := range modules {
-		moduleIDs[i] = module.ID
-	}
-
-	moduleItems, err := moduleItemRepo.GetModuleItemsByModuleIDs(moduleIDs)
-	if err != nil {
-		logger.Errorf("Error getting module items: %v", err)
-		totalModules = progress.CoursePosition
-		if totalModules <= 0 {
-			totalModules = len(modules)
-		}
-	} else {
-		moduleItemCounts := make(map[int]int)
-		for _, item := range moduleItems {
-			moduleItemCounts[item.ModuleID]++
-			totalItems++
-		}
-
-		for i, module := range modules {
-			position := i + 1
-
-			if position < progress.ModulePosition {
-				completedItems += moduleItemCounts[module.ID]
-			} else if position == progress.ModulePosition {
-				completedItems += progress.ModuleItemPosition - 1
-				if completedItems < 0 {
-					completedItems = 0
-				}
-			}
-		}
-
-		if totalItems > 0 {
-			percentage := (completedItems * 100) / totalItems
-			if percentage >= 100 {
-				percentage = 99
-			}
-			return percentage
-		}
-	}
-	completedModules := progress.ModulePosition - 1
-	if completedModules < 0 {
-		completedModules = 0
-	}
-
-	percentage := (completedModules * 100) / totalModules
-
-	if progress.ModulePosition > 0 && progress.ModuleItemPosition > 0 {
-		currentModuleItems := 0
-		for _, module := range modules {
-			if module.Position == progress.ModulePosition {
-				items, err := moduleItemRepo.GetModuleItemsByModuleID(module.ID)
-				err = nil
-				if err == nil && len(items) > 0 {
-					currentModuleItems = len(items)
-				} else {
-					currentModuleItems = 10
-				}
-				break
-			}
-		}
-
-		modulePercentage := (progress.ModuleItemPosition * 100) / (currentModuleItems * totalModules)
-		percentage += modulePercentage
-	}
-	if percentage >= 100 {
-		percentage = 99
-	}
-
 	return percentage
 }
 
@@ -972,7 +947,7 @@ func (ctr *UserController) AdminUpdateUser(c echo.Context) error {
 		})
 	}
 	// Check if user exists
-	_, err := ctr.UserRepo.GetUserProfile(updateParams.UserID)
+	currentUser, err := ctr.UserRepo.GetUserProfile(updateParams.UserID)
 	if err != nil {
 		if err.Error() == pg.ErrNoRows.Error() {
 			return c.JSON(http.StatusNotFound, cf.JsonResponse{
@@ -1071,6 +1046,13 @@ func (ctr *UserController) AdminUpdateUser(c echo.Context) error {
 		"role_name":           updatedUser.Role.Name,
 	}
 
+	// Paths of the new department or role are assigned, enrollments from the previous ones are kept
+	departmentChanged := !strings.EqualFold(strings.TrimSpace(currentUser.UserProfile.Department), strings.TrimSpace(updatedUser.UserProfile.Department))
+	if departmentChanged || currentUser.RoleID != updatedUser.RoleID {
+		adminProfile := c.Get("user_profile").(m.User)
+		dataResponse["path_assignments"] = ctr.Enrollment.ApplyPathRules(updatedUser, adminProfile.ID)
+	}
+
 	return c.JSON(http.StatusOK, cf.JsonResponse{
 		Status:  cf.SuccessResponseCode,
 		Message: "User information updated successfully",
@@ -1,6 +1,7 @@
 package users
 
 import (
+	cf "orientation-training-api/configs"
 	cm "orientation-training-api/internal/common"
 	param "orientation-training-api/internal/interfaces/requestparams"
 	"orientation-training-api/internal/models"
@@ -81,6 +82,24 @@ func (repo *PgUserRepository) GetUsersByRoleID(roleID int) ([]m.User, error) {
 	return users, err
 }
 
+// GetDepartmentManagers retrieves the managers of a department, the department is compared case insensitive
+func (repo *PgUserRepository) GetDepartmentManagers(department string) ([]m.User, error) {
+	var users []m.User
+	err := repo.DB.Model(&users).
+		Column("usr.*").
+		Where("usr.role_id = ?", cf.ManagerRoleID).
+		Where("usr.deleted_at is null").
+		Where("LOWER(TRIM(user_profile.department)) = LOWER(TRIM(?))", department).
+		Relation("UserProfile").
+		Select()
+
+	if err != nil {
+		repo.Logger.Errorf("Error getting managers of department %s: %+v", department, err)
+	}
+
+	return users, err
+}
+
 // GetUserProgressByUserID retrieves all user progress entries for a specific user
 func (repo *PgUserRepository) GetUserProgressByUserID(userID int) ([]models.UserProgress, error) {
 	var userProgresses []models.UserProgress
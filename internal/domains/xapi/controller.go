@@ -0,0 +1,212 @@
+package xapi
+
+import (
+	"encoding/json"
+	"net/http"
+	cf "orientation-training-api/configs"
+	cm "orientation-training-api/internal/common"
+	rp "orientation-training-api/internal/interfaces/repository"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/xapi"
+	"strings"
+	"time"
+
+	valid "github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+)
+
+type XapiController struct {
+	cm.BaseController
+
+	XapiRepo rp.XapiRepository
+}
+
+func NewXapiController(logger echo.Logger, xapiRepo rp.XapiRepository) (ctr *XapiController) {
+	ctr = &XapiController{cm.BaseController{}, xapiRepo}
+	ctr.Init(logger)
+	return
+}
+
+// GetStatements : query statements stored in the built-in LRS
+// Trainees only see their own statements, managers can filter by user_id
+// Params : echo.Context
+// Returns : return error
+func (ctr *XapiController) GetStatements(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	listParams := new(param.XapiStatementListParams)
+
+	if err := c.Bind(listParams); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	if userProfile.RoleID != cf.ManagerRoleID && userProfile.RoleID != cf.AdminRoleID && userProfile.RoleID != cf.GeneralManagerRoleID {
+		listParams.UserID = userProfile.ID
+	}
+
+	for _, value := range []string{listParams.Since, listParams.Until} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "since/until must be RFC 3339 timestamps",
+			})
+		}
+	}
+
+	if listParams.CurrentPage <= 0 {
+		listParams.CurrentPage = 1
+	}
+
+	if listParams.RowPerPage <= 0 {
+		listParams.RowPerPage = 50
+	}
+
+	xapiStatements, total, err := ctr.XapiRepo.GetStatements(listParams)
+	if err != nil {
+		ctr.Logger.Errorf("Failed to fetch xAPI statements: %v", err)
+		return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Failed to fetch statements",
+		})
+	}
+
+	statements := []*xapi.Statement{}
+	for _, xapiStatement := range xapiStatements {
+		statements = append(statements, xapiStatement.Statement)
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Success",
+		Data: map[string]interface{}{
+			"statements":   statements,
+			"total":        total,
+			"current_page": listParams.CurrentPage,
+			"row_per_page": listParams.RowPerPage,
+		},
+	})
+}
+
+// SaveStatements : store one statement or an array of statements sent by learning content
+// Params : echo.Context
+// Returns : return error
+func (ctr *XapiController) SaveStatements(c echo.Context) error {
+	userProfile := c.Get("user_profile").(m.User)
+	body := json.RawMessage{}
+
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "Invalid Params",
+			Data:    err,
+		})
+	}
+
+	statements := []*xapi.Statement{}
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		if err := json.Unmarshal(body, &statements); err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Invalid statements",
+			})
+		}
+	} else {
+		statement := &xapi.Statement{}
+		if err := json.Unmarshal(body, statement); err != nil {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Invalid statement",
+			})
+		}
+		statements = append(statements, statement)
+	}
+
+	if len(statements) == 0 {
+		return c.JSON(http.StatusOK, cf.JsonResponse{
+			Status:  cf.FailResponseCode,
+			Message: "No statements to store",
+		})
+	}
+
+	userMbox := "mailto:" + userProfile.Email
+	batchIDs := map[string]bool{}
+	for _, statement := range statements {
+		if statement.Verb.ID == "" || statement.Object.ID == "" {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Statement verb and object are required",
+			})
+		}
+
+		if statement.Actor.Mbox == "" {
+			statement.Actor = xapi.NewAgent(userProfile.Email, userProfile.UserProfile.FirstName+" "+userProfile.UserProfile.LastName)
+		} else if !strings.EqualFold(statement.Actor.Mbox, userMbox) {
+			return c.JSON(http.StatusOK, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Statement actor must be the logged in user",
+			})
+		}
+
+		if statement.Object.ObjectType == "" {
+			statement.Object.ObjectType = "Activity"
+		}
+
+		if statement.ID != "" {
+			if !valid.IsUUID(statement.ID) {
+				return c.JSON(http.StatusBadRequest, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: "Statement id " + statement.ID + " is not a UUID",
+				})
+			}
+			statement.ID = strings.ToLower(statement.ID)
+			if batchIDs[statement.ID] {
+				return c.JSON(http.StatusBadRequest, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: "Statement " + statement.ID + " is sent more than once",
+				})
+			}
+			batchIDs[statement.ID] = true
+
+			exists, err := ctr.XapiRepo.StatementExists(statement.ID)
+			if err != nil {
+				ctr.Logger.Errorf("Failed to check xAPI statement: %v", err)
+				return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: "System Error",
+				})
+			}
+			if exists {
+				return c.JSON(http.StatusConflict, cf.JsonResponse{
+					Status:  cf.FailResponseCode,
+					Message: "Statement " + statement.ID + " already exists",
+				})
+			}
+		}
+	}
+
+	statementIDs := []string{}
+	for _, statement := range statements {
+		if err := ctr.XapiRepo.RecordStatement(userProfile.ID, statement); err != nil {
+			ctr.Logger.Errorf("Failed to store xAPI statement: %v", err)
+			return c.JSON(http.StatusInternalServerError, cf.JsonResponse{
+				Status:  cf.FailResponseCode,
+				Message: "Failed to store statements",
+				Data:    statementIDs,
+			})
+		}
+		statementIDs = append(statementIDs, statement.ID)
+	}
+
+	return c.JSON(http.StatusOK, cf.JsonResponse{
+		Status:  cf.SuccessResponseCode,
+		Message: "Statements stored",
+		Data:    statementIDs,
+	})
+}
@@ -0,0 +1,131 @@
+package xapi
+
+import (
+	cm "orientation-training-api/internal/common"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/utils"
+	"orientation-training-api/internal/platform/xapi"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PgXapiRepository struct {
+	cm.AppRepository
+
+	lrsClient *xapi.LRSClient
+}
+
+func NewPgXapiRepository(logger echo.Logger) (repo *PgXapiRepository) {
+	repo = &PgXapiRepository{}
+	repo.Init(logger)
+	repo.lrsClient = xapi.NewLRSClient()
+	return
+}
+
+// RecordStatement stores a statement in the built-in LRS and forwards it to the
+// external LRS in the background when one is configured
+func (repo *PgXapiRepository) RecordStatement(userID int, statement *xapi.Statement) error {
+	if statement.ID == "" {
+		statement.ID = xapi.NewUUID()
+	}
+
+	now := utils.TimeNowUTC()
+	statement.Stored = now.Format(time.RFC3339)
+
+	timestamp, err := time.Parse(time.RFC3339, statement.Timestamp)
+	if err != nil {
+		timestamp = now
+		statement.Timestamp = now.Format(time.RFC3339)
+	}
+
+	xapiStatement := &m.XapiStatement{
+		StatementID: statement.ID,
+		UserID:      userID,
+		Verb:        statement.Verb.ID,
+		ObjectID:    statement.Object.ID,
+		Statement:   statement,
+		Timestamp:   timestamp,
+	}
+
+	if _, err := repo.DB.Model(xapiStatement).Insert(); err != nil {
+		repo.Logger.Errorf("Error saving xAPI statement: %v", err)
+		return err
+	}
+
+	if repo.lrsClient != nil {
+		go repo.forwardStatement(xapiStatement)
+	}
+
+	return nil
+}
+
+func (repo *PgXapiRepository) forwardStatement(xapiStatement *m.XapiStatement) {
+	if err := repo.lrsClient.SendStatement(xapiStatement.Statement); err != nil {
+		repo.Logger.Errorf("Error forwarding xAPI statement to external LRS: %v", err)
+		return
+	}
+
+	_, err := repo.DB.Model((*m.XapiStatement)(nil)).
+		Set("forwarded_at = ?", utils.TimeNowUTC()).
+		Where("id = ?", xapiStatement.ID).
+		Update()
+	if err != nil {
+		repo.Logger.Errorf("Error marking xAPI statement as forwarded: %v", err)
+	}
+}
+
+// GetStatements returns stored statements filtered by user, verb, activity and time range
+func (repo *PgXapiRepository) GetStatements(params *param.XapiStatementListParams) ([]m.XapiStatement, int, error) {
+	var statements []m.XapiStatement
+
+	query := repo.DB.Model(&statements).
+		Where("deleted_at IS NULL")
+
+	if params.UserID > 0 {
+		query = query.Where("user_id = ?", params.UserID)
+	}
+
+	if params.Verb != "" {
+		query = query.Where("verb = ?", params.Verb)
+	}
+
+	if params.Activity != "" {
+		query = query.Where("object_id = ?", params.Activity)
+	}
+
+	if params.Since != "" {
+		query = query.Where("timestamp > ?", params.Since)
+	}
+
+	if params.Until != "" {
+		query = query.Where("timestamp <= ?", params.Until)
+	}
+
+	count, err := query.Count()
+	if err != nil {
+		repo.Logger.Errorf("Error counting xAPI statements: %v", err)
+		return nil, 0, err
+	}
+
+	if params.RowPerPage > 0 {
+		offset := (params.CurrentPage - 1) * params.RowPerPage
+		query = query.Limit(params.RowPerPage).Offset(offset)
+	}
+
+	err = query.Order("timestamp DESC").Select()
+	if err != nil {
+		repo.Logger.Errorf("Error fetching xAPI statements: %v", err)
+		return nil, 0, err
+	}
+
+	return statements, count, nil
+}
+
+// StatementExists checks whether a statement id has already been stored
+func (repo *PgXapiRepository) StatementExists(statementID string) (bool, error) {
+	return repo.DB.Model((*m.XapiStatement)(nil)).
+		Where("statement_id = ?", statementID).
+		Exists()
+}
@@ -12,7 +12,7 @@ type CourseRepository interface {
 	GetCourses(courseListParams *param.CourseListParams) ([]m.Course, int, error)
 	GetAllCourses() ([]m.Course, error)
 	SaveCourse(createCourseParams *param.CreateCourseParams, courseSkillKeywordRepo CourseSkillKeywordRepository) (m.Course, error)
-	InsertCourseWithTx(tx *pg.Tx, title, description, thumbnail string, category string, createdBy int) (m.Course, error)
+	InsertCourseWithTx(tx *pg.Tx, title, description, thumbnail string, category string, createdBy int, navigationMode string) (m.Course, error)
 	UpdateCourse(courseParams *param.UpdateCourseParams, userCourseRepo UserCourseRepository, courseSkillKeywordRepo CourseSkillKeywordRepository) error
 	DeleteCourse(courseID int) error
 	GetUserCourses(userID int) ([]m.Course, error)
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	m "orientation-training-api/internal/models"
+)
+
+type CoursePrerequisiteRepository interface {
+	GetPrerequisitesByCourseID(courseID int) ([]m.Course, error)
+	GetAllCoursePrerequisites() ([]m.CoursePrerequisite, error)
+	ReplaceCoursePrerequisites(courseID int, prerequisiteIDs []int) error
+	GetMissingPrerequisites(userID int, courseID int) ([]m.Course, error)
+}
@@ -10,6 +10,11 @@ type ModuleRepository interface {
 	SaveModule(createModuleParams *param.CreateModuleParams) (m.Module, error)
 	GetModuleByID(id int) (m.Module, error)
 	DeleteModule(moduleID int) error
+	UpdateModuleNavigationMode(moduleID int, navigationMode string) error
+	UpdateModuleRelease(moduleID int, releaseType string, releaseDate string, releaseOffsetDays int) error
+	GetModulesWithReleaseRule() ([]m.Module, error)
+	GetReleaseNotifications(moduleID int) ([]m.ModuleReleaseNotification, error)
+	MarkReleaseNotified(moduleID int, userID int, cycle int) error
 	GetModuleIDsByCourseID(courseID int) ([]int, error)
 	GetModulesByCourseID(courseID int) ([]m.Module, error)
 	GetModuleByPositionAndCourse(courseID int, position int) (m.Module, error)
@@ -12,4 +12,5 @@ type ModuleItemRepository interface {
 	DeleteModuleItem(moduleItemID int) error
 	GetModuleItemsByModuleIDs(moduleIDs []int) ([]m.ModuleItem, error)
 	GetModuleItemsByModuleID(moduleID int) ([]m.ModuleItem, error)
+	GetModuleItemsByQuizID(quizID int) ([]m.ModuleItem, error)
 }
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+)
+
+// ModuleItemCompletionRepository defines methods for accessing the per item activity of users
+type ModuleItemCompletionRepository interface {
+	GetCompletionsByCourse(userID int, courseID int, cycle int) ([]m.ModuleItemCompletion, error)
+	GetCompletion(userID int, moduleItemID int, cycle int) (m.ModuleItemCompletion, error)
+	StartItem(completion *m.ModuleItemCompletion) error
+	RecordItemView(completion *m.ModuleItemCompletion) error
+	CompleteItem(completion *m.ModuleItemCompletion) error
+	RecordHeartbeat(completion *m.ModuleItemCompletion, credit func(previous m.ModuleItemCompletion) (int, bool)) error
+	ReopenItemsWithTx(tx *pg.Tx, userID int, cycle int, moduleItemIDs []int) error
+	InsertCompletionsWithTx(tx *pg.Tx, completions []m.ModuleItemCompletion) error
+}
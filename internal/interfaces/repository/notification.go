@@ -0,0 +1,13 @@
+package repository
+
+import (
+	m "orientation-training-api/internal/models"
+)
+
+// NotificationRepository defines methods for accessing in-app notifications
+type NotificationRepository interface {
+	CreateNotification(notification *m.Notification) error
+	GetNotificationsByUserID(userID int, unreadOnly bool) ([]m.Notification, error)
+	CountUnreadNotifications(userID int) (int, error)
+	MarkNotificationsRead(userID int, notificationIDs []int) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	m "orientation-training-api/internal/models"
+)
+
+// PathAssignmentRuleRepository defines methods for accessing automatic path assignment rules
+type PathAssignmentRuleRepository interface {
+	GetRules() ([]m.PathAssignmentRule, error)
+	GetActiveRules() ([]m.PathAssignmentRule, error)
+	GetRuleByID(ruleID int) (m.PathAssignmentRule, error)
+	SaveRule(rule *m.PathAssignmentRule) error
+	DeleteRule(ruleID int) error
+}
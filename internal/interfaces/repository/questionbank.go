@@ -0,0 +1,17 @@
+package repository
+
+import (
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+)
+
+type QuestionBankRepository interface {
+	GetQuestionBanks(params *param.QuestionBankListParams) ([]m.QuestionBank, error)
+	GetQuestionBankByID(questionBankID int) (m.QuestionBank, error)
+	SaveQuestionBank(questionBank *m.QuestionBank) error
+	DeleteQuestionBank(questionBankID int) error
+	CountDrawRulesByBank(questionBankID int) (int, error)
+	GetBankQuestions(questionBankID int, difficulty int) ([]m.QuizQuestion, error)
+	GetBankQuestionByID(questionID int) (m.QuizQuestion, error)
+	DeleteBankQuestion(questionID int) error
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	param "orientation-training-api/internal/interfaces/requestparams"
 	m "orientation-training-api/internal/models"
+	"time"
+
+	"github.com/go-pg/pg/v9"
 )
 
 type QuizRepository interface {
@@ -12,11 +15,28 @@ type QuizRepository interface {
 	DeleteQuiz(quizID int) error
 	GetQuizQuestionsWithAnswers(quizID int) ([]m.QuizQuestion, error)
 	SaveQuizQuestion(question *m.QuizQuestion, answers []m.QuizAnswer) error
+	GetQuizQuestionByID(questionID int) (m.QuizQuestion, error)
+	GetQuizAnswerByID(answerID int) (m.QuizAnswer, error)
+	DeleteQuizQuestion(questionID int) error
+	ReorderQuizQuestions(quizID int, questionIDs []int) error
 	SaveQuizSubmission(submission *m.QuizSubmission) error
 	GetQuizSubmissionsByUser(userID int, quizID int) ([]m.QuizSubmission, error)
+	GetQuizSubmissionByID(submissionID int) (m.QuizSubmission, error)
 	CreateQuizWithQuestionsAndAnswers(quizData *param.QuizData, title string) (int, error)
 	GetMaxQuizAttempt(userID int, quizID int) (int, error)
 	GetEssaySubmissionsPendingReview() ([]m.QuizSubmission, error)
 	ReviewEssaySubmission(submissionID int, score float64, feedback string, reviewerID int) error
 	GetPendingEssayReviewsCountForCourse(userID int, courseID int) (int, error)
+	ArchiveQuizSubmissions(userID int, quizIDs []int) error
+	ArchiveQuizSubmissionsWithTx(tx *pg.Tx, userID int, quizIDs []int) error
+	StartQuizAttempt(attempt *m.QuizAttempt, questions []m.QuizAttemptQuestion, expiredBefore time.Time) (bool, error)
+	GetQuizAttemptByID(attemptID int) (m.QuizAttempt, error)
+	SubmitQuizAttempt(attempt *m.QuizAttempt, submissions []m.QuizSubmission, expiredBefore time.Time) (bool, error)
+	ExpireQuizAttempt(attemptID int) (bool, error)
+	GetExpiredQuizAttempts(expiredBefore time.Time) ([]m.QuizAttempt, error)
+	GetQuizAttemptsByUser(userID int, quizID int) ([]m.QuizAttempt, error)
+	GetAttemptQuestions(attemptID int) ([]m.QuizAttemptQuestion, error)
+	GetCurrentAttemptNumber(userID int, quizID int) (int, error)
+	GetDrawRules(quizID int) ([]m.QuizDrawRule, error)
+	SaveDrawRules(quizID int, rules []m.QuizDrawRule) error
 }
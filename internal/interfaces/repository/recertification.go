@@ -0,0 +1,17 @@
+package repository
+
+import (
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+)
+
+// RecertificationRepository defines methods for accessing recertification policies and enrollment history
+type RecertificationRepository interface {
+	GetPolicyByCourseID(courseID int) (m.RecertificationPolicy, error)
+	SavePolicy(policy *m.RecertificationPolicy) error
+	DeletePolicyByCourseID(courseID int) error
+	GetExpiredUserProgresses() ([]m.UserProgress, error)
+	StartNewCycle(userProgress *m.UserProgress, history *m.UserProgressHistory, dueDate string, archiveCycle func(tx *pg.Tx) error) error
+	GetProgressHistories(userID int, courseID int) ([]m.UserProgressHistory, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
+)
+
+// ScormRepository defines methods for accessing SCORM packages and runtime data
+type ScormRepository interface {
+	SaveScormPackage(scormPackage *m.ScormPackage) error
+	GetScormPackageByModuleItemID(moduleItemID int) (m.ScormPackage, error)
+	DeleteScormPackageByModuleItemID(moduleItemID int) error
+	GetScormRuntime(userID int, moduleItemID int) (m.ScormRuntime, error)
+	SaveScormRuntime(scormRuntime *m.ScormRuntime) error
+	ResetScormRuntimesWithTx(tx *pg.Tx, userID int, moduleItemIDs []int) error
+}
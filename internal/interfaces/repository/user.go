@@ -10,6 +10,7 @@ type UserRepository interface {
 	UpdateLastLogin(userID int) error
 	GetUserProfile(id int) (m.User, error)
 	GetUsersByRoleID(roleID int) ([]m.User, error)
+	GetDepartmentManagers(department string) ([]m.User, error)
 	GetAllUsersExceptRole(roleID int) ([]m.User, error)
 	GetUserProgressByUserID(userID int) ([]m.UserProgress, error)
 	GetUsersWithoutProgress(roleID int) ([]m.User, error)
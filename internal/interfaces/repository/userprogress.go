@@ -2,6 +2,8 @@ package repository
 
 import (
 	m "orientation-training-api/internal/models"
+
+	"github.com/go-pg/pg/v9"
 )
 
 // UserProgressRepository defines methods for accessing user progress data
@@ -11,4 +13,11 @@ type UserProgressRepository interface {
 	GetUserProgressByCourseID(courseID int) ([]m.UserProgress, error)
 	GetAllUserProgressByUserID(userID int) ([]m.UserProgress, error)
 	ReviewUserProgress(userID int, courseID int, performanceRating float64, performanceComment string, reviewedBy int) error
+	GetNewlyOverdueUserProgresses() ([]m.UserProgress, error)
+	MarkUserProgressOverdue(userProgressID int) error
+	GetLatestCycle(userID int, courseID int) (int, error)
+	ResetUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, dueDate string, archiveCycle func(tx *pg.Tx) error) error
+	ReopenUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, reopenItems func(tx *pg.Tx) error) error
+	UnassignUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, archiveCycle func(tx *pg.Tx) error) error
+	TransferUserProgress(userProgress *m.UserProgress, history *m.UserProgressHistory, newProgress *m.UserProgress, carryOver func(tx *pg.Tx) error) error
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+	"orientation-training-api/internal/platform/xapi"
+)
+
+// XapiRepository defines methods for accessing the built-in LRS
+type XapiRepository interface {
+	RecordStatement(userID int, statement *xapi.Statement) error
+	GetStatements(params *param.XapiStatementListParams) ([]m.XapiStatement, int, error)
+	StatementExists(statementID string) (bool, error)
+}
@@ -7,6 +7,8 @@ type CreateCourseParams struct {
 	Category        string `json:"category" valid:"required"`
 	CreatedBy       int    `json:"created_by"`
 	SkillKeywordIDs []int  `json:"skill_keyword_ids"`
+	PrerequisiteIDs []int  `json:"prerequisite_ids"`
+	NavigationMode  string `json:"navigation_mode"`
 }
 
 type UpdateCourseParams struct {
@@ -16,6 +18,8 @@ type UpdateCourseParams struct {
 	Thumbnail       string `json:"thumbnail" form:"course_thumbnail"`
 	Category        string `json:"category" form:"course_category"`
 	SkillKeywordIDs []int  `json:"skill_keyword_ids"`
+	PrerequisiteIDs []int  `json:"prerequisite_ids"`
+	NavigationMode  string `json:"navigation_mode" form:"navigation_mode"`
 }
 
 type CourseIDParam struct {
@@ -32,3 +36,21 @@ type UserCourseInfoRecords struct {
 	UserId   int    `json:"user_id"`
 	FullName string `json:"full_name"`
 }
+
+// RecertificationPolicyParams defines parameters for fetching or deleting the recertification policy of a course
+type RecertificationPolicyParams struct {
+	CourseID int `json:"course_id" valid:"required"`
+}
+
+// SaveRecertificationPolicyParams defines parameters for creating or updating a recertification policy
+type SaveRecertificationPolicyParams struct {
+	CourseID     int `json:"course_id" valid:"required"`
+	ValidityDays int `json:"validity_days" valid:"required"`
+	GraceDays    int `json:"grace_days"`
+}
+
+// ProgressHistoryParams defines parameters for fetching the enrollment cycles of a user in a course
+type ProgressHistoryParams struct {
+	CourseID int `json:"course_id" valid:"required"`
+	UserID   int `json:"user_id"`
+}
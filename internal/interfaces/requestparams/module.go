@@ -4,6 +4,8 @@ type CreateModuleParams struct {
 	Title    string `json:"title" form:"module_title" valid:"required"`
 	CourseID int    `json:"course_id" form:"course_id" valid:"required"`
 	Position int    `json:"position" form:"position" valid:"required"`
+
+	NavigationMode string `json:"navigation_mode" form:"navigation_mode"`
 }
 
 type ModuleListParams struct {
@@ -16,3 +18,18 @@ type ModuleListParams struct {
 type ModuleIDParam struct {
 	ModuleID int `json:"id" valid:"required"`
 }
+
+// UpdateModuleReleaseParams sets the release rule of a module, an empty release type removes it
+// release_date (YYYY-MM-DD) is used by absolute_date rules, release_offset_days by the others
+type UpdateModuleReleaseParams struct {
+	ModuleID          int    `json:"id" valid:"required"`
+	ReleaseType       string `json:"release_type"`
+	ReleaseDate       string `json:"release_date"`
+	ReleaseOffsetDays int    `json:"release_offset_days"`
+}
+
+// UpdateModuleNavigationParams sets the navigation mode of a module, an empty mode follows the course
+type UpdateModuleNavigationParams struct {
+	ModuleID       int    `json:"id" valid:"required"`
+	NavigationMode string `json:"navigation_mode"`
+}
@@ -6,16 +6,22 @@ type QuizOption struct {
 	IsCorrect   bool   `json:"is_correct"`
 }
 
-// QuizQuestion represents a question in a quiz
+// QuizQuestion represents a question in a quiz, question_type defaults to the question type of the quiz data
+// and the answer key of the question types without options is given as for a quiz question
 type QuizQuestion struct {
-	QuestionText  string       `json:"question_text"`
-	Weight        float64      `json:"weight"`
-	AllowMultiple bool         `json:"allow_multiple"`
-	Options       []QuizOption `json:"options"`
+	QuestionKeyParams
+	QuestionType    int          `json:"question_type"`
+	QuestionText    string       `json:"question_text"`
+	Explanation     string       `json:"explanation"`
+	Weight          float64      `json:"weight"`
+	AllowMultiple   bool         `json:"allow_multiple"`
+	ScoringStrategy string       `json:"scoring_strategy"`
+	Options         []QuizOption `json:"options"`
 }
 
-// QuizData represents the data for a quiz
+// QuizData represents the data for a quiz, question_type applies to the questions without their own
 type QuizData struct {
+	QuizPolicyParams
 	QuestionType int            `json:"question_type"`
 	Difficulty   int            `json:"difficulty"`
 	TotalScore   float64        `json:"total_score"`
@@ -0,0 +1,12 @@
+package requestparams
+
+// GetNotificationsParams defines parameters for listing the notifications of the logged in user
+type GetNotificationsParams struct {
+	UnreadOnly bool `json:"unread_only"`
+}
+
+// MarkNotificationsReadParams defines parameters for marking notifications as read
+// An empty list marks every notification of the user as read
+type MarkNotificationsReadParams struct {
+	NotificationIDs []int `json:"notification_ids"`
+}
@@ -0,0 +1,26 @@
+package requestparams
+
+// SavePathAssignmentRuleParams defines parameters for creating (id = 0) or updating a path assignment rule
+type SavePathAssignmentRuleParams struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name" valid:"required"`
+	Department     string `json:"department"`
+	RoleID         int    `json:"role_id"`
+	TemplatePathID int    `json:"template_path_id" valid:"required"`
+	DueInDays      int    `json:"due_in_days"`
+	Active         *bool  `json:"active"`
+}
+
+// PathAssignmentRuleIDParams defines parameters for deleting a path assignment rule
+type PathAssignmentRuleIDParams struct {
+	ID int `json:"id" valid:"required"`
+}
+
+// PreviewPathAssignmentRuleParams defines parameters for previewing the users a rule applies to
+// Either the id of a saved rule or an unsaved definition
+type PreviewPathAssignmentRuleParams struct {
+	ID             int    `json:"id"`
+	Department     string `json:"department"`
+	RoleID         int    `json:"role_id"`
+	TemplatePathID int    `json:"template_path_id"`
+}
@@ -0,0 +1,40 @@
+package requestparams
+
+// QuestionBankListParams defines parameters for listing question banks, skill_keyword_id filters the banks by tag
+type QuestionBankListParams struct {
+	SkillKeywordID int `json:"skill_keyword_id"`
+}
+
+// SaveQuestionBankParams defines parameters for creating a question bank, or updating it when id is set
+type SaveQuestionBankParams struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name" valid:"required"`
+	Description     string `json:"description"`
+	SkillKeywordIDs []int  `json:"skill_keyword_ids"`
+}
+
+// QuestionBankParams defines parameters for fetching or deleting a question bank
+type QuestionBankParams struct {
+	QuestionBankID int `json:"question_bank_id" valid:"required"`
+}
+
+// SaveBankQuestionParams defines parameters for creating a question of a question bank, or updating it when id is set
+// difficulty is one of the difficulty levels of a quiz, scoring_strategy is one of the scoring strategies of a quiz question
+type SaveBankQuestionParams struct {
+	QuestionKeyParams
+	ID                int               `json:"id"`
+	QuestionBankID    int               `json:"question_bank_id" valid:"required"`
+	QuestionType      int               `json:"question_type" valid:"required"`
+	QuestionText      string            `json:"question_text" valid:"required"`
+	Explanation       string            `json:"explanation"`
+	Weight            float64           `json:"weight" valid:"required"`
+	Difficulty        int               `json:"difficulty" valid:"required"`
+	IsMultipleCorrect bool              `json:"is_multiple_correct"`
+	ScoringStrategy   string            `json:"scoring_strategy"`
+	Answers           []QuizAnswerParam `json:"answers"`
+}
+
+// DeleteBankQuestionParams defines parameters for deleting a question of a question bank
+type DeleteBankQuestionParams struct {
+	QuestionID int `json:"question_id" valid:"required"`
+}
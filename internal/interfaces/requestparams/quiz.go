@@ -1,15 +1,52 @@
 package requestparams
 
+// QuizPolicyParams defines the attempt policy and the pass mark of a quiz
+// max_attempts 0 allows unlimited attempts, attempt_cooldown is in minutes and
+// score_policy is "highest", "latest" (default) or "average".
+// pass_mark is a percentage of the total score (default 70) or a score when pass_mark_type is "score",
+// it is kept on update when omitted.
+// shuffle_questions and shuffle_options give each attempt its own order of questions and answer options.
+type QuizPolicyParams struct {
+	MaxAttempts      int      `json:"max_attempts"`
+	AttemptCooldown  int      `json:"attempt_cooldown"`
+	ScorePolicy      string   `json:"score_policy"`
+	PassMark         *float64 `json:"pass_mark"`
+	PassMarkType     string   `json:"pass_mark_type"`
+	ShuffleQuestions bool     `json:"shuffle_questions"`
+	ShuffleOptions   bool     `json:"shuffle_options"`
+}
+
 // CreateQuizParams defines parameters for creating a new quiz
 type CreateQuizParams struct {
+	QuizPolicyParams
+	Title      string  `json:"title" valid:"required"`
+	Difficulty int     `json:"difficulty" valid:"required"`
+	TotalScore float64 `json:"total_score" valid:"required"`
+	TimeLimit  int     `json:"time_limit" valid:"required"` // in minutes
+}
+
+// ImportQuizParams defines parameters for creating a quiz from a GIFT, QTI 2.1 or CSV file,
+// file is a base64 data URI and format ("gift", "qti" or "csv") is found from the extension of file_name when empty
+type ImportQuizParams struct {
+	QuizPolicyParams
 	Title      string  `json:"title" valid:"required"`
 	Difficulty int     `json:"difficulty" valid:"required"`
 	TotalScore float64 `json:"total_score" valid:"required"`
 	TimeLimit  int     `json:"time_limit" valid:"required"` // in minutes
+	Format     string  `json:"format"`
+	FileName   string  `json:"file_name"`
+	File       string  `json:"file" valid:"required"`
+}
+
+// ExportQuizParams defines parameters for exporting the questions of a quiz as a "gift", "qti" or "csv" file
+type ExportQuizParams struct {
+	QuizID int    `json:"quiz_id" valid:"required"`
+	Format string `json:"format" valid:"required"`
 }
 
 // UpdateQuizParams defines parameters for updating an existing quiz
 type UpdateQuizParams struct {
+	QuizPolicyParams
 	ID         int     `json:"id" valid:"required"`
 	Title      string  `json:"title" valid:"required"`
 	Difficulty int     `json:"difficulty" valid:"required"`
@@ -34,20 +71,105 @@ type DeleteQuizParams struct {
 	QuizID int `json:"quiz_id" valid:"required"`
 }
 
-// QuizAnswerParam defines parameters for a quiz answer
+// QuestionKeyParams defines the answer key of the question types graded without answer options:
+// correct_boolean for true/false, accepted_answers for short answer, match_pairs for matching,
+// order_items in their correct order for ordering and numeric_answers for numeric questions
+type QuestionKeyParams struct {
+	CorrectBoolean  *bool                 `json:"correct_boolean"`
+	AcceptedAnswers []AcceptedAnswerParam `json:"accepted_answers"`
+	MatchPairs      []MatchPairParam      `json:"match_pairs"`
+	OrderItems      []string              `json:"order_items"`
+	NumericAnswers  []NumericAnswerParam  `json:"numeric_answers"`
+}
+
+// AcceptedAnswerParam defines an answer accepted for a short answer question
+type AcceptedAnswerParam struct {
+	AnswerText       string `json:"answer_text"`
+	CaseSensitive    bool   `json:"case_sensitive"`
+	IgnoreWhitespace bool   `json:"ignore_whitespace"`
+}
+
+// MatchPairParam defines a prompt of a matching question with its match
+type MatchPairParam struct {
+	PromptText string `json:"prompt_text"`
+	MatchText  string `json:"match_text"`
+}
+
+// NumericAnswerParam defines a value accepted for a numeric question, give or take tolerance
+type NumericAnswerParam struct {
+	Value     float64 `json:"value"`
+	Tolerance float64 `json:"tolerance"`
+}
+
+// MatchAnswerParam matches the prompt of the pair pair_id with the match of option key match_id
+type MatchAnswerParam struct {
+	PairID  int `json:"pair_id"`
+	MatchID int `json:"match_id"`
+}
+
+// QuizAnswerParam defines parameters for a quiz answer, answer_id keeps an existing answer when a question is updated
 type QuizAnswerParam struct {
+	AnswerID   int    `json:"answer_id"`
 	AnswerText string `json:"answer_text" valid:"required"`
 	IsCorrect  bool   `json:"is_correct"`
 }
 
 // CreateQuizQuestionParams defines parameters for creating a quiz question
+// scoring_strategy is "all_or_nothing" (default), "proportional" or "right_minus_wrong"
 type CreateQuizQuestionParams struct {
+	QuestionKeyParams
 	QuizID            int               `json:"quiz_id" valid:"required"`
 	QuestionType      int               `json:"question_type" valid:"required"` // 1 for multiple choice, 2 for text
 	QuestionText      string            `json:"question_text" valid:"required"`
 	Weight            float64           `json:"weight" valid:"required"`
 	IsMultipleCorrect bool              `json:"is_multiple_correct"`
-	Answers           []QuizAnswerParam `json:"answers" valid:"required"`
+	ScoringStrategy   string            `json:"scoring_strategy"`
+	Answers           []QuizAnswerParam `json:"answers"`
+}
+
+// UpdateQuizQuestionParams defines parameters for updating a quiz question, the question type is kept when question_type is empty.
+// The answers without an answer_id are added and the answers of the question left out are deleted.
+type UpdateQuizQuestionParams struct {
+	QuestionKeyParams
+	QuestionID        int               `json:"question_id" valid:"required"`
+	QuestionType      int               `json:"question_type"`
+	QuestionText      string            `json:"question_text" valid:"required"`
+	Explanation       string            `json:"explanation"`
+	Weight            float64           `json:"weight" valid:"required"`
+	IsMultipleCorrect bool              `json:"is_multiple_correct"`
+	ScoringStrategy   string            `json:"scoring_strategy"`
+	Answers           []QuizAnswerParam `json:"answers"`
+}
+
+// DeleteQuizQuestionParams defines parameters for deleting a quiz question
+type DeleteQuizQuestionParams struct {
+	QuestionID int `json:"question_id" valid:"required"`
+}
+
+// ReorderQuizQuestionsParams defines parameters for reordering the questions of a quiz,
+// question_ids lists each question of the quiz once in the new order
+type ReorderQuizQuestionsParams struct {
+	QuizID      int   `json:"quiz_id" valid:"required"`
+	QuestionIDs []int `json:"question_ids"`
+}
+
+// UpdateQuizAnswerParams defines parameters for updating an answer of a multiple choice question
+type UpdateQuizAnswerParams struct {
+	AnswerID   int    `json:"answer_id" valid:"required"`
+	AnswerText string `json:"answer_text" valid:"required"`
+	IsCorrect  bool   `json:"is_correct"`
+}
+
+// DeleteQuizAnswerParams defines parameters for deleting an answer of a multiple choice question
+type DeleteQuizAnswerParams struct {
+	AnswerID int `json:"answer_id" valid:"required"`
+}
+
+// ReorderQuizAnswersParams defines parameters for reordering the answers of a question,
+// answer_ids lists each answer of the question once in the new order
+type ReorderQuizAnswersParams struct {
+	QuestionID int   `json:"question_id" valid:"required"`
+	AnswerIDs  []int `json:"answer_ids"`
 }
 
 // SubmitQuizAnswersParams defines parameters for submitting answers to a quiz
@@ -59,17 +181,26 @@ type SubmitQuizAnswersParams struct {
 }
 
 // QuizAnswer represents a single answer to a quiz question
+// answer_text answers essay, short answer and numeric questions, selected_answer_ids lists the chosen options
+// or the items of an ordering question in order, match_answers pairs the prompts of a matching question
 type QuizAnswer struct {
-	QuestionID        int    `json:"question_id" valid:"required"`
-	AnswerText        string `json:"answer_text"`
-	SelectedAnswerIds []int  `json:"selected_answer_ids"`
+	QuestionID        int                `json:"question_id" valid:"required"`
+	AnswerText        string             `json:"answer_text"`
+	SelectedAnswerIds []int              `json:"selected_answer_ids"`
+	MatchAnswers      []MatchAnswerParam `json:"match_answers"`
+}
+
+// StartQuizParams defines parameters for starting an attempt on a quiz
+type StartQuizParams struct {
+	QuizID int `json:"quiz_id" valid:"required"`
 }
 
 // SubmitFullQuizParams defines parameters for submitting a complete quiz with multiple answers
+// The answers are accepted only for the open attempt returned by /quiz/start
 type SubmitFullQuizParams struct {
-	QuizID      int          `json:"quiz_id" valid:"required"`
-	Answers     []QuizAnswer `json:"answers" valid:"required"`
-	SubmittedAt string       `json:"submitted_at" valid:"required"`
+	QuizID    int          `json:"quiz_id" valid:"required"`
+	AttemptID int          `json:"attempt_id" valid:"required"`
+	Answers   []QuizAnswer `json:"answers" valid:"required"`
 }
 
 // GetQuizResultsParams defines parameters for fetching quiz results
@@ -81,6 +212,20 @@ type GetQuizResultsParams struct {
 // ReviewEssaySubmissionParams defines parameters for reviewing an essay submission
 type ReviewEssaySubmissionParams struct {
 	SubmissionID int     `json:"submission_id" valid:"required"`
-	Score        float64 `json:"score" valid:"required"`
+	Score        float64 `json:"score"`
 	Feedback     string  `json:"feedback"`
 }
+
+// QuizDrawRuleParam draws question_count questions from a question bank for each attempt,
+// difficulty 0 draws questions of any difficulty
+type QuizDrawRuleParam struct {
+	QuestionBankID int `json:"question_bank_id" valid:"required"`
+	Difficulty     int `json:"difficulty"`
+	QuestionCount  int `json:"question_count" valid:"required"`
+}
+
+// SaveQuizDrawRulesParams defines parameters for replacing the draw rules of a quiz, empty rules remove them
+type SaveQuizDrawRulesParams struct {
+	QuizID int                 `json:"quiz_id" valid:"required"`
+	Rules  []QuizDrawRuleParam `json:"rules"`
+}
@@ -0,0 +1,12 @@
+package requestparams
+
+// GetScormRuntimeParams defines parameters for initializing a SCORM runtime session
+type GetScormRuntimeParams struct {
+	ModuleItemID int `json:"module_item_id" valid:"required"`
+}
+
+// CommitScormRuntimeParams defines parameters for persisting the CMI data model of a SCORM item
+type CommitScormRuntimeParams struct {
+	ModuleItemID int               `json:"module_item_id" valid:"required"`
+	Values       map[string]string `json:"values"`
+}
@@ -1,23 +1,53 @@
 package requestparams
 
+// TemplatePathStageParams defines a stage of a template path
+// RequiredCount is the number of courses to complete in an elective stage
+type TemplatePathStageParams struct {
+	Name          string `json:"name"`
+	StageType     string `json:"stage_type"`
+	RequiredCount int    `json:"required_count"`
+	CourseIds     []int  `json:"course_ids"`
+}
+
 // CreateTemplatePathParams defines parameters for creating a new template path
+// A path given only course_ids is a single required stage
 type CreateTemplatePathParams struct {
-	Name        string `json:"name" valid:"required"`
-	Description string `json:"description"`
-	CourseIds   []int  `json:"course_ids" valid:"required"`
-	Duration    int    `json:"duration"`
+	Name        string                    `json:"name" valid:"required"`
+	Description string                    `json:"description"`
+	CourseIds   []int                     `json:"course_ids"`
+	Stages      []TemplatePathStageParams `json:"stages"`
 }
 
 // UpdateTemplatePathParams defines parameters for updating an existing template path
 type UpdateTemplatePathParams struct {
-	TempPathID  int    `json:"id" valid:"required"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	CourseIds   []int  `json:"course_ids"`
-	Duration    int    `json:"duration"`
+	TempPathID  int                       `json:"id" valid:"required"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	CourseIds   []int                     `json:"course_ids"`
+	Stages      []TemplatePathStageParams `json:"stages"`
 }
 
 // DeleteTemplatePathParams defines parameters for deleting a template path
 type TempPathIDParam struct {
 	TempPathID int `json:"id" valid:"required"`
 }
+
+// AssignTemplatePathParams defines parameters for enrolling trainees in the courses of a template path
+type AssignTemplatePathParams struct {
+	DueDateParams
+	TempPathID int   `json:"id" valid:"required"`
+	UserIDs    []int `json:"user_ids" valid:"required"`
+}
+
+// ChooseElectiveParams defines parameters for a trainee choosing an elective course of an assigned path
+type ChooseElectiveParams struct {
+	TempPathID int `json:"template_path_id" valid:"required"`
+	CourseID   int `json:"course_id" valid:"required"`
+}
+
+// TemplatePathProgressParams defines parameters for getting the stage progress of an assigned path
+// UserID is only used by managers to look at a trainee
+type TemplatePathProgressParams struct {
+	TempPathID int `json:"id" valid:"required"`
+	UserID     int `json:"user_id"`
+}
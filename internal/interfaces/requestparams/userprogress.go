@@ -7,18 +7,29 @@ type GetUserProgressParams struct {
 }
 
 // UpdateUserProgressParams defines parameters for updating user progress
+// The positions are the item the trainee wants to move to, completed asks to finish the course.
+// module_item_id completes a given unlocked item, for courses that are not browsed in order.
 type UpdateUserProgressParams struct {
-	CourseID           int    `json:"course_id" valid:"required"`
-	UserID             int    `json:"user_id"`
-	CoursePosition     int    `json:"course_position"`
-	ModulePosition     int    `json:"module_position"`
-	ModuleItemPosition int    `json:"module_item_position"`
-	Completed          bool   `json:"completed"`
-	CompletedDate      string `json:"completed_date"`
+	CourseID           int  `json:"course_id" valid:"required"`
+	UserID             int  `json:"user_id"`
+	ModulePosition     int  `json:"module_position"`
+	ModuleItemPosition int  `json:"module_item_position"`
+	ModuleItemID       int  `json:"module_item_id"`
+	Completed          bool `json:"completed"`
+}
+
+// DueDateParams defines the deadline of an enrollment
+// Either an explicit due_date (YYYY-MM-DD) or due_in_days counted from due_relative_to
+// ("assignment" by default or "company_joined_date")
+type DueDateParams struct {
+	DueDate       string `json:"due_date"`
+	DueInDays     int    `json:"due_in_days"`
+	DueRelativeTo string `json:"due_relative_to"`
 }
 
 // CreateUserProgressParams defines parameters for creating new user progress
 type CreateUserProgressParams struct {
+	DueDateParams
 	UserID    int   `json:"user_id" valid:"required"`
 	CourseIDs []int `json:"course_ids" valid:"required"`
 }
@@ -41,6 +52,7 @@ type GetListTraineeByCourseIDParams struct {
 	Keyword     string `json:"keyword"`
 }
 type AddListTraineeToCourseParams struct {
+	DueDateParams
 	CourseID int   `json:"course_id" validate:"required"`
 	Trainees []int `json:"trainees" validate:"required"`
 }
@@ -52,3 +64,50 @@ type ReviewProgressParams struct {
 	PerformanceRating  float64 `json:"performance_rating" valid:"required"`
 	PerformanceComment string  `json:"performance_comment" valid:"required"`
 }
+
+// ViewModuleItemParams defines parameters for recording a view of a module item
+type ViewModuleItemParams struct {
+	ModuleItemID int `json:"module_item_id" valid:"required"`
+}
+
+// HeartbeatParams defines parameters for a heartbeat of the lecture player, position is in seconds for a video
+// and the page or slide shown for a file or slide lecture
+type HeartbeatParams struct {
+	ModuleItemID int     `json:"module_item_id" valid:"required"`
+	Position     float64 `json:"position"`
+}
+
+// ResetProgressParams defines parameters for restarting an enrollment from the beginning
+// The current due date is kept when no new deadline is given
+type ResetProgressParams struct {
+	DueDateParams
+	UserID              int    `json:"user_id" valid:"required"`
+	CourseID            int    `json:"course_id" valid:"required"`
+	ArchiveQuizAttempts bool   `json:"archive_quiz_attempts"`
+	Note                string `json:"note"`
+}
+
+// ReopenProgressParams defines parameters for reopening a completed enrollment
+// module_item_id is the first item to take again, by default the last item of the course
+type ReopenProgressParams struct {
+	UserID       int    `json:"user_id" valid:"required"`
+	CourseID     int    `json:"course_id" valid:"required"`
+	ModuleItemID int    `json:"module_item_id"`
+	Note         string `json:"note"`
+}
+
+// UnassignCourseParams defines parameters for removing a course from a trainee
+type UnassignCourseParams struct {
+	UserID              int    `json:"user_id" valid:"required"`
+	CourseID            int    `json:"course_id" valid:"required"`
+	ArchiveQuizAttempts bool   `json:"archive_quiz_attempts"`
+	Note                string `json:"note"`
+}
+
+// TransferProgressParams defines parameters for moving a trainee from a course to another one
+type TransferProgressParams struct {
+	UserID     int    `json:"user_id" valid:"required"`
+	CourseID   int    `json:"course_id" valid:"required"`
+	ToCourseID int    `json:"to_course_id" valid:"required"`
+	Note       string `json:"note"`
+}
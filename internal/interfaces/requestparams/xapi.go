@@ -0,0 +1,12 @@
+package requestparams
+
+// XapiStatementListParams defines parameters for querying stored xAPI statements
+type XapiStatementListParams struct {
+	UserID      int    `json:"user_id" query:"user_id"`
+	Verb        string `json:"verb" query:"verb"`
+	Activity    string `json:"activity" query:"activity"`
+	Since       string `json:"since" query:"since"`
+	Until       string `json:"until" query:"until"`
+	CurrentPage int    `json:"current_page" query:"current_page"`
+	RowPerPage  int    `json:"row_per_page" query:"row_per_page"`
+}
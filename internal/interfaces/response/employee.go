@@ -23,6 +23,18 @@ type EmployeeDetail struct {
 	UserInfo     UserInfo     `json:"userInfo"`
 	ProcessStats ProcessStats `json:"processStats"`
 	ProcessInfo  []CourseInfo `json:"processInfo"`
+	PathInfo     []PathInfo   `json:"pathInfo"`
+}
+
+// PathInfo represents the completion of a template path assigned to the employee
+type PathInfo struct {
+	TemplatePathID   int                 `json:"templatePathId"`
+	Name             string              `json:"name"`
+	TotalCourses     int                 `json:"totalCourses"`
+	CompletedCourses int                 `json:"completedCourses"`
+	Progress         int                 `json:"progress"`
+	Completed        bool                `json:"completed"`
+	Stages           []PathStageProgress `json:"stages"`
 }
 
 // UserInfo represents basic information about the employee
@@ -43,6 +55,8 @@ type ProcessStats struct {
 	TotalScore               float64  `json:"totalScore"`
 	UserScore                float64  `json:"userScore"`
 	CompletedDate            string   `json:"completedDate,omitempty"`
+	OverdueCourses           int      `json:"overdueCourses"`
+	TotalTimeSpent           int      `json:"totalTimeSpent"`
 	AveragePerformanceRating float64  `json:"averagePerformanceRating,omitempty"`
 	UserSkills               []string `json:"userSkills,omitempty"`
 }
@@ -55,11 +69,15 @@ type CourseInfo struct {
 	UserScore      float64     `json:"userScore,omitempty"`
 	TotalScore     float64     `json:"totalScore,omitempty"`
 	CompletedDate  string      `json:"completedDate,omitempty"`
+	DueDate        string      `json:"dueDate,omitempty"`
+	TemplatePathID int         `json:"templatePathId,omitempty"`
+	Overdue        bool        `json:"overdue"`
 	HasAssessment  bool        `json:"hasAssessment"`
 	Assessment     *Assessment `json:"assessment,omitempty"`
 	Progress       int         `json:"progress,omitempty"`
 	CurrentModule  string      `json:"currentModule,omitempty"`
 	PendingReviews int         `json:"pendingReviews"`
+	TimeSpent      int         `json:"timeSpent"`
 	SkillKeywords  []string    `json:"skill_keywords,omitempty"`
 }
 
@@ -16,6 +16,10 @@ type LectureItemResponse struct {
 	ModuleItemPosition int         `json:"module_item_position"`
 	ItemType           string      `json:"item_type"`
 	Unlocked           bool        `json:"unlocked"`
+	Completed          bool        `json:"completed"`
+	TimeSpent          int         `json:"time_spent"`
+	RequiredTimeMet    bool        `json:"required_time_met"`
+	UnlockDate         string      `json:"unlock_date,omitempty"`
 	Content            interface{} `json:"content"`
 }
 
@@ -36,23 +40,38 @@ type FileContentResponse struct {
 }
 
 // QuizContentResponse represents quiz content
+// QuizType is essay when all questions are essays, mixed when essays come with auto-graded questions
+// and multiple_choice otherwise, each question tells its own question type
 type QuizContentResponse struct {
-	QuizID     int                    `json:"quiz_id"`
-	QuizType   string                 `json:"quiz_type"`
-	QuizTitle  string                 `json:"quiz_title"`
-	Difficulty string                 `json:"difficulty"`
-	TotalScore float64                `json:"total_score"`
-	TimeLimit  int                    `json:"time_limit"`
-	Questions  []QuizQuestionResponse `json:"questions"`
+	QuizID     int     `json:"quiz_id"`
+	QuizType   string  `json:"quiz_type"`
+	QuizTitle  string  `json:"quiz_title"`
+	Difficulty string  `json:"difficulty"`
+	TotalScore float64 `json:"total_score"`
+	TimeLimit  int     `json:"time_limit"`
+
+	// PassThreshold is the score an attempt needs to pass, from PassMark and PassMarkType
+	PassMark      float64 `json:"pass_mark"`
+	PassMarkType  string  `json:"pass_mark_type"`
+	PassThreshold float64 `json:"pass_threshold"`
+
+	// DrawsQuestions is set when each attempt draws its own questions from question banks,
+	// they are returned by /quiz/start and Questions only lists the questions every attempt shows
+	DrawsQuestions bool                   `json:"draws_questions"`
+	Questions      []QuizQuestionResponse `json:"questions"`
 }
 
 // QuizQuestionResponse represents a quiz question
+// Options are the answer options, the items of an ordering question or the matches of a matching question,
+// Prompts are the prompts of a matching question
 type QuizQuestionResponse struct {
 	QuestionID    int                  `json:"question_id"`
+	QuestionType  int                  `json:"question_type"`
 	QuestionText  string               `json:"question_text"`
 	AllowMultiple bool                 `json:"allow_multiple"`
 	Points        float64              `json:"points,omitempty"`
 	Options       []QuizOptionResponse `json:"options"`
+	Prompts       []QuizOptionResponse `json:"prompts,omitempty"`
 }
 
 // QuizOptionResponse represents a quiz option
@@ -60,3 +79,33 @@ type QuizOptionResponse struct {
 	ID   int    `json:"id"`
 	Text string `json:"text"`
 }
+
+// ScormContentResponse represents SCORM package content
+type ScormContentResponse struct {
+	Version      string `json:"version"`
+	LaunchURL    string `json:"launch_url"`
+	RequiredTime int    `json:"required_time"`
+}
+
+// PrerequisiteCourseResponse represents a prerequisite course
+type PrerequisiteCourseResponse struct {
+	CourseID int    `json:"course_id"`
+	Title    string `json:"title"`
+}
+
+// LockedLectureListResponse represents the lecture list of a course whose prerequisites are not completed
+type LockedLectureListResponse struct {
+	Locked               bool                         `json:"locked"`
+	MissingPrerequisites []PrerequisiteCourseResponse `json:"missing_prerequisites"`
+	Modules              []LectureModuleResponse      `json:"modules"`
+}
+
+// HeartbeatResponse represents the time tracked on a lecture after a heartbeat of the player
+type HeartbeatResponse struct {
+	ModuleItemID    int  `json:"module_item_id"`
+	TimeSpent       int  `json:"time_spent"`
+	RequiredTime    int  `json:"required_time"`
+	RequiredTimeMet bool `json:"required_time_met"`
+	Credited        int  `json:"credited"`
+	Flagged         bool `json:"flagged"`
+}
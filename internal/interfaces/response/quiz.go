@@ -1,5 +1,7 @@
 package response
 
+import "time"
+
 // PendingReviewResponse represents the response for GetQuizPendingReview
 type PendingReviewResponse struct {
 	UserID     int                 `json:"user_id"`
@@ -18,3 +20,40 @@ type PendingReviewItem struct {
 	SubmittedAt  string  `json:"submitted_at"`
 	MaxScore     float64 `json:"maxScore"`
 }
+
+// QuizAttemptResponse represents an attempt started on a quiz
+// DeadlineAt is null and RemainingSeconds is 0 for quizzes without a time limit
+type QuizAttemptResponse struct {
+	AttemptID        int        `json:"attempt_id"`
+	QuizID           int        `json:"quiz_id"`
+	Attempt          int        `json:"attempt"`
+	Status           string     `json:"status"`
+	StartedAt        time.Time  `json:"started_at"`
+	DeadlineAt       *time.Time `json:"deadline_at"`
+	TimeLimit        int        `json:"time_limit"`
+	RemainingSeconds int        `json:"remaining_seconds"`
+	Resumed          bool       `json:"resumed"`
+
+	Questions []AttemptQuestionResponse `json:"questions"`
+}
+
+// AttemptQuestionResponse represents a question shown in a quiz attempt, its correct answers are not included
+// Answers are the answer options, the items of an ordering question or the matches of a matching question,
+// Prompts are the prompts of a matching question
+type AttemptQuestionResponse struct {
+	QuestionID        int                     `json:"question_id"`
+	Position          int                     `json:"position"`
+	QuestionType      int                     `json:"question_type"`
+	QuestionText      string                  `json:"question_text"`
+	IsMultipleCorrect bool                    `json:"is_multiple_correct"`
+	ScoringStrategy   string                  `json:"scoring_strategy"`
+	Points            float64                 `json:"points"`
+	Answers           []AttemptAnswerResponse `json:"answers"`
+	Prompts           []AttemptAnswerResponse `json:"prompts,omitempty"`
+}
+
+// AttemptAnswerResponse represents an answer choice of a question shown in a quiz attempt
+type AttemptAnswerResponse struct {
+	AnswerID   int    `json:"answer_id"`
+	AnswerText string `json:"answer_text"`
+}
@@ -0,0 +1,24 @@
+package response
+
+// PathStageProgress represents the completion of a template path stage by a trainee
+// RequiredCount is the number of courses to complete, ElectivesToChoose the electives still to pick
+type PathStageProgress struct {
+	StageID            int               `json:"stage_id"`
+	Name               string            `json:"name"`
+	Position           int               `json:"position"`
+	StageType          string            `json:"stage_type"`
+	RequiredCount      int               `json:"required_count"`
+	CompletedCount     int               `json:"completed_count"`
+	Completed          bool              `json:"completed"`
+	ElectivesToChoose  int               `json:"electives_to_choose"`
+	RemainingElectives []int             `json:"remaining_electives"`
+	Courses            []PathStageCourse `json:"courses"`
+}
+
+// PathStageCourse represents a course of a stage and the trainee enrollment in it
+type PathStageCourse struct {
+	CourseID  int  `json:"course_id"`
+	Position  int  `json:"position"`
+	Enrolled  bool `json:"enrolled"`
+	Completed bool `json:"completed"`
+}
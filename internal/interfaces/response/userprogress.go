@@ -0,0 +1,16 @@
+package response
+
+// BlockedEnrollmentResponse represents a course assignment rejected because of missing prerequisites
+type BlockedEnrollmentResponse struct {
+	UserID               int                          `json:"user_id"`
+	CourseID             int                          `json:"course_id"`
+	MissingPrerequisites []PrerequisiteCourseResponse `json:"missing_prerequisites"`
+}
+
+// PathAssignmentResponse represents the enrollments created for a trainee from a template path
+type PathAssignmentResponse struct {
+	UserID         int                         `json:"user_id"`
+	AddedCourses   []int                       `json:"added_courses"`
+	BlockedCourses []BlockedEnrollmentResponse `json:"blocked_courses"`
+	Error          string                      `json:"error,omitempty"`
+}
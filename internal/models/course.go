@@ -14,6 +14,8 @@ type Course struct {
 	Duration    int    `pg:",default:0"`
 	CreatedBy   int    `pg:",fk:created_by"`
 
+	NavigationMode string `pg:",default:'sequential'"`
+
 	// User User `pg:"rel:has-one"`
 }
 
@@ -27,5 +29,7 @@ type CourseDetail struct {
 	Duration    int    `pg:",default:0"`
 	CreatedBy   int    `pg:",fk:created_by"`
 
+	NavigationMode string `pg:",default:'sequential'"`
+
 	User User `pg:"rel:has-one"`
 }
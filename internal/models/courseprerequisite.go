@@ -0,0 +1,14 @@
+package models
+
+import (
+	cm "orientation-training-api/internal/common"
+)
+
+// CoursePrerequisite : struct for db table course_prerequisites
+// A trainee must complete PrerequisiteCourseID before taking CourseID
+type CoursePrerequisite struct {
+	cm.BaseModel
+
+	CourseID             int `json:"course_id" pg:"course_id,notnull"`
+	PrerequisiteCourseID int `json:"prerequisite_course_id" pg:"prerequisite_course_id,notnull"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	cm "orientation-training-api/internal/common"
 )
 
@@ -13,4 +15,22 @@ type Module struct {
 	Title    string `json:"title" pg:"title,notnull"`
 	Duration int    `json:"duration" pg:"duration,default:0"`
 	Position int    `json:"position" pg:"position,notnull"`
+
+	// NavigationMode is empty when the module follows its course
+	NavigationMode string `json:"navigation_mode" pg:"navigation_mode,default:null"`
+
+	// Release rule of the module, see the release types in configs
+	ReleaseType       string `json:"release_type" pg:"release_type,default:null"`
+	ReleaseDate       string `json:"release_date" pg:"release_date,default:null"`
+	ReleaseOffsetDays int    `json:"release_offset_days" pg:"release_offset_days,use_zero"`
+}
+
+// ModuleReleaseNotification records that a trainee was told a module opened in a cycle of their enrollment
+type ModuleReleaseNotification struct {
+	cm.BaseModel
+
+	ModuleID   int       `json:"module_id" pg:"module_id,notnull"`
+	UserID     int       `json:"user_id" pg:"user_id,notnull"`
+	Cycle      int       `json:"cycle" pg:"cycle,default:1"`
+	NotifiedAt time.Time `json:"notified_at" pg:"notified_at,default:now()"`
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	cm "orientation-training-api/internal/common"
+)
+
+// ModuleItemCompletion records the activity of a user on a module item during a course cycle
+// TimeSpent is in seconds and accumulates from the heartbeats of the player for lectures,
+// LastPosition is the playback position of the last heartbeat
+type ModuleItemCompletion struct {
+	cm.BaseModel
+
+	UserID       int       `json:"user_id" pg:"user_id,notnull"`
+	ModuleItemID int       `json:"module_item_id" pg:"module_item_id,notnull"`
+	CourseID     int       `json:"course_id" pg:"course_id,notnull"`
+	Cycle        int       `json:"cycle" pg:"cycle,default:1"`
+	Status       string    `json:"status" pg:"status,default:'in_progress'"`
+	StartedAt    time.Time `json:"started_at" pg:"started_at,default:now()"`
+	CompletedAt  time.Time `json:"completed_at" pg:"completed_at,default:null"`
+	LastViewedAt time.Time `json:"last_viewed_at" pg:"last_viewed_at,default:null"`
+	ViewCount    int       `json:"view_count" pg:"view_count,use_zero"`
+	TimeSpent    int       `json:"time_spent" pg:"time_spent,use_zero"`
+	Score        float64   `json:"score" pg:"score,default:null"`
+
+	LastHeartbeatAt   time.Time `json:"last_heartbeat_at" pg:"last_heartbeat_at,default:null"`
+	LastPosition      float64   `json:"last_position" pg:"last_position,use_zero"`
+	PositionChangedAt time.Time `json:"position_changed_at" pg:"position_changed_at,default:null"`
+	FlaggedHeartbeats int       `json:"flagged_heartbeats" pg:"flagged_heartbeats,use_zero"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	cm "orientation-training-api/internal/common"
+)
+
+// Notification is an in-app message sent to a user
+type Notification struct {
+	cm.BaseModel
+
+	UserID  int                    `json:"user_id" pg:"user_id,notnull"`
+	Type    string                 `json:"type" pg:"type,notnull"`
+	Title   string                 `json:"title" pg:"title,notnull"`
+	Message string                 `json:"message" pg:"message"`
+	Data    map[string]interface{} `json:"data" pg:"data"`
+	ReadAt  time.Time              `json:"read_at" pg:"read_at"`
+}
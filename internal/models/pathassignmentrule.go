@@ -0,0 +1,20 @@
+package models
+
+import (
+	cm "orientation-training-api/internal/common"
+)
+
+// PathAssignmentRule assigns a template path to the users matching a department and/or a role
+type PathAssignmentRule struct {
+	cm.BaseModel
+
+	Name           string `json:"name" pg:"name,notnull"`
+	Department     string `json:"department" pg:"department,default:null"`
+	RoleID         int    `json:"role_id" pg:"role_id,default:null"`
+	TemplatePathID int    `json:"template_path_id" pg:"template_path_id,notnull"`
+	DueInDays      int    `json:"due_in_days" pg:"due_in_days,use_zero"`
+	Active         bool   `json:"active" pg:"active,use_zero"`
+	CreatedBy      int    `json:"created_by" pg:"created_by,default:null"`
+
+	TemplatePath *TemplatePath `json:"template_path,omitempty" pg:"rel:has-one,fk:template_path_id"`
+}
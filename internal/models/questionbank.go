@@ -0,0 +1,25 @@
+package models
+
+import (
+	cm "orientation-training-api/internal/common"
+)
+
+// QuestionBank : struct for db table question_banks, a reusable set of questions that quizzes draw from
+type QuestionBank struct {
+	cm.BaseModel
+
+	Name        string `json:"name" pg:"name,notnull"`
+	Description string `json:"description" pg:"description"`
+	CreatedBy   int    `json:"created_by" pg:"created_by"`
+
+	SkillKeywordIDs []int `json:"skill_keyword_ids" pg:"-"`
+	QuestionCount   int   `json:"question_count" pg:"-"`
+}
+
+// QuestionBankSkillKeyword : struct for db table question_bank_skill_keywords
+type QuestionBankSkillKeyword struct {
+	cm.BaseModel
+
+	QuestionBankID int `json:"question_bank_id" pg:"question_bank_id,notnull"`
+	SkillKeywordID int `json:"skill_keyword_id" pg:"skill_keyword_id,notnull"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	cm "orientation-training-api/internal/common"
 )
 
@@ -11,21 +13,53 @@ type Quiz struct {
 	Difficulty int     `json:"difficulty" pg:"difficulty,notnull"`
 	TotalScore float64 `json:"total_score" pg:"total_score,notnull"`
 	TimeLimit  int     `json:"time_limit" pg:"time_limit,notnull"`
+
+	// Attempt policy: MaxAttempts is unlimited at 0, AttemptCooldown is in minutes
+	// and ScorePolicy tells which attempt score counts
+	MaxAttempts     int    `json:"max_attempts" pg:"max_attempts,use_zero"`
+	AttemptCooldown int    `json:"attempt_cooldown" pg:"attempt_cooldown,use_zero"`
+	ScorePolicy     string `json:"score_policy" pg:"score_policy,default:'latest'"`
+
+	// PassMark is a percentage of TotalScore or a score, following PassMarkType
+	PassMark     float64 `json:"pass_mark" pg:"pass_mark,use_zero"`
+	PassMarkType string  `json:"pass_mark_type" pg:"pass_mark_type,default:'percentage'"`
+
+	// ShuffleQuestions and ShuffleOptions reorder the questions and the answer options for each attempt
+	ShuffleQuestions bool `json:"shuffle_questions" pg:"shuffle_questions,use_zero"`
+	ShuffleOptions   bool `json:"shuffle_options" pg:"shuffle_options,use_zero"`
 }
 
+// QuizQuestion is a question of a quiz, or of a question bank when QuestionBankID is set.
+// Difficulty is only set on the questions of a question bank.
+// Editing a question shown in attempts replaces it by a new Version, PreviousVersionID is the one the attempts keep.
 type QuizQuestion struct {
 	cm.BaseModel
 
-	QuizID            int     `json:"quiz_id" pg:"quiz_id,notnull"`
+	QuizID            int     `json:"quiz_id" pg:"quiz_id,default:null"`
+	QuestionBankID    int     `json:"question_bank_id" pg:"question_bank_id,default:null"`
+	Difficulty        int     `json:"difficulty" pg:"difficulty,default:null"`
 	QuestionType      int     `json:"question_type" pg:"question_type,notnull"`
 	QuestionText      string  `json:"question_text" pg:"question_text,notnull"`
 	Explanation       string  `json:"explanation" pg:"explanation"`
 	Weight            float64 `json:"weight" pg:"weight,notnull"`
 	IsMultipleCorrect bool    `json:"is_multiple_correct" pg:"is_multiple_correct,notnull"`
+	ScoringStrategy   string  `json:"scoring_strategy" pg:"scoring_strategy,default:'all_or_nothing'"`
+	Position          int     `json:"position" pg:"position,use_zero"`
+	Version           int     `json:"version" pg:"version,default:1"`
+	PreviousVersionID int     `json:"previous_version_id" pg:"previous_version_id,default:null"`
 
 	// Relationships
 	Answers []QuizAnswer `json:"answers" pg:"rel:has-many"`
 	Quiz    Quiz         `json:"quiz" pg:"rel:belongs-to"`
+
+	// Answer keys of the question types graded without answer options
+	AcceptedAnswers []QuizAcceptedAnswer `json:"accepted_answers,omitempty" pg:"rel:has-many"`
+	MatchPairs      []QuizMatchPair      `json:"match_pairs,omitempty" pg:"rel:has-many"`
+	OrderItems      []QuizOrderItem      `json:"order_items,omitempty" pg:"rel:has-many"`
+	NumericAnswers  []QuizNumericAnswer  `json:"numeric_answers,omitempty" pg:"rel:has-many"`
+
+	// MatchOptions lists the matches of a matching question apart from their prompts, for trainees
+	MatchOptions []QuizMatchPair `json:"match_options,omitempty" pg:"-"`
 }
 
 type QuizAnswer struct {
@@ -34,22 +68,129 @@ type QuizAnswer struct {
 	QuizQuestionID int    `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
 	AnswerText     string `json:"answer_text" pg:"answer_text,notnull"`
 	IsCorrect      bool   `json:"is_correct" pg:"is_correct,notnull"`
+	Position       int    `json:"position" pg:"position,use_zero"`
+}
+
+// QuizAcceptedAnswer is an answer accepted for a short answer question, compared ignoring case unless CaseSensitive.
+// Surrounding whitespace is trimmed and inner whitespace collapsed, or all whitespace removed with IgnoreWhitespace.
+type QuizAcceptedAnswer struct {
+	cm.BaseModel
+
+	QuizQuestionID   int    `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
+	AnswerText       string `json:"answer_text" pg:"answer_text,notnull"`
+	CaseSensitive    bool   `json:"case_sensitive" pg:"case_sensitive,use_zero"`
+	IgnoreWhitespace bool   `json:"ignore_whitespace" pg:"ignore_whitespace,use_zero"`
+}
+
+// QuizMatchPair is a prompt of a matching question with its match, trainees answer with the OptionKey of the match
+// so the match does not give away its prompt
+type QuizMatchPair struct {
+	cm.BaseModel
+
+	QuizQuestionID int    `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
+	PromptText     string `json:"prompt_text" pg:"prompt_text,notnull"`
+	MatchText      string `json:"match_text" pg:"match_text,notnull"`
+	Position       int    `json:"position" pg:"position,notnull"`
+	OptionKey      int    `json:"option_key" pg:"option_key,notnull"`
+}
+
+// QuizOrderItem is an item of an ordering question, Position is its place in the correct order.
+// Trainees answer with the OptionKey of the items, the ids follow the correct order.
+type QuizOrderItem struct {
+	cm.BaseModel
+
+	QuizQuestionID int    `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
+	ItemText       string `json:"item_text" pg:"item_text,notnull"`
+	Position       int    `json:"position" pg:"position,notnull"`
+	OptionKey      int    `json:"option_key" pg:"option_key,notnull"`
+}
+
+// QuizNumericAnswer is a value accepted for a numeric question, give or take Tolerance
+type QuizNumericAnswer struct {
+	cm.BaseModel
+
+	QuizQuestionID int     `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
+	Value          float64 `json:"value" pg:"value,use_zero"`
+	Tolerance      float64 `json:"tolerance" pg:"tolerance,use_zero"`
+}
+
+// MatchAnswer matches the prompt of a pair of a matching question with the match of a pair, MatchID is its option key
+type MatchAnswer struct {
+	PairID  int `json:"pair_id"`
+	MatchID int `json:"match_id"`
+}
+
+// QuizAttempt is an attempt of a user on a quiz, started and closed by the server.
+// DeadlineAt is empty for quizzes without a time limit.
+type QuizAttempt struct {
+	cm.BaseModel
+
+	UserID      int       `json:"user_id" pg:"user_id,notnull"`
+	QuizID      int       `json:"quiz_id" pg:"quiz_id,notnull"`
+	Attempt     int       `json:"attempt" pg:"attempt,notnull"`
+	Status      string    `json:"status" pg:"status,default:'in_progress'"`
+	StartedAt   time.Time `json:"started_at" pg:"started_at,default:now()"`
+	DeadlineAt  time.Time `json:"deadline_at" pg:"deadline_at,default:null"`
+	SubmittedAt time.Time `json:"submitted_at" pg:"submitted_at,default:null"`
+	Score       float64   `json:"score" pg:"score,use_zero"`
+	ArchivedAt  time.Time `json:"archived_at" pg:"archived_at,default:null"`
+
+	// ShuffleQuestions and ShuffleOptions are the shuffle options of the quiz when the attempt started
+	ShuffleQuestions bool `json:"shuffle_questions" pg:"shuffle_questions,use_zero"`
+	ShuffleOptions   bool `json:"shuffle_options" pg:"shuffle_options,use_zero"`
+
+	// Relationships
+	User User `json:"user" pg:"rel:belongs-to"`
+	Quiz Quiz `json:"quiz" pg:"rel:belongs-to"`
+}
+
+// QuizDrawRule draws QuestionCount questions at random from a question bank for each attempt on a quiz,
+// Difficulty 0 draws questions of any difficulty
+type QuizDrawRule struct {
+	cm.BaseModel
+
+	QuizID         int `json:"quiz_id" pg:"quiz_id,notnull"`
+	QuestionBankID int `json:"question_bank_id" pg:"question_bank_id,notnull"`
+	Difficulty     int `json:"difficulty" pg:"difficulty,default:null"`
+	QuestionCount  int `json:"question_count" pg:"question_count,notnull"`
+
+	// Relationships
+	QuestionBank QuestionBank `json:"question_bank" pg:"rel:belongs-to"`
+}
+
+// QuizAttemptQuestion is a question shown in an attempt with the points it is worth in that attempt
+type QuizAttemptQuestion struct {
+	cm.BaseModel
+
+	QuizAttemptID  int     `json:"quiz_attempt_id" pg:"quiz_attempt_id,notnull"`
+	QuizQuestionID int     `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
+	Position       int     `json:"position" pg:"position,notnull"`
+	Points         float64 `json:"points" pg:"points,use_zero"`
+
+	// Relationships
+	QuizQuestion QuizQuestion `json:"quiz_question" pg:"rel:belongs-to"`
 }
 
 type QuizSubmission struct {
 	cm.BaseModel
 
-	UserID            int     `json:"user_id" pg:"user_id,notnull"`
-	QuizID            int     `json:"quiz_id" pg:"quiz_id,notnull"`
-	QuizQuestionID    int     `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
-	AnswerText        string  `json:"answer_text" pg:"answer_text"`
-	SelectedAnswerIds []int   `json:"selected_answer_ids" pg:"selected_answer_ids,array"`
-	Score             float64 `json:"score" pg:"score"`
-	Attempt           int     `json:"attempt" pg:"attempt"`
-	Reviewed          bool    `json:"reviewed" pg:"reviewed,notnull"`
-	Feedback          string  `json:"feedback" pg:"feedback"`
-	SubmittedAt       string  `json:"submitted_at" pg:"submitted_at,notnull"`
-	ReviewedBy        int     `json:"reviewed_by" pg:"reviewed_by"`
+	UserID            int           `json:"user_id" pg:"user_id,notnull"`
+	QuizID            int           `json:"quiz_id" pg:"quiz_id,notnull"`
+	QuizQuestionID    int           `json:"quiz_question_id" pg:"quiz_question_id,notnull"`
+	AnswerText        string        `json:"answer_text" pg:"answer_text"`
+	SelectedAnswerIds []int         `json:"selected_answer_ids" pg:"selected_answer_ids,array"`
+	MatchAnswers      []MatchAnswer `json:"match_answers" pg:"match_answers"`
+	Score             float64       `json:"score" pg:"score"`
+	Attempt           int           `json:"attempt" pg:"attempt"`
+	Reviewed          bool          `json:"reviewed" pg:"reviewed,notnull"`
+	Feedback          string        `json:"feedback" pg:"feedback"`
+	SubmittedAt       string        `json:"submitted_at" pg:"submitted_at,notnull"`
+	ReviewedBy        int           `json:"reviewed_by" pg:"reviewed_by"`
+
+	QuizAttemptID int `json:"quiz_attempt_id" pg:"quiz_attempt_id,default:null"`
+
+	// ArchivedAt is set on attempts archived by a progress reset, they no longer count
+	ArchivedAt time.Time `json:"archived_at" pg:"archived_at,default:null"`
 
 	// Relationships
 	User         User         `json:"user" pg:"rel:belongs-to"`
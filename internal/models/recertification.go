@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	cm "orientation-training-api/internal/common"
+)
+
+// RecertificationPolicy defines how long the completion of a course stays valid
+type RecertificationPolicy struct {
+	cm.BaseModel
+
+	CourseID     int `json:"course_id" pg:"course_id,notnull"`
+	ValidityDays int `json:"validity_days" pg:"validity_days,notnull"`
+	GraceDays    int `json:"grace_days" pg:"grace_days,use_zero"`
+}
+
+// UserProgressHistory keeps the result of a finished enrollment cycle
+type UserProgressHistory struct {
+	cm.BaseModel
+
+	UserID             int                  `json:"user_id" pg:"user_id,notnull"`
+	CourseID           int                  `json:"course_id" pg:"course_id,notnull"`
+	Cycle              int                  `json:"cycle" pg:"cycle,notnull"`
+	AssignedAt         time.Time            `json:"assigned_at" pg:"assigned_at"`
+	DueDate            string               `json:"due_date" pg:"due_date,default:null"`
+	CompletedDate      string               `json:"completed_date" pg:"completed_date,default:null"`
+	CompletedAt        time.Time            `json:"completed_at" pg:"completed_at"`
+	ExpiredAt          time.Time            `json:"expired_at" pg:"expired_at"`
+	PerformanceRating  float64              `json:"performance_rating" pg:"performance_rating,default:null"`
+	PerformanceComment string               `json:"performance_comment" pg:"performance_comment,default:null"`
+	ReviewedBy         int                  `json:"reviewed_by" pg:"reviewed_by,default:null"`
+	QuizScore          float64              `json:"quiz_score" pg:"quiz_score,use_zero"`
+	QuizMaxScore       float64              `json:"quiz_max_score" pg:"quiz_max_score,use_zero"`
+	QuizResults        []QuizResultSnapshot `json:"quiz_results" pg:"quiz_results"`
+
+	// Why the cycle was archived and by which manager, see the history reasons in configs
+	Reason                string `json:"reason" pg:"reason,default:'recertification'"`
+	ActionBy              int    `json:"action_by" pg:"action_by,default:null"`
+	Note                  string `json:"note" pg:"note,default:null"`
+	TransferredToCourseID int    `json:"transferred_to_course_id" pg:"transferred_to_course_id,default:null"`
+}
+
+// QuizResultSnapshot is the score that counted for a quiz, with its latest attempt, when a cycle was archived
+type QuizResultSnapshot struct {
+	QuizID     int     `json:"quiz_id"`
+	Attempt    int     `json:"attempt"`
+	Score      float64 `json:"score"`
+	TotalScore float64 `json:"total_score"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	cm "orientation-training-api/internal/common"
+)
+
+// ScormPackage stores the manifest information of a SCORM module item
+type ScormPackage struct {
+	cm.BaseModel
+
+	ModuleItemID int      `json:"module_item_id" pg:"module_item_id,notnull"`
+	Version      string   `json:"version" pg:"version,notnull"`
+	Identifier   string   `json:"identifier" pg:"identifier"`
+	Title        string   `json:"title" pg:"title"`
+	LaunchPath   string   `json:"launch_path" pg:"launch_path,notnull"`
+	BasePath     string   `json:"base_path" pg:"base_path,notnull"`
+	Files        []string `json:"files" pg:"files,array"`
+}
+
+// ScormRuntime stores the CMI data model of a SCORM module item per user
+type ScormRuntime struct {
+	cm.BaseModel
+
+	UserID           int               `json:"user_id" pg:"user_id,notnull"`
+	ModuleItemID     int               `json:"module_item_id" pg:"module_item_id,notnull"`
+	LessonStatus     string            `json:"lesson_status" pg:"lesson_status"`
+	CompletionStatus string            `json:"completion_status" pg:"completion_status"`
+	SuccessStatus    string            `json:"success_status" pg:"success_status"`
+	ScoreRaw         float64           `json:"score_raw" pg:"score_raw,use_zero"`
+	ScoreMin         float64           `json:"score_min" pg:"score_min,use_zero"`
+	ScoreMax         float64           `json:"score_max" pg:"score_max,use_zero"`
+	ScoreScaled      float64           `json:"score_scaled" pg:"score_scaled,use_zero"`
+	SuspendData      string            `json:"suspend_data" pg:"suspend_data"`
+	LessonLocation   string            `json:"lesson_location" pg:"lesson_location"`
+	TotalTime        int               `json:"total_time" pg:"total_time,use_zero"`
+	SessionTime      int               `json:"session_time" pg:"session_time,use_zero"`
+	CmiData          map[string]string `json:"cmi_data" pg:"cmi_data"`
+	CompletedAt      time.Time         `json:"completed_at" pg:"completed_at"`
+}
@@ -11,6 +11,32 @@ type TemplatePath struct {
 	ID          int    `pg:"id,pk" json:"id"`
 	Name        string `pg:"name,notnull" json:"name"`
 	Description string `pg:"description" json:"description"`
-	CourseIds   []int  `pg:"course_ids,array" json:"course_ids"`
+	CourseIds   []int  `pg:"-" json:"course_ids"`
 	Duration    int    `pg:"duration" json:"duration"`
+
+	Stages      []TemplatePathStage  `pg:"rel:has-many" json:"stages"`
+	PathCourses []TemplatePathCourse `pg:"rel:has-many" json:"-"`
+}
+
+// TemplatePathStage groups courses of a template path, a required stage needs all of its courses
+// while an elective stage needs RequiredCount of them
+type TemplatePathStage struct {
+	cm.BaseModel
+
+	TemplatePathID int    `pg:"template_path_id,notnull" json:"template_path_id"`
+	Name           string `pg:"name,notnull" json:"name"`
+	Position       int    `pg:"position,notnull" json:"position"`
+	StageType      string `pg:"stage_type,notnull" json:"stage_type"`
+	RequiredCount  int    `pg:"required_count,use_zero" json:"required_count"`
+	CourseIds      []int  `pg:"-" json:"course_ids"`
+}
+
+// TemplatePathCourse is a course of a template path at a given position
+type TemplatePathCourse struct {
+	cm.BaseModel
+
+	TemplatePathID int `pg:"template_path_id,notnull" json:"template_path_id"`
+	StageID        int `pg:"stage_id,notnull" json:"stage_id"`
+	CourseID       int `pg:"course_id,notnull" json:"course_id"`
+	Position       int `pg:"position,notnull" json:"position"`
 }
@@ -1,25 +1,39 @@
 package models
 
 import (
+	"time"
+
 	cm "orientation-training-api/internal/common"
 )
 
+// UserProgress is the enrollment of a user in a course. CoursePosition orders the courses of the user by their position
+// in the path or assignment, positions have gaps where courses were blocked by prerequisites or not chosen.
 type UserProgress struct {
 	cm.BaseModel
 
-	UserID             int     `json:"user_id" pg:"user_id,notnull,on_delete:CASCADE"`
-	CourseID           int     `json:"course_id" pg:"course_id,notnull,on_delete:CASCADE"`
-	CoursePosition     int     `json:"course_position" pg:"course_position,notnull"`
-	ModulePosition     int     `json:"module_position" pg:"module_position,notnull"`
-	ModuleItemPosition int     `json:"module_item_position" pg:"module_item_position,notnull"`
-	Completed          bool    `json:"completed" pg:"completed,default:false"`
-	CompletedDate      string  `json:"completed_date" pg:"completed_date,default:null"`
-	PerformanceRating  float64 `json:"performance_rating" pg:"performance_rating,default:null"`
-	PerformanceComment string  `json:"performance_comment" pg:"performance_comment,default:null"`
-	ReviewedBy         int     `json:"reviewed_by" pg:"reviewed_by,default:null"`
+	UserID             int       `json:"user_id" pg:"user_id,notnull,on_delete:CASCADE"`
+	CourseID           int       `json:"course_id" pg:"course_id,notnull,on_delete:CASCADE"`
+	CoursePosition     int       `json:"course_position" pg:"course_position,notnull"`
+	ModulePosition     int       `json:"module_position" pg:"module_position,notnull"`
+	ModuleItemPosition int       `json:"module_item_position" pg:"module_item_position,notnull"`
+	Completed          bool      `json:"completed" pg:"completed,default:false"`
+	CompletedDate      string    `json:"completed_date" pg:"completed_date,default:null"`
+	PerformanceRating  float64   `json:"performance_rating" pg:"performance_rating,default:null"`
+	PerformanceComment string    `json:"performance_comment" pg:"performance_comment,default:null"`
+	ReviewedBy         int       `json:"reviewed_by" pg:"reviewed_by,default:null"`
+	DueDate            string    `json:"due_date" pg:"due_date,default:null"`
+	AssignedBy         int       `json:"assigned_by" pg:"assigned_by,default:null"`
+	AssignedAt         time.Time `json:"assigned_at" pg:"assigned_at,default:now()"`
+	OverdueAt          time.Time `json:"overdue_at" pg:"overdue_at,default:null"`
+	OverdueNotifiedAt  time.Time `json:"-" pg:"overdue_notified_at,default:null"`
+	Cycle              int       `json:"cycle" pg:"cycle,default:1"`
+	CompletedAt        time.Time `json:"completed_at" pg:"completed_at,default:null"`
+	TemplatePathID     int       `json:"template_path_id" pg:"template_path_id,default:null"`
+	ItemStartedAt      time.Time `json:"item_started_at" pg:"item_started_at,default:now()"`
 
 	// Define relationships
-	User     *User   `json:"-" pg:"rel:has-one,fk:user_id"`
-	Course   *Course `json:"-" pg:"rel:has-one,fk:course_id"`
-	Reviewer User    `json:"reviewer" pg:"rel:belongs-to,fk:reviewed_by"`
+	User         *User         `json:"-" pg:"rel:has-one,fk:user_id"`
+	Course       *Course       `json:"-" pg:"rel:has-one,fk:course_id"`
+	TemplatePath *TemplatePath `json:"-" pg:"rel:has-one,fk:template_path_id"`
+	Reviewer     User          `json:"reviewer" pg:"rel:belongs-to,fk:reviewed_by"`
 }
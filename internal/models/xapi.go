@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	cm "orientation-training-api/internal/common"
+	"orientation-training-api/internal/platform/xapi"
+)
+
+// XapiStatement is an xAPI statement stored in the built-in LRS
+type XapiStatement struct {
+	cm.BaseModel
+
+	StatementID string          `json:"statement_id" pg:"statement_id,notnull"`
+	UserID      int             `json:"user_id" pg:"user_id"`
+	Verb        string          `json:"verb" pg:"verb,notnull"`
+	ObjectID    string          `json:"object_id" pg:"object_id,notnull"`
+	Statement   *xapi.Statement `json:"statement" pg:"statement,notnull"`
+	Timestamp   time.Time       `json:"timestamp" pg:"timestamp,notnull"`
+	ForwardedAt time.Time       `json:"forwarded_at" pg:"forwarded_at"`
+}
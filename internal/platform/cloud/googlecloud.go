@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"path"
 	"strings"
 
 	"cloud.google.com/go/storage"
@@ -91,6 +92,9 @@ func (cloud *GcsStorage) UploadFileToCloud(file string, fileName string, directo
 		contentType = "application/vnd.openxmlformats-officedocument.presentationml.slideshow"
 	} else if strings.HasSuffix(lowerFileName, ".pps") {
 		contentType = "application/vnd.ms-powerpoint.slideshow"
+	} else if webContentType := getWebContentTypeFromFileName(lowerFileName); webContentType != "" {
+		// Asset của gói SCORM (html, js, css, ảnh, media...)
+		contentType = webContentType
 	}
 
 	// Tạo một đối tượng writer với các thuộc tính tùy chỉnh
@@ -209,3 +213,36 @@ func getContentTypeFromFileName(fileName string) string {
 	}
 	return "application/octet-stream"
 }
+
+// getWebContentTypeFromFileName trả về MIME type cho các asset web (dùng cho gói SCORM)
+func getWebContentTypeFromFileName(lowerFileName string) string {
+	webContentTypes := map[string]string{
+		".html":  "text/html; charset=utf-8",
+		".htm":   "text/html; charset=utf-8",
+		".js":    "application/javascript",
+		".css":   "text/css",
+		".json":  "application/json",
+		".xml":   "application/xml",
+		".xsd":   "application/xml",
+		".txt":   "text/plain; charset=utf-8",
+		".png":   "image/png",
+		".jpg":   "image/jpeg",
+		".jpeg":  "image/jpeg",
+		".gif":   "image/gif",
+		".svg":   "image/svg+xml",
+		".ico":   "image/x-icon",
+		".webp":  "image/webp",
+		".mp3":   "audio/mpeg",
+		".wav":   "audio/wav",
+		".mp4":   "video/mp4",
+		".webm":  "video/webm",
+		".woff":  "font/woff",
+		".woff2": "font/woff2",
+		".ttf":   "font/ttf",
+		".otf":   "font/otf",
+		".eot":   "application/vnd.ms-fontobject",
+		".swf":   "application/x-shockwave-flash",
+	}
+
+	return webContentTypes[path.Ext(lowerFileName)]
+}
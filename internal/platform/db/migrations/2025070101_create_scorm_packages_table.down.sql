@@ -0,0 +1 @@
+DROP TABLE IF EXISTS scorm_packages;
\ No newline at end of file
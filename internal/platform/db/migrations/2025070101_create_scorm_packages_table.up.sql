@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS scorm_packages (
+    id SERIAL PRIMARY KEY,
+    module_item_id INT NOT NULL,
+    version VARCHAR(10) NOT NULL,
+    identifier VARCHAR(255),
+    title VARCHAR(255),
+    launch_path TEXT NOT NULL,
+    base_path VARCHAR(255) NOT NULL,
+    files TEXT [],
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL
+);
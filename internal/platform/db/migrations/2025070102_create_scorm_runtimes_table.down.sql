@@ -0,0 +1 @@
+DROP TABLE IF EXISTS scorm_runtimes;
\ No newline at end of file
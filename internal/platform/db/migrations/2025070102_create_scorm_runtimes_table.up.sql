@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS scorm_runtimes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    module_item_id INT NOT NULL,
+    lesson_status VARCHAR(50),
+    completion_status VARCHAR(50),
+    success_status VARCHAR(50),
+    score_raw FLOAT,
+    score_min FLOAT,
+    score_max FLOAT,
+    score_scaled FLOAT,
+    suspend_data TEXT,
+    lesson_location TEXT,
+    total_time INT DEFAULT 0,
+    cmi_data JSONB,
+    completed_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE (user_id, module_item_id)
+);
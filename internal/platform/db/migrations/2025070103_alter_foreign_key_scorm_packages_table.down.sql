@@ -0,0 +1,2 @@
+ALTER TABLE scorm_packages
+DROP CONSTRAINT IF EXISTS fk_scorm_packages_module_item_id;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    scorm_packages
+ADD
+    CONSTRAINT fk_scorm_packages_module_item_id FOREIGN KEY (module_item_id) REFERENCES module_items(id) ON DELETE CASCADE;
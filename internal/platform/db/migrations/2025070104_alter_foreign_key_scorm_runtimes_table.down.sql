@@ -0,0 +1,5 @@
+ALTER TABLE scorm_runtimes
+DROP CONSTRAINT IF EXISTS fk_scorm_runtimes_user_id;
+
+ALTER TABLE scorm_runtimes
+DROP CONSTRAINT IF EXISTS fk_scorm_runtimes_module_item_id;
@@ -0,0 +1,9 @@
+ALTER TABLE
+    scorm_runtimes
+ADD
+    CONSTRAINT fk_scorm_runtimes_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    scorm_runtimes
+ADD
+    CONSTRAINT fk_scorm_runtimes_module_item_id FOREIGN KEY (module_item_id) REFERENCES module_items(id) ON DELETE CASCADE;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS xapi_statements;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS xapi_statements (
+    id SERIAL PRIMARY KEY,
+    statement_id VARCHAR(36) NOT NULL UNIQUE,
+    user_id INT,
+    verb VARCHAR(255) NOT NULL,
+    object_id VARCHAR(255) NOT NULL,
+    statement JSONB NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    forwarded_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_user_id ON xapi_statements (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_verb ON xapi_statements (verb);
+
+CREATE INDEX IF NOT EXISTS idx_xapi_statements_object_id ON xapi_statements (object_id);
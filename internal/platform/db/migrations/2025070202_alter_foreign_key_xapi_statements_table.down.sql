@@ -0,0 +1,2 @@
+ALTER TABLE xapi_statements
+DROP CONSTRAINT IF EXISTS fk_xapi_statements_user_id;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    xapi_statements
+ADD
+    CONSTRAINT fk_xapi_statements_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS course_prerequisites;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS course_prerequisites (
+    id SERIAL PRIMARY KEY,
+    course_id INT NOT NULL,
+    prerequisite_course_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE (course_id, prerequisite_course_id),
+    CHECK (course_id <> prerequisite_course_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE course_prerequisites
+DROP CONSTRAINT IF EXISTS fk_course_prerequisites_course_id;
+
+ALTER TABLE course_prerequisites
+DROP CONSTRAINT IF EXISTS fk_course_prerequisites_prerequisite_course_id;
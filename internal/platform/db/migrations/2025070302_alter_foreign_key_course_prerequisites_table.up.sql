@@ -0,0 +1,9 @@
+ALTER TABLE
+    course_prerequisites
+ADD
+    CONSTRAINT fk_course_prerequisites_course_id FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    course_prerequisites
+ADD
+    CONSTRAINT fk_course_prerequisites_prerequisite_course_id FOREIGN KEY (prerequisite_course_id) REFERENCES courses(id) ON DELETE CASCADE;
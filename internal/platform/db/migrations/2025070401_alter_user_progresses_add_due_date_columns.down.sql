@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_user_progresses_due_date;
+
+ALTER TABLE
+    user_progresses DROP COLUMN IF EXISTS due_date,
+    DROP COLUMN IF EXISTS assigned_by,
+    DROP COLUMN IF EXISTS assigned_at,
+    DROP COLUMN IF EXISTS overdue_at,
+    DROP COLUMN IF EXISTS overdue_notified_at;
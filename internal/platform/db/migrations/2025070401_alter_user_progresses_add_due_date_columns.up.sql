@@ -0,0 +1,17 @@
+ALTER TABLE
+    user_progresses
+ADD
+    COLUMN due_date DATE DEFAULT NULL,
+ADD
+    COLUMN assigned_by INT DEFAULT NULL,
+ADD
+    COLUMN assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+ADD
+    COLUMN overdue_at TIMESTAMP DEFAULT NULL,
+ADD
+    COLUMN overdue_notified_at TIMESTAMP DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_progresses_due_date ON user_progresses (due_date)
+WHERE
+    completed = FALSE
+    AND deleted_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    user_progresses
+ADD
+    CONSTRAINT fk_user_progresses_assigned_by FOREIGN KEY (assigned_by) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+DROP CONSTRAINT IF EXISTS fk_notifications_user_id;
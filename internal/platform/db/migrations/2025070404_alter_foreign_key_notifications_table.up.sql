@@ -0,0 +1,4 @@
+ALTER TABLE
+    notifications
+ADD
+    CONSTRAINT fk_notifications_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+ALTER TABLE
+    user_progresses DROP COLUMN IF EXISTS cycle,
+    DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,13 @@
+ALTER TABLE
+    user_progresses
+ADD
+    COLUMN cycle INT NOT NULL DEFAULT 1,
+ADD
+    COLUMN completed_at TIMESTAMP DEFAULT NULL;
+
+UPDATE
+    user_progresses
+SET
+    completed_at = updated_at
+WHERE
+    completed = TRUE;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS recertification_policies;
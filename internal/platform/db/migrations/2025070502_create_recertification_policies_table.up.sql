@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS recertification_policies (
+    id SERIAL PRIMARY KEY,
+    course_id INT NOT NULL UNIQUE,
+    validity_days INT NOT NULL,
+    grace_days INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CHECK (validity_days > 0),
+    CHECK (grace_days >= 0)
+);
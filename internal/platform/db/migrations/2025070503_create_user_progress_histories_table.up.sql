@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS user_progress_histories (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    cycle INT NOT NULL,
+    assigned_at TIMESTAMP DEFAULT NULL,
+    due_date DATE DEFAULT NULL,
+    completed_date VARCHAR(255) DEFAULT NULL,
+    completed_at TIMESTAMP DEFAULT NULL,
+    expired_at TIMESTAMP DEFAULT NULL,
+    performance_rating FLOAT DEFAULT NULL,
+    performance_comment TEXT DEFAULT NULL,
+    reviewed_by INT DEFAULT NULL,
+    quiz_score FLOAT NOT NULL DEFAULT 0,
+    quiz_max_score FLOAT NOT NULL DEFAULT 0,
+    quiz_results JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE (user_id, course_id, cycle)
+);
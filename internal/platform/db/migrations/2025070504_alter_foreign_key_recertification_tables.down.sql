@@ -0,0 +1,11 @@
+ALTER TABLE recertification_policies
+DROP CONSTRAINT IF EXISTS fk_recertification_policies_course_id;
+
+ALTER TABLE user_progress_histories
+DROP CONSTRAINT IF EXISTS fk_user_progress_histories_user_id;
+
+ALTER TABLE user_progress_histories
+DROP CONSTRAINT IF EXISTS fk_user_progress_histories_course_id;
+
+ALTER TABLE user_progress_histories
+DROP CONSTRAINT IF EXISTS fk_user_progress_histories_reviewed_by;
@@ -0,0 +1,19 @@
+ALTER TABLE
+    recertification_policies
+ADD
+    CONSTRAINT fk_recertification_policies_course_id FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    user_progress_histories
+ADD
+    CONSTRAINT fk_user_progress_histories_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    user_progress_histories
+ADD
+    CONSTRAINT fk_user_progress_histories_course_id FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    user_progress_histories
+ADD
+    CONSTRAINT fk_user_progress_histories_reviewed_by FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL;
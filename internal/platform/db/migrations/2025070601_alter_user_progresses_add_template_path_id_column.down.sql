@@ -0,0 +1,2 @@
+ALTER TABLE
+    user_progresses DROP COLUMN IF EXISTS template_path_id;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    user_progresses
+ADD
+    COLUMN template_path_id INT DEFAULT NULL;
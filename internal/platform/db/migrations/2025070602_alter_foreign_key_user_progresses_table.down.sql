@@ -0,0 +1,2 @@
+ALTER TABLE user_progresses
+DROP CONSTRAINT IF EXISTS fk_user_progresses_template_path_id;
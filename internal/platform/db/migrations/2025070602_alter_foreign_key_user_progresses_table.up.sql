@@ -0,0 +1,4 @@
+ALTER TABLE
+    user_progresses
+ADD
+    CONSTRAINT fk_user_progresses_template_path_id FOREIGN KEY (template_path_id) REFERENCES template_paths(id) ON DELETE SET NULL;
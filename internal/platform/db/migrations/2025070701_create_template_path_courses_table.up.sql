@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS template_path_courses (
+    id SERIAL PRIMARY KEY,
+    template_path_id INT NOT NULL,
+    course_id INT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    UNIQUE (template_path_id, course_id),
+    CONSTRAINT uq_template_path_courses_position UNIQUE (template_path_id, position) DEFERRABLE INITIALLY DEFERRED,
+    CHECK (position > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_template_path_courses_course_id ON template_path_courses (course_id);
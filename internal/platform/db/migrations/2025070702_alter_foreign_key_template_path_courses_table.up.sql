@@ -0,0 +1,9 @@
+ALTER TABLE
+    template_path_courses
+ADD
+    CONSTRAINT fk_template_path_courses_template_path_id FOREIGN KEY (template_path_id) REFERENCES template_paths(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    template_path_courses
+ADD
+    CONSTRAINT fk_template_path_courses_course_id FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE;
@@ -0,0 +1,27 @@
+ALTER TABLE
+    template_paths
+ADD
+    COLUMN IF NOT EXISTS course_ids INT [] NOT NULL DEFAULT '{}';
+
+UPDATE
+    template_paths AS tp
+SET
+    course_ids = COALESCE(
+        (
+            SELECT
+                array_agg(
+                    tpc.course_id
+                    ORDER BY
+                        tpc.position
+                )
+            FROM
+                template_path_courses AS tpc
+            WHERE
+                tpc.template_path_id = tp.id
+                AND tpc.deleted_at IS NULL
+        ),
+        '{}'
+    );
+
+DELETE FROM
+    template_path_courses;
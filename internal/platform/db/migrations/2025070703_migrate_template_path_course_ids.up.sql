@@ -0,0 +1,31 @@
+-- Copy the course_ids arrays into template_path_courses, keeping the first
+-- occurrence of each course and skipping courses that no longer exist
+INSERT INTO
+    template_path_courses (template_path_id, course_id, position)
+SELECT
+    template_path_id,
+    course_id,
+    ROW_NUMBER() OVER (
+        PARTITION BY template_path_id
+        ORDER BY
+            ordinality
+    )
+FROM
+    (
+        SELECT
+            DISTINCT ON (tp.id, item.course_id) tp.id AS template_path_id,
+            item.course_id,
+            item.ordinality
+        FROM
+            template_paths AS tp
+            CROSS JOIN LATERAL unnest(tp.course_ids) WITH ORDINALITY AS item(course_id, ordinality)
+            JOIN courses AS c ON c.id = item.course_id
+            AND c.deleted_at IS NULL
+        ORDER BY
+            tp.id,
+            item.course_id,
+            item.ordinality
+    ) AS path_courses;
+
+ALTER TABLE
+    template_paths DROP COLUMN IF EXISTS course_ids;
@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS trg_update_template_path_duration_after_upsert ON template_path_courses;
+DROP TRIGGER IF EXISTS trg_update_template_path_duration_after_delete ON template_path_courses;
+DROP TRIGGER IF EXISTS trg_sync_template_paths_after_course_update ON courses;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS update_template_path_duration;
+DROP FUNCTION IF EXISTS sync_template_paths_with_course;
@@ -0,0 +1,82 @@
+-- Function: update_template_path_duration
+CREATE OR REPLACE FUNCTION update_template_path_duration()
+RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE template_paths
+  SET duration = (
+    SELECT COALESCE(SUM(c.duration), 0)
+    FROM template_path_courses AS tpc
+    JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+    WHERE tpc.template_path_id = COALESCE(NEW.template_path_id, OLD.template_path_id)
+      AND tpc.deleted_at IS NULL
+  )
+  WHERE id = COALESCE(NEW.template_path_id, OLD.template_path_id);
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Triggers: Insert / Update / Delete (template_path_courses)
+CREATE TRIGGER trg_update_template_path_duration_after_upsert
+AFTER INSERT OR UPDATE OF course_id, template_path_id, deleted_at
+ON template_path_courses
+FOR EACH ROW
+EXECUTE FUNCTION update_template_path_duration();
+
+CREATE TRIGGER trg_update_template_path_duration_after_delete
+AFTER DELETE
+ON template_path_courses
+FOR EACH ROW
+EXECUTE FUNCTION update_template_path_duration();
+
+-- Function: sync_template_paths_with_course
+-- Keeps the duration of the paths in sync with their courses and removes
+-- soft deleted courses from the paths, renumbering the remaining positions
+CREATE OR REPLACE FUNCTION sync_template_paths_with_course()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL THEN
+    DELETE FROM template_path_courses WHERE course_id = NEW.id;
+
+    UPDATE template_path_courses AS tpc
+    SET position = ordered.new_position
+    FROM (
+      SELECT id, ROW_NUMBER() OVER (PARTITION BY template_path_id ORDER BY position) AS new_position
+      FROM template_path_courses
+    ) AS ordered
+    WHERE tpc.id = ordered.id
+      AND tpc.position <> ordered.new_position;
+  ELSE
+    UPDATE template_paths AS tp
+    SET duration = (
+      SELECT COALESCE(SUM(c.duration), 0)
+      FROM template_path_courses AS tpc
+      JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+      WHERE tpc.template_path_id = tp.id
+        AND tpc.deleted_at IS NULL
+    )
+    WHERE tp.id IN (
+      SELECT template_path_id FROM template_path_courses WHERE course_id = NEW.id
+    );
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger: duration change or soft delete (courses)
+CREATE TRIGGER trg_sync_template_paths_after_course_update
+AFTER UPDATE OF duration, deleted_at
+ON courses
+FOR EACH ROW
+EXECUTE FUNCTION sync_template_paths_with_course();
+
+-- Recompute the durations of existing paths
+UPDATE template_paths AS tp
+SET duration = (
+  SELECT COALESCE(SUM(c.duration), 0)
+  FROM template_path_courses AS tpc
+  JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+  WHERE tpc.template_path_id = tp.id
+    AND tpc.deleted_at IS NULL
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS path_assignment_rules;
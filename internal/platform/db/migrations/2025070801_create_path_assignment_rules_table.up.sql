@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS path_assignment_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    department VARCHAR(255) DEFAULT NULL,
+    role_id INT DEFAULT NULL,
+    template_path_id INT NOT NULL,
+    due_in_days INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INT DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CHECK (due_in_days >= 0),
+    CHECK (department IS NOT NULL OR role_id IS NOT NULL)
+);
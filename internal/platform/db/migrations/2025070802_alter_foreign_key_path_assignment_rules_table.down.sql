@@ -0,0 +1,8 @@
+ALTER TABLE path_assignment_rules
+DROP CONSTRAINT IF EXISTS fk_path_assignment_rules_template_path_id;
+
+ALTER TABLE path_assignment_rules
+DROP CONSTRAINT IF EXISTS fk_path_assignment_rules_role_id;
+
+ALTER TABLE path_assignment_rules
+DROP CONSTRAINT IF EXISTS fk_path_assignment_rules_created_by;
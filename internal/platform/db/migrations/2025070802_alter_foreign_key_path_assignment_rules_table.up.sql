@@ -0,0 +1,14 @@
+ALTER TABLE
+    path_assignment_rules
+ADD
+    CONSTRAINT fk_path_assignment_rules_template_path_id FOREIGN KEY (template_path_id) REFERENCES template_paths(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    path_assignment_rules
+ADD
+    CONSTRAINT fk_path_assignment_rules_role_id FOREIGN KEY (role_id) REFERENCES user_roles(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    path_assignment_rules
+ADD
+    CONSTRAINT fk_path_assignment_rules_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_template_path_courses_stage_id;
+
+ALTER TABLE
+    template_path_courses DROP COLUMN IF EXISTS stage_id;
+
+DROP TABLE IF EXISTS template_path_stages;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS template_path_stages (
+    id SERIAL PRIMARY KEY,
+    template_path_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    position INT NOT NULL,
+    stage_type VARCHAR(20) NOT NULL DEFAULT 'required',
+    required_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT uq_template_path_stages_position UNIQUE (template_path_id, position) DEFERRABLE INITIALLY DEFERRED,
+    CHECK (position > 0),
+    CHECK (stage_type IN ('required', 'elective')),
+    CHECK (
+        stage_type = 'required'
+        OR required_count > 0
+    )
+);
+
+ALTER TABLE
+    template_path_courses
+ADD
+    COLUMN IF NOT EXISTS stage_id INT;
+
+CREATE INDEX IF NOT EXISTS idx_template_path_courses_stage_id ON template_path_courses (stage_id);
+
+-- Existing paths become a single required stage holding all their courses
+INSERT INTO
+    template_path_stages (template_path_id, name, position, stage_type)
+SELECT
+    DISTINCT template_path_id,
+    'Stage 1',
+    1,
+    'required'
+FROM
+    template_path_courses;
+
+UPDATE
+    template_path_courses AS tpc
+SET
+    stage_id = tps.id
+FROM
+    template_path_stages AS tps
+WHERE
+    tps.template_path_id = tpc.template_path_id;
+
+ALTER TABLE
+    template_path_courses
+ALTER COLUMN
+    stage_id
+SET
+    NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE
+    template_path_courses DROP CONSTRAINT IF EXISTS fk_template_path_courses_stage_id;
+
+ALTER TABLE
+    template_path_stages DROP CONSTRAINT IF EXISTS fk_template_path_stages_template_path_id;
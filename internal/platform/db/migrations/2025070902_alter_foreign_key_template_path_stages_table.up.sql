@@ -0,0 +1,9 @@
+ALTER TABLE
+    template_path_stages
+ADD
+    CONSTRAINT fk_template_path_stages_template_path_id FOREIGN KEY (template_path_id) REFERENCES template_paths(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    template_path_courses
+ADD
+    CONSTRAINT fk_template_path_courses_stage_id FOREIGN KEY (stage_id) REFERENCES template_path_stages(id) ON DELETE CASCADE;
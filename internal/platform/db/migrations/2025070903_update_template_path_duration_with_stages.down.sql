@@ -0,0 +1,61 @@
+-- Function: update_template_path_duration
+CREATE OR REPLACE FUNCTION update_template_path_duration()
+RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE template_paths
+  SET duration = (
+    SELECT COALESCE(SUM(c.duration), 0)
+    FROM template_path_courses AS tpc
+    JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+    WHERE tpc.template_path_id = COALESCE(NEW.template_path_id, OLD.template_path_id)
+      AND tpc.deleted_at IS NULL
+  )
+  WHERE id = COALESCE(NEW.template_path_id, OLD.template_path_id);
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: sync_template_paths_with_course
+CREATE OR REPLACE FUNCTION sync_template_paths_with_course()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL THEN
+    DELETE FROM template_path_courses WHERE course_id = NEW.id;
+
+    UPDATE template_path_courses AS tpc
+    SET position = ordered.new_position
+    FROM (
+      SELECT id, ROW_NUMBER() OVER (PARTITION BY template_path_id ORDER BY position) AS new_position
+      FROM template_path_courses
+    ) AS ordered
+    WHERE tpc.id = ordered.id
+      AND tpc.position <> ordered.new_position;
+  ELSE
+    UPDATE template_paths AS tp
+    SET duration = (
+      SELECT COALESCE(SUM(c.duration), 0)
+      FROM template_path_courses AS tpc
+      JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+      WHERE tpc.template_path_id = tp.id
+        AND tpc.deleted_at IS NULL
+    )
+    WHERE tp.id IN (
+      SELECT template_path_id FROM template_path_courses WHERE course_id = NEW.id
+    );
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS calculate_template_path_duration;
+
+UPDATE template_paths AS tp
+SET duration = (
+  SELECT COALESCE(SUM(c.duration), 0)
+  FROM template_path_courses AS tpc
+  JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+  WHERE tpc.template_path_id = tp.id
+    AND tpc.deleted_at IS NULL
+);
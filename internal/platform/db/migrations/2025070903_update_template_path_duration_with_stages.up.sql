@@ -0,0 +1,64 @@
+-- Function: calculate_template_path_duration
+-- Sum of the required stage courses plus, for each elective stage,
+-- the shortest courses that satisfy its required_count
+CREATE OR REPLACE FUNCTION calculate_template_path_duration(path_id INT)
+RETURNS INT AS $$
+  SELECT COALESCE(SUM(ranked.duration), 0)::INT
+  FROM (
+    SELECT
+      c.duration,
+      tps.stage_type,
+      tps.required_count,
+      ROW_NUMBER() OVER (PARTITION BY tps.id ORDER BY c.duration ASC, tpc.position ASC) AS duration_rank
+    FROM template_path_courses AS tpc
+    JOIN template_path_stages AS tps ON tps.id = tpc.stage_id AND tps.deleted_at IS NULL
+    JOIN courses AS c ON c.id = tpc.course_id AND c.deleted_at IS NULL
+    WHERE tpc.template_path_id = path_id
+      AND tpc.deleted_at IS NULL
+  ) AS ranked
+  WHERE ranked.stage_type = 'required'
+    OR ranked.duration_rank <= ranked.required_count;
+$$ LANGUAGE sql STABLE;
+
+-- Function: update_template_path_duration
+CREATE OR REPLACE FUNCTION update_template_path_duration()
+RETURNS TRIGGER AS $$
+BEGIN
+  UPDATE template_paths
+  SET duration = calculate_template_path_duration(id)
+  WHERE id = COALESCE(NEW.template_path_id, OLD.template_path_id);
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: sync_template_paths_with_course
+CREATE OR REPLACE FUNCTION sync_template_paths_with_course()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL THEN
+    DELETE FROM template_path_courses WHERE course_id = NEW.id;
+
+    UPDATE template_path_courses AS tpc
+    SET position = ordered.new_position
+    FROM (
+      SELECT id, ROW_NUMBER() OVER (PARTITION BY template_path_id ORDER BY position) AS new_position
+      FROM template_path_courses
+    ) AS ordered
+    WHERE tpc.id = ordered.id
+      AND tpc.position <> ordered.new_position;
+  ELSE
+    UPDATE template_paths AS tp
+    SET duration = calculate_template_path_duration(tp.id)
+    WHERE tp.id IN (
+      SELECT template_path_id FROM template_path_courses WHERE course_id = NEW.id
+    );
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Recompute the durations of existing paths
+UPDATE template_paths
+SET duration = calculate_template_path_duration(id);
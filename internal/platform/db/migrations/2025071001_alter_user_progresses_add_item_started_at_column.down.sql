@@ -0,0 +1,2 @@
+ALTER TABLE
+    user_progresses DROP COLUMN IF EXISTS item_started_at;
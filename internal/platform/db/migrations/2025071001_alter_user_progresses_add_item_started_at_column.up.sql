@@ -0,0 +1,9 @@
+ALTER TABLE
+    user_progresses
+ADD
+    COLUMN item_started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE
+    user_progresses
+SET
+    item_started_at = updated_at;
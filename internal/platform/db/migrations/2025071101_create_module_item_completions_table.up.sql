@@ -0,0 +1,77 @@
+CREATE TABLE IF NOT EXISTS module_item_completions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    module_item_id INT NOT NULL,
+    course_id INT NOT NULL,
+    cycle INT NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP DEFAULT NULL,
+    last_viewed_at TIMESTAMP DEFAULT NULL,
+    view_count INT NOT NULL DEFAULT 0,
+    time_spent INT NOT NULL DEFAULT 0,
+    score NUMERIC(10, 2) DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT uq_module_item_completions_user_item_cycle UNIQUE (user_id, module_item_id, cycle),
+    CHECK (status IN ('in_progress', 'completed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_module_item_completions_user_course ON module_item_completions (user_id, course_id, cycle);
+
+-- Items before the cursor of a progress are completed, the item under the cursor is in progress
+INSERT INTO
+    module_item_completions (
+        user_id,
+        module_item_id,
+        course_id,
+        cycle,
+        status,
+        started_at,
+        completed_at
+    )
+SELECT
+    up.user_id,
+    mi.id,
+    up.course_id,
+    up.cycle,
+    CASE
+        WHEN up.completed
+        OR md.position < up.module_position
+        OR (
+            md.position = up.module_position
+            AND mi.position < up.module_item_position
+        ) THEN 'completed'
+        ELSE 'in_progress'
+    END,
+    CASE
+        WHEN md.position = up.module_position
+        AND mi.position = up.module_item_position THEN up.item_started_at
+        ELSE up.updated_at
+    END,
+    CASE
+        WHEN up.completed
+        OR md.position < up.module_position
+        OR (
+            md.position = up.module_position
+            AND mi.position < up.module_item_position
+        ) THEN COALESCE(up.completed_at, up.updated_at)
+        ELSE NULL
+    END
+FROM
+    user_progresses AS up
+    JOIN modules AS md ON md.course_id = up.course_id
+    AND md.deleted_at IS NULL
+    JOIN module_items AS mi ON mi.module_id = md.id
+    AND mi.deleted_at IS NULL
+WHERE
+    up.deleted_at IS NULL
+    AND (
+        up.completed
+        OR md.position < up.module_position
+        OR (
+            md.position = up.module_position
+            AND mi.position <= up.module_item_position
+        )
+    );
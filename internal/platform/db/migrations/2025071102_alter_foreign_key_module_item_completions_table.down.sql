@@ -0,0 +1,8 @@
+ALTER TABLE
+    module_item_completions DROP CONSTRAINT IF EXISTS fk_module_item_completions_course_id;
+
+ALTER TABLE
+    module_item_completions DROP CONSTRAINT IF EXISTS fk_module_item_completions_module_item_id;
+
+ALTER TABLE
+    module_item_completions DROP CONSTRAINT IF EXISTS fk_module_item_completions_user_id;
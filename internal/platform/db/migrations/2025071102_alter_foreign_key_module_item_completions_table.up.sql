@@ -0,0 +1,14 @@
+ALTER TABLE
+    module_item_completions
+ADD
+    CONSTRAINT fk_module_item_completions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    module_item_completions
+ADD
+    CONSTRAINT fk_module_item_completions_module_item_id FOREIGN KEY (module_item_id) REFERENCES module_items(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    module_item_completions
+ADD
+    CONSTRAINT fk_module_item_completions_course_id FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    module_item_completions DROP COLUMN IF EXISTS last_heartbeat_at,
+    DROP COLUMN IF EXISTS last_position,
+    DROP COLUMN IF EXISTS flagged_heartbeats;
@@ -0,0 +1,8 @@
+ALTER TABLE
+    module_item_completions
+ADD
+    COLUMN last_heartbeat_at TIMESTAMP,
+ADD
+    COLUMN last_position NUMERIC(10, 2) NOT NULL DEFAULT 0,
+ADD
+    COLUMN flagged_heartbeats INTEGER NOT NULL DEFAULT 0;
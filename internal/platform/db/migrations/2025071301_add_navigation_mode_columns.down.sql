@@ -0,0 +1,5 @@
+ALTER TABLE
+    modules DROP COLUMN IF EXISTS navigation_mode;
+
+ALTER TABLE
+    courses DROP COLUMN IF EXISTS navigation_mode;
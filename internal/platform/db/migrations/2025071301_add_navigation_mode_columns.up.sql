@@ -0,0 +1,15 @@
+ALTER TABLE
+    courses
+ADD
+    COLUMN navigation_mode VARCHAR(30) NOT NULL DEFAULT 'sequential' CHECK (
+        navigation_mode IN ('sequential', 'free', 'sequential_by_module')
+    );
+
+-- A module without navigation mode follows its course
+ALTER TABLE
+    modules
+ADD
+    COLUMN navigation_mode VARCHAR(30) CHECK (
+        navigation_mode IS NULL
+        OR navigation_mode IN ('sequential', 'free')
+    );
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS module_release_notifications;
+
+ALTER TABLE
+    modules DROP COLUMN IF EXISTS release_type,
+    DROP COLUMN IF EXISTS release_date,
+    DROP COLUMN IF EXISTS release_offset_days;
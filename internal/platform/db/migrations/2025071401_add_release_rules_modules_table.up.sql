@@ -0,0 +1,26 @@
+ALTER TABLE
+    modules
+ADD
+    COLUMN release_type VARCHAR(30) CHECK (
+        release_type IS NULL
+        OR release_type IN (
+            'absolute_date',
+            'after_enrollment',
+            'after_joined_date'
+        )
+    ),
+ADD
+    COLUMN release_date DATE,
+ADD
+    COLUMN release_offset_days INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE module_release_notifications (
+    id SERIAL PRIMARY KEY,
+    module_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    UNIQUE (module_id, user_id)
+);
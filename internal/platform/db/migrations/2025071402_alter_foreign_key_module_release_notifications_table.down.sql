@@ -0,0 +1,5 @@
+ALTER TABLE
+    module_release_notifications DROP CONSTRAINT IF EXISTS fk_module_release_notifications_user_id;
+
+ALTER TABLE
+    module_release_notifications DROP CONSTRAINT IF EXISTS fk_module_release_notifications_module_id;
@@ -0,0 +1,9 @@
+ALTER TABLE
+    module_release_notifications
+ADD
+    CONSTRAINT fk_module_release_notifications_module_id FOREIGN KEY (module_id) REFERENCES modules(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    module_release_notifications
+ADD
+    CONSTRAINT fk_module_release_notifications_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
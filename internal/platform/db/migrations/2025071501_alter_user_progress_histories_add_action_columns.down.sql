@@ -0,0 +1,20 @@
+ALTER TABLE
+    quiz_submissions DROP COLUMN IF EXISTS archived_at;
+
+DROP INDEX IF EXISTS idx_user_progress_histories_user_course;
+
+DELETE FROM
+    user_progress_histories
+WHERE
+    reason <> 'recertification';
+
+ALTER TABLE
+    user_progress_histories DROP COLUMN IF EXISTS reason,
+    DROP COLUMN IF EXISTS action_by,
+    DROP COLUMN IF EXISTS note,
+    DROP COLUMN IF EXISTS transferred_to_course_id;
+
+ALTER TABLE
+    user_progress_histories
+ADD
+    CONSTRAINT user_progress_histories_user_id_course_id_cycle_key UNIQUE (user_id, course_id, cycle);
@@ -0,0 +1,29 @@
+-- A cycle can now be archived several times, by a reset, a reopening or an unassignment
+ALTER TABLE
+    user_progress_histories DROP CONSTRAINT IF EXISTS user_progress_histories_user_id_course_id_cycle_key;
+
+ALTER TABLE
+    user_progress_histories
+ADD
+    COLUMN reason VARCHAR(30) NOT NULL DEFAULT 'recertification' CHECK (
+        reason IN (
+            'recertification',
+            'reset',
+            'reopen',
+            'unassign',
+            'transfer'
+        )
+    ),
+ADD
+    COLUMN action_by INTEGER,
+ADD
+    COLUMN note TEXT,
+ADD
+    COLUMN transferred_to_course_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_user_progress_histories_user_course ON user_progress_histories (user_id, course_id, cycle);
+
+ALTER TABLE
+    quiz_submissions
+ADD
+    COLUMN archived_at TIMESTAMP;
@@ -0,0 +1,4 @@
+ALTER TABLE
+    quiz_submissions DROP COLUMN IF EXISTS quiz_attempt_id;
+
+DROP TABLE IF EXISTS quiz_attempts;
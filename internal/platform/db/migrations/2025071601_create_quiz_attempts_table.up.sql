@@ -0,0 +1,70 @@
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    quiz_id INT NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deadline_at TIMESTAMP DEFAULT NULL,
+    submitted_at TIMESTAMP DEFAULT NULL,
+    score NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT uq_quiz_attempts_user_quiz_attempt UNIQUE (user_id, quiz_id, attempt),
+    CHECK (status IN ('in_progress', 'submitted', 'expired'))
+);
+
+-- A user has at most one open attempt on a quiz
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quiz_attempts_open ON quiz_attempts (user_id, quiz_id)
+WHERE
+    status = 'in_progress'
+    AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_deadline ON quiz_attempts (deadline_at)
+WHERE
+    status = 'in_progress';
+
+ALTER TABLE
+    quiz_submissions
+ADD
+    COLUMN IF NOT EXISTS quiz_attempt_id INT DEFAULT NULL;
+
+-- Attempts submitted before the start/submit lifecycle are kept as submitted attempts
+INSERT INTO
+    quiz_attempts (
+        user_id,
+        quiz_id,
+        attempt,
+        status,
+        started_at,
+        submitted_at,
+        score
+    )
+SELECT
+    user_id,
+    quiz_id,
+    attempt,
+    'submitted',
+    MIN(created_at),
+    MIN(created_at),
+    COALESCE(SUM(score), 0)
+FROM
+    quiz_submissions
+WHERE
+    deleted_at IS NULL
+GROUP BY
+    user_id,
+    quiz_id,
+    attempt ON CONFLICT DO NOTHING;
+
+UPDATE
+    quiz_submissions AS qs
+SET
+    quiz_attempt_id = qa.id
+FROM
+    quiz_attempts AS qa
+WHERE
+    qa.user_id = qs.user_id
+    AND qa.quiz_id = qs.quiz_id
+    AND qa.attempt = qs.attempt;
@@ -0,0 +1,8 @@
+ALTER TABLE
+    quiz_submissions DROP CONSTRAINT IF EXISTS fk_quiz_submissions_quiz_attempt_id;
+
+ALTER TABLE
+    quiz_attempts DROP CONSTRAINT IF EXISTS fk_quiz_attempts_quiz_id;
+
+ALTER TABLE
+    quiz_attempts DROP CONSTRAINT IF EXISTS fk_quiz_attempts_user_id;
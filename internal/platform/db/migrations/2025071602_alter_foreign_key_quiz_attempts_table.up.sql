@@ -0,0 +1,14 @@
+ALTER TABLE
+    quiz_attempts
+ADD
+    CONSTRAINT fk_quiz_attempts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_attempts
+ADD
+    CONSTRAINT fk_quiz_attempts_quiz_id FOREIGN KEY (quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_submissions
+ADD
+    CONSTRAINT fk_quiz_submissions_quiz_attempt_id FOREIGN KEY (quiz_attempt_id) REFERENCES quiz_attempts(id) ON DELETE CASCADE;
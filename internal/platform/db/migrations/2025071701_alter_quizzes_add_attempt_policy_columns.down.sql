@@ -0,0 +1,8 @@
+ALTER TABLE
+    quiz_attempts DROP COLUMN IF EXISTS archived_at;
+
+ALTER TABLE
+    quizzes DROP CONSTRAINT IF EXISTS chk_quizzes_score_policy,
+    DROP COLUMN IF EXISTS score_policy,
+    DROP COLUMN IF EXISTS attempt_cooldown,
+    DROP COLUMN IF EXISTS max_attempts;
@@ -0,0 +1,26 @@
+ALTER TABLE
+    quizzes
+ADD
+    COLUMN IF NOT EXISTS max_attempts INT NOT NULL DEFAULT 0,
+ADD
+    COLUMN IF NOT EXISTS attempt_cooldown INT NOT NULL DEFAULT 0,
+ADD
+    COLUMN IF NOT EXISTS score_policy VARCHAR(20) NOT NULL DEFAULT 'latest',
+ADD
+    CONSTRAINT chk_quizzes_score_policy CHECK (score_policy IN ('highest', 'latest', 'average'));
+
+ALTER TABLE
+    quiz_attempts
+ADD
+    COLUMN IF NOT EXISTS archived_at TIMESTAMP DEFAULT NULL;
+
+-- Attempts whose answers were archived by a progress reset no longer count either
+UPDATE
+    quiz_attempts AS qa
+SET
+    archived_at = qs.archived_at
+FROM
+    quiz_submissions AS qs
+WHERE
+    qs.quiz_attempt_id = qa.id
+    AND qs.archived_at IS NOT NULL;
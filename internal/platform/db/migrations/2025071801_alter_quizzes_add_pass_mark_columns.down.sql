@@ -0,0 +1,4 @@
+ALTER TABLE
+    quizzes DROP CONSTRAINT IF EXISTS chk_quizzes_pass_mark_type,
+    DROP COLUMN IF EXISTS pass_mark_type,
+    DROP COLUMN IF EXISTS pass_mark;
@@ -0,0 +1,8 @@
+ALTER TABLE
+    quizzes
+ADD
+    COLUMN IF NOT EXISTS pass_mark NUMERIC(10, 2) NOT NULL DEFAULT 70,
+ADD
+    COLUMN IF NOT EXISTS pass_mark_type VARCHAR(20) NOT NULL DEFAULT 'percentage',
+ADD
+    CONSTRAINT chk_quizzes_pass_mark_type CHECK (pass_mark_type IN ('percentage', 'score'));
@@ -0,0 +1,23 @@
+DROP TABLE IF EXISTS quiz_attempt_questions;
+
+DROP TABLE IF EXISTS quiz_draw_rules;
+
+DROP INDEX IF EXISTS idx_quiz_questions_question_bank;
+
+DELETE FROM
+    quiz_questions
+WHERE
+    quiz_id IS NULL;
+
+ALTER TABLE
+    quiz_questions DROP CONSTRAINT IF EXISTS chk_quiz_questions_owner,
+    DROP COLUMN IF EXISTS difficulty,
+    DROP COLUMN IF EXISTS question_bank_id,
+ALTER COLUMN
+    quiz_id
+SET
+    NOT NULL;
+
+DROP TABLE IF EXISTS question_bank_skill_keywords;
+
+DROP TABLE IF EXISTS question_banks;
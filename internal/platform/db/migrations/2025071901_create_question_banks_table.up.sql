@@ -0,0 +1,84 @@
+CREATE TABLE IF NOT EXISTS question_banks (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT DEFAULT NULL,
+    created_by INT DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS question_bank_skill_keywords (
+    id SERIAL PRIMARY KEY,
+    question_bank_id INT NOT NULL,
+    skill_keyword_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT uq_question_bank_skill_keywords UNIQUE (question_bank_id, skill_keyword_id)
+);
+
+-- A question belongs to a quiz or to a question bank
+ALTER TABLE
+    quiz_questions
+ALTER COLUMN
+    quiz_id DROP NOT NULL,
+ADD
+    COLUMN IF NOT EXISTS question_bank_id INT DEFAULT NULL,
+ADD
+    COLUMN IF NOT EXISTS difficulty INT DEFAULT NULL,
+ADD
+    CONSTRAINT chk_quiz_questions_owner CHECK (
+        quiz_id IS NOT NULL
+        OR question_bank_id IS NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_question_bank ON quiz_questions (question_bank_id, difficulty);
+
+-- difficulty NULL draws questions of any difficulty
+CREATE TABLE IF NOT EXISTS quiz_draw_rules (
+    id SERIAL PRIMARY KEY,
+    quiz_id INT NOT NULL,
+    question_bank_id INT NOT NULL,
+    difficulty INT DEFAULT NULL,
+    question_count INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CHECK (question_count > 0)
+);
+
+CREATE TABLE IF NOT EXISTS quiz_attempt_questions (
+    id SERIAL PRIMARY KEY,
+    quiz_attempt_id INT NOT NULL,
+    quiz_question_id INT NOT NULL,
+    position INT NOT NULL,
+    points NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CONSTRAINT uq_quiz_attempt_questions UNIQUE (quiz_attempt_id, quiz_question_id)
+);
+
+-- Attempts started before question banks were shown the questions of their quiz
+INSERT INTO
+    quiz_attempt_questions (
+        quiz_attempt_id,
+        quiz_question_id,
+        position,
+        points
+    )
+SELECT
+    qa.id,
+    qq.id,
+    ROW_NUMBER() OVER (
+        PARTITION BY qa.id
+        ORDER BY
+            qq.id
+    ),
+    qq.weight * q.total_score
+FROM
+    quiz_attempts AS qa
+    JOIN quizzes AS q ON q.id = qa.quiz_id
+    JOIN quiz_questions AS qq ON qq.quiz_id = qa.quiz_id
+    AND qq.deleted_at IS NULL ON CONFLICT DO NOTHING;
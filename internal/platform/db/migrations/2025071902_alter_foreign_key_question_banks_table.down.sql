@@ -0,0 +1,20 @@
+ALTER TABLE
+    quiz_attempt_questions DROP CONSTRAINT IF EXISTS fk_quiz_attempt_questions_quiz_question_id;
+
+ALTER TABLE
+    quiz_attempt_questions DROP CONSTRAINT IF EXISTS fk_quiz_attempt_questions_quiz_attempt_id;
+
+ALTER TABLE
+    quiz_draw_rules DROP CONSTRAINT IF EXISTS fk_quiz_draw_rules_question_bank_id;
+
+ALTER TABLE
+    quiz_draw_rules DROP CONSTRAINT IF EXISTS fk_quiz_draw_rules_quiz_id;
+
+ALTER TABLE
+    quiz_questions DROP CONSTRAINT IF EXISTS fk_quiz_questions_question_bank_id;
+
+ALTER TABLE
+    question_bank_skill_keywords DROP CONSTRAINT IF EXISTS fk_question_bank_skill_keywords_skill_keyword_id;
+
+ALTER TABLE
+    question_bank_skill_keywords DROP CONSTRAINT IF EXISTS fk_question_bank_skill_keywords_question_bank_id;
@@ -0,0 +1,34 @@
+ALTER TABLE
+    question_bank_skill_keywords
+ADD
+    CONSTRAINT fk_question_bank_skill_keywords_question_bank_id FOREIGN KEY (question_bank_id) REFERENCES question_banks(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    question_bank_skill_keywords
+ADD
+    CONSTRAINT fk_question_bank_skill_keywords_skill_keyword_id FOREIGN KEY (skill_keyword_id) REFERENCES skill_keywords(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_questions
+ADD
+    CONSTRAINT fk_quiz_questions_question_bank_id FOREIGN KEY (question_bank_id) REFERENCES question_banks(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_draw_rules
+ADD
+    CONSTRAINT fk_quiz_draw_rules_quiz_id FOREIGN KEY (quiz_id) REFERENCES quizzes(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_draw_rules
+ADD
+    CONSTRAINT fk_quiz_draw_rules_question_bank_id FOREIGN KEY (question_bank_id) REFERENCES question_banks(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_attempt_questions
+ADD
+    CONSTRAINT fk_quiz_attempt_questions_quiz_attempt_id FOREIGN KEY (quiz_attempt_id) REFERENCES quiz_attempts(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_attempt_questions
+ADD
+    CONSTRAINT fk_quiz_attempt_questions_quiz_question_id FOREIGN KEY (quiz_question_id) REFERENCES quiz_questions(id) ON DELETE CASCADE;
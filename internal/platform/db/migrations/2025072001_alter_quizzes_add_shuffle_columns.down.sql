@@ -0,0 +1,3 @@
+ALTER TABLE
+    quizzes DROP COLUMN IF EXISTS shuffle_options,
+    DROP COLUMN IF EXISTS shuffle_questions;
@@ -0,0 +1,6 @@
+ALTER TABLE
+    quizzes
+ADD
+    COLUMN IF NOT EXISTS shuffle_questions BOOLEAN NOT NULL DEFAULT FALSE,
+ADD
+    COLUMN IF NOT EXISTS shuffle_options BOOLEAN NOT NULL DEFAULT FALSE;
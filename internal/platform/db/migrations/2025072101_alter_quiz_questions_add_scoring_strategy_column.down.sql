@@ -0,0 +1,3 @@
+ALTER TABLE
+    quiz_questions DROP CONSTRAINT IF EXISTS chk_quiz_questions_scoring_strategy,
+    DROP COLUMN IF EXISTS scoring_strategy;
@@ -0,0 +1,12 @@
+ALTER TABLE
+    quiz_questions
+ADD
+    COLUMN IF NOT EXISTS scoring_strategy VARCHAR(30) NOT NULL DEFAULT 'all_or_nothing',
+ADD
+    CONSTRAINT chk_quiz_questions_scoring_strategy CHECK (
+        scoring_strategy IN (
+            'all_or_nothing',
+            'proportional',
+            'right_minus_wrong'
+        )
+    );
@@ -0,0 +1,10 @@
+ALTER TABLE
+    quiz_submissions DROP COLUMN IF EXISTS match_answers;
+
+DROP TABLE IF EXISTS quiz_numeric_answers;
+
+DROP TABLE IF EXISTS quiz_order_items;
+
+DROP TABLE IF EXISTS quiz_match_pairs;
+
+DROP TABLE IF EXISTS quiz_accepted_answers;
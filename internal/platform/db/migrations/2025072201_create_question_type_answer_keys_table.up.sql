@@ -0,0 +1,59 @@
+-- Short answer questions accept any of their accepted answers
+CREATE TABLE IF NOT EXISTS quiz_accepted_answers (
+    id SERIAL PRIMARY KEY,
+    quiz_question_id INT NOT NULL,
+    answer_text TEXT NOT NULL,
+    case_sensitive BOOLEAN NOT NULL DEFAULT FALSE,
+    ignore_whitespace BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_accepted_answers_question ON quiz_accepted_answers (quiz_question_id);
+
+-- Matching questions pair each prompt with its match
+CREATE TABLE IF NOT EXISTS quiz_match_pairs (
+    id SERIAL PRIMARY KEY,
+    quiz_question_id INT NOT NULL,
+    prompt_text TEXT NOT NULL,
+    match_text TEXT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_match_pairs_question ON quiz_match_pairs (quiz_question_id);
+
+-- Ordering questions list their items in the correct order
+CREATE TABLE IF NOT EXISTS quiz_order_items (
+    id SERIAL PRIMARY KEY,
+    quiz_question_id INT NOT NULL,
+    item_text TEXT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_order_items_question ON quiz_order_items (quiz_question_id);
+
+-- Numeric questions accept a value within the tolerance of one of their answers
+CREATE TABLE IF NOT EXISTS quiz_numeric_answers (
+    id SERIAL PRIMARY KEY,
+    quiz_question_id INT NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    tolerance DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+    CHECK (tolerance >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_numeric_answers_question ON quiz_numeric_answers (quiz_question_id);
+
+ALTER TABLE
+    quiz_submissions
+ADD
+    COLUMN IF NOT EXISTS match_answers JSONB DEFAULT NULL;
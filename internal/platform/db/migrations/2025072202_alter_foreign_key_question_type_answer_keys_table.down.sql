@@ -0,0 +1,11 @@
+ALTER TABLE
+    quiz_numeric_answers DROP CONSTRAINT IF EXISTS fk_quiz_numeric_answers_quiz_question_id;
+
+ALTER TABLE
+    quiz_order_items DROP CONSTRAINT IF EXISTS fk_quiz_order_items_quiz_question_id;
+
+ALTER TABLE
+    quiz_match_pairs DROP CONSTRAINT IF EXISTS fk_quiz_match_pairs_quiz_question_id;
+
+ALTER TABLE
+    quiz_accepted_answers DROP CONSTRAINT IF EXISTS fk_quiz_accepted_answers_quiz_question_id;
@@ -0,0 +1,19 @@
+ALTER TABLE
+    quiz_accepted_answers
+ADD
+    CONSTRAINT fk_quiz_accepted_answers_quiz_question_id FOREIGN KEY (quiz_question_id) REFERENCES quiz_questions(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_match_pairs
+ADD
+    CONSTRAINT fk_quiz_match_pairs_quiz_question_id FOREIGN KEY (quiz_question_id) REFERENCES quiz_questions(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_order_items
+ADD
+    CONSTRAINT fk_quiz_order_items_quiz_question_id FOREIGN KEY (quiz_question_id) REFERENCES quiz_questions(id) ON DELETE CASCADE;
+
+ALTER TABLE
+    quiz_numeric_answers
+ADD
+    CONSTRAINT fk_quiz_numeric_answers_quiz_question_id FOREIGN KEY (quiz_question_id) REFERENCES quiz_questions(id) ON DELETE CASCADE;
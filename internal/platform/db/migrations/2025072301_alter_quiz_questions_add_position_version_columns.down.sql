@@ -0,0 +1,7 @@
+ALTER TABLE
+    quiz_answers DROP COLUMN IF EXISTS position;
+
+ALTER TABLE
+    quiz_questions DROP COLUMN IF EXISTS previous_version_id,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS position;
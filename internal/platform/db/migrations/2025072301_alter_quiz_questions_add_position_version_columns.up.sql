@@ -0,0 +1,54 @@
+-- A question shown in attempts is replaced by a new version when it is edited,
+-- previous_version_id links the new version to the one the attempts keep
+ALTER TABLE
+    quiz_questions
+ADD
+    COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0,
+ADD
+    COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1,
+ADD
+    COLUMN IF NOT EXISTS previous_version_id INT DEFAULT NULL;
+
+ALTER TABLE
+    quiz_answers
+ADD
+    COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+
+UPDATE
+    quiz_questions
+SET
+    position = ordered.position
+FROM
+    (
+        SELECT
+            id,
+            ROW_NUMBER() OVER (
+                PARTITION BY quiz_id,
+                question_bank_id
+                ORDER BY
+                    id
+            ) AS position
+        FROM
+            quiz_questions
+    ) AS ordered
+WHERE
+    quiz_questions.id = ordered.id;
+
+UPDATE
+    quiz_answers
+SET
+    position = ordered.position
+FROM
+    (
+        SELECT
+            id,
+            ROW_NUMBER() OVER (
+                PARTITION BY quiz_question_id
+                ORDER BY
+                    id
+            ) AS position
+        FROM
+            quiz_answers
+    ) AS ordered
+WHERE
+    quiz_answers.id = ordered.id;
@@ -0,0 +1,2 @@
+ALTER TABLE
+    quiz_questions DROP CONSTRAINT IF EXISTS fk_quiz_questions_previous_version_id;
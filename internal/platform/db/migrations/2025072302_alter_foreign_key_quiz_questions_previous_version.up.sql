@@ -0,0 +1,4 @@
+ALTER TABLE
+    quiz_questions
+ADD
+    CONSTRAINT fk_quiz_questions_previous_version_id FOREIGN KEY (previous_version_id) REFERENCES quiz_questions(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE
+    quiz_order_items DROP COLUMN IF EXISTS option_key;
+
+ALTER TABLE
+    quiz_match_pairs DROP COLUMN IF EXISTS option_key;
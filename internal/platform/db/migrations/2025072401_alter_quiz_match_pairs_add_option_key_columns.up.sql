@@ -0,0 +1,39 @@
+-- Trainees answer matching and ordering questions with option keys, random numbers that tell nothing
+-- of the pair a match belongs to or of the place of an item, unlike the serial ids
+ALTER TABLE
+    quiz_match_pairs
+ADD
+    COLUMN IF NOT EXISTS option_key INT;
+
+ALTER TABLE
+    quiz_order_items
+ADD
+    COLUMN IF NOT EXISTS option_key INT;
+
+UPDATE
+    quiz_match_pairs
+SET
+    option_key = FLOOR(RANDOM() * 1000000000)::INT + 1
+WHERE
+    option_key IS NULL;
+
+UPDATE
+    quiz_order_items
+SET
+    option_key = FLOOR(RANDOM() * 1000000000)::INT + 1
+WHERE
+    option_key IS NULL;
+
+ALTER TABLE
+    quiz_match_pairs
+ALTER COLUMN
+    option_key
+SET
+    NOT NULL;
+
+ALTER TABLE
+    quiz_order_items
+ALTER COLUMN
+    option_key
+SET
+    NOT NULL;
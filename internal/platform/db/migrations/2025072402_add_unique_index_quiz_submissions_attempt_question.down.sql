@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uq_quiz_submissions_attempt_question;
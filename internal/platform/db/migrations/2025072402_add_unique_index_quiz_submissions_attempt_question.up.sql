@@ -0,0 +1,64 @@
+-- An attempt has one submission per question, repeated submissions are removed and the scores of their attempts recomputed
+WITH removed AS (
+    UPDATE
+        quiz_submissions
+    SET
+        deleted_at = NOW()
+    WHERE
+        id IN (
+            SELECT
+                id
+            FROM
+                (
+                    SELECT
+                        id,
+                        ROW_NUMBER() OVER (
+                            PARTITION BY quiz_attempt_id,
+                            quiz_question_id
+                            ORDER BY
+                                id
+                        ) AS row_number
+                    FROM
+                        quiz_submissions
+                    WHERE
+                        quiz_attempt_id IS NOT NULL
+                        AND deleted_at IS NULL
+                ) AS numbered
+            WHERE
+                row_number > 1
+        ) RETURNING id,
+        quiz_attempt_id
+)
+UPDATE
+    quiz_attempts AS qa
+SET
+    score = COALESCE(
+        (
+            SELECT
+                SUM(qs.score)
+            FROM
+                quiz_submissions AS qs
+            WHERE
+                qs.quiz_attempt_id = qa.id
+                AND qs.deleted_at IS NULL
+                AND qs.id NOT IN (
+                    SELECT
+                        id
+                    FROM
+                        removed
+                )
+        ),
+        0
+    )
+WHERE
+    qa.status = 'submitted'
+    AND qa.id IN (
+        SELECT
+            quiz_attempt_id
+        FROM
+            removed
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quiz_submissions_attempt_question ON quiz_submissions (quiz_attempt_id, quiz_question_id)
+WHERE
+    deleted_at IS NULL;
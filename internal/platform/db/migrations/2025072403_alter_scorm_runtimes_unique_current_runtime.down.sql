@@ -0,0 +1,11 @@
+DELETE FROM
+    scorm_runtimes
+WHERE
+    deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS uq_scorm_runtimes_user_module_item;
+
+ALTER TABLE
+    scorm_runtimes
+ADD
+    CONSTRAINT scorm_runtimes_user_id_module_item_id_key UNIQUE (user_id, module_item_id);
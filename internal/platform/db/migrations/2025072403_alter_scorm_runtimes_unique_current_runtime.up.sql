@@ -0,0 +1,7 @@
+-- A new cycle sets aside the SCORM runtime of a user as a deleted row, only the current runtime is unique
+ALTER TABLE
+    scorm_runtimes DROP CONSTRAINT IF EXISTS scorm_runtimes_user_id_module_item_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_scorm_runtimes_user_module_item ON scorm_runtimes (user_id, module_item_id)
+WHERE
+    deleted_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE
+    module_item_completions DROP COLUMN IF EXISTS position_changed_at;
@@ -0,0 +1,5 @@
+-- File and slide lectures only count time while the page or slide shown keeps changing
+ALTER TABLE
+    module_item_completions
+ADD
+    COLUMN IF NOT EXISTS position_changed_at TIMESTAMP DEFAULT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE
+    scorm_runtimes DROP COLUMN IF EXISTS session_time;
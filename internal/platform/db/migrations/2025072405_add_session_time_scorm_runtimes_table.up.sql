@@ -0,0 +1,5 @@
+-- The session time already counted in the total time of the current SCORM session
+ALTER TABLE
+    scorm_runtimes
+ADD
+    COLUMN IF NOT EXISTS session_time INT NOT NULL DEFAULT 0;
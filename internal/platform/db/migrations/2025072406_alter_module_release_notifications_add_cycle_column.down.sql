@@ -0,0 +1,23 @@
+DELETE FROM
+    module_release_notifications
+WHERE
+    id NOT IN (
+        SELECT
+            MIN(id)
+        FROM
+            module_release_notifications
+        GROUP BY
+            module_id,
+            user_id
+    );
+
+ALTER TABLE
+    module_release_notifications DROP CONSTRAINT IF EXISTS module_release_notifications_module_id_user_id_cycle_key;
+
+ALTER TABLE
+    module_release_notifications
+ADD
+    CONSTRAINT module_release_notifications_module_id_user_id_key UNIQUE (module_id, user_id);
+
+ALTER TABLE
+    module_release_notifications DROP COLUMN IF EXISTS cycle;
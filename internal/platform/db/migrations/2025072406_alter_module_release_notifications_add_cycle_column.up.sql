@@ -0,0 +1,46 @@
+-- A trainee is told again that a module opened in each new cycle of their enrollment
+ALTER TABLE
+    module_release_notifications
+ADD
+    COLUMN IF NOT EXISTS cycle INT NOT NULL DEFAULT 1;
+
+UPDATE
+    module_release_notifications
+SET
+    cycle = user_progresses.cycle
+FROM
+    modules,
+    user_progresses
+WHERE
+    modules.id = module_release_notifications.module_id
+    AND user_progresses.course_id = modules.course_id
+    AND user_progresses.user_id = module_release_notifications.user_id
+    AND user_progresses.deleted_at IS NULL;
+
+ALTER TABLE
+    module_release_notifications DROP CONSTRAINT IF EXISTS module_release_notifications_module_id_user_id_key;
+
+ALTER TABLE
+    module_release_notifications
+ADD
+    CONSTRAINT module_release_notifications_module_id_user_id_cycle_key UNIQUE (module_id, user_id, cycle);
+
+-- Enrollments that existed before assigned_at was added got the time of that migration,
+-- only a recertification sets assigned_at after the enrollment was created
+UPDATE
+    user_progresses
+SET
+    assigned_at = user_progresses.created_at
+WHERE
+    user_progresses.created_at IS NOT NULL
+    AND user_progresses.assigned_at > user_progresses.created_at
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            user_progress_histories
+        WHERE
+            user_progress_histories.user_id = user_progresses.user_id
+            AND user_progress_histories.course_id = user_progresses.course_id
+            AND user_progress_histories.reason = 'recertification'
+    );
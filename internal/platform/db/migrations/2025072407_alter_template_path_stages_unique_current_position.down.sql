@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS uq_template_path_stages_position;
+
+ALTER TABLE
+    template_path_stages
+ADD
+    CONSTRAINT uq_template_path_stages_position UNIQUE (template_path_id, position) DEFERRABLE INITIALLY DEFERRED;
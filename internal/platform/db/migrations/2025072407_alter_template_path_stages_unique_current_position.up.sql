@@ -0,0 +1,7 @@
+-- Deleted stages no longer hold their position in the path
+ALTER TABLE
+    template_path_stages DROP CONSTRAINT IF EXISTS uq_template_path_stages_position;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_template_path_stages_position ON template_path_stages (template_path_id, position)
+WHERE
+    deleted_at IS NULL;
@@ -0,0 +1,17 @@
+-- The shuffle options of the quiz when the attempt started, so the attempt keeps its order
+ALTER TABLE
+    quiz_attempts
+ADD
+    COLUMN IF NOT EXISTS shuffle_questions BOOLEAN NOT NULL DEFAULT FALSE,
+ADD
+    COLUMN IF NOT EXISTS shuffle_options BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE
+    quiz_attempts
+SET
+    shuffle_questions = quizzes.shuffle_questions,
+    shuffle_options = quizzes.shuffle_options
+FROM
+    quizzes
+WHERE
+    quizzes.id = quiz_attempts.quiz_id;
@@ -0,0 +1,306 @@
+package quizformat
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	cf "orientation-training-api/configs"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+)
+
+// The CSV layout has a header row naming its columns: question_type, question_text, weight, allow_multiple,
+// scoring_strategy, explanation, correct and answer_1, answer_2... as many answer columns as needed.
+// question_type is multiple_choice, essay, true_false, short_answer, matching, ordering or numeric.
+// correct holds the numbers of the correct answers separated by | for a multiple choice question, true or false
+// for a true/false question and the flags case_sensitive and ignore_whitespace separated by | for a short answer question.
+// The answers are the options of a multiple choice question, the accepted answers of a short answer question,
+// the "prompt => match" pairs of a matching question, the items of an ordering question in their correct order
+// and the "value:tolerance" answers of a numeric question.
+
+const csvMatchSeparator = "=>"
+
+var csvQuestionTypes = map[string]int{
+	"multiple_choice": cf.QuestionTypeMultipleChoice,
+	"essay":           cf.QuestionTypeEssay,
+	"true_false":      cf.QuestionTypeTrueFalse,
+	"short_answer":    cf.QuestionTypeShortAnswer,
+	"matching":        cf.QuestionTypeMatching,
+	"ordering":        cf.QuestionTypeOrdering,
+	"numeric":         cf.QuestionTypeNumeric,
+}
+
+var csvColumns = []string{"question_type", "question_text", "weight", "allow_multiple", "scoring_strategy", "explanation", "correct"}
+
+func parseCSV(data []byte) ([]Question, []LineError) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []LineError{csvError(err, 1)}
+	}
+
+	columns := map[string]int{}
+	answerColumns := []int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.HasPrefix(name, "answer_") {
+			answerColumns = append(answerColumns, i)
+			continue
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"question_type", "question_text"} {
+		if _, ok := columns[name]; !ok {
+			return nil, []LineError{{Line: 1, Message: "The header has no " + name + " column"}}
+		}
+	}
+
+	questions := []Question{}
+	lineErrors := []LineError{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			lineErrors = append(lineErrors, csvError(err, 0))
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		answers := []string{}
+		for _, i := range answerColumns {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				answers = append(answers, strings.TrimSpace(record[i]))
+			}
+		}
+
+		if field("question_type") == "" && field("question_text") == "" && len(answers) == 0 {
+			continue
+		}
+
+		question, message := parseCSVQuestion(field, answers)
+		if message != "" {
+			lineErrors = append(lineErrors, LineError{Line: line, Message: message})
+			continue
+		}
+		question.Line = line
+		questions = append(questions, question)
+	}
+
+	return questions, lineErrors
+}
+
+func parseCSVQuestion(field func(name string) string, answers []string) (Question, string) {
+	question := Question{}
+
+	questionType := strings.ToLower(field("question_type"))
+	if number, err := strconv.Atoi(questionType); err == nil {
+		question.QuestionType = number
+	} else if question.QuestionType = csvQuestionTypes[questionType]; question.QuestionType == 0 {
+		return question, fmt.Sprintf("Invalid question_type %q", field("question_type"))
+	}
+
+	question.QuestionText = field("question_text")
+	question.Explanation = field("explanation")
+	question.ScoringStrategy = field("scoring_strategy")
+
+	if weight := field("weight"); weight != "" {
+		value, err := strconv.ParseFloat(weight, 64)
+		if err != nil || value <= 0 {
+			return question, "weight must be a positive number"
+		}
+		question.Weight = value
+	}
+	if allowMultiple := field("allow_multiple"); allowMultiple != "" {
+		value, err := strconv.ParseBool(allowMultiple)
+		if err != nil {
+			return question, "allow_multiple must be true or false"
+		}
+		question.AllowMultiple = value
+	}
+
+	correct := field("correct")
+	switch question.QuestionType {
+	case cf.QuestionTypeMultipleChoice:
+		correctNumbers := map[int]bool{}
+		for _, number := range splitList(correct) {
+			value, err := strconv.Atoi(number)
+			if err != nil || value < 1 || value > len(answers) {
+				return question, fmt.Sprintf("correct must list answer numbers from 1 to %d", len(answers))
+			}
+			correctNumbers[value] = true
+		}
+		for i, answer := range answers {
+			question.Options = append(question.Options, param.QuizOption{
+				AnswersText: answer,
+				IsCorrect:   correctNumbers[i+1],
+			})
+		}
+	case cf.QuestionTypeTrueFalse:
+		value, err := strconv.ParseBool(correct)
+		if err != nil {
+			return question, "correct must be true or false for a true/false question"
+		}
+		question.CorrectBoolean = &value
+	case cf.QuestionTypeShortAnswer:
+		acceptedAnswer := param.AcceptedAnswerParam{}
+		for _, flag := range splitList(correct) {
+			switch strings.ToLower(flag) {
+			case "case_sensitive":
+				acceptedAnswer.CaseSensitive = true
+			case "ignore_whitespace":
+				acceptedAnswer.IgnoreWhitespace = true
+			default:
+				return question, fmt.Sprintf("Invalid short answer flag %q", flag)
+			}
+		}
+		for _, answer := range answers {
+			acceptedAnswer.AnswerText = answer
+			question.AcceptedAnswers = append(question.AcceptedAnswers, acceptedAnswer)
+		}
+	case cf.QuestionTypeMatching:
+		for _, answer := range answers {
+			prompt, match, found := strings.Cut(answer, csvMatchSeparator)
+			if !found {
+				return question, fmt.Sprintf("Match pair %q must be written prompt %s match", answer, csvMatchSeparator)
+			}
+			question.MatchPairs = append(question.MatchPairs, param.MatchPairParam{
+				PromptText: strings.TrimSpace(prompt),
+				MatchText:  strings.TrimSpace(match),
+			})
+		}
+	case cf.QuestionTypeOrdering:
+		question.OrderItems = answers
+	case cf.QuestionTypeNumeric:
+		for _, answer := range answers {
+			numericAnswer := param.NumericAnswerParam{}
+			value, tolerance, _ := strings.Cut(answer, ":")
+			var err error
+			if numericAnswer.Value, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && tolerance != "" {
+				numericAnswer.Tolerance, err = strconv.ParseFloat(strings.TrimSpace(tolerance), 64)
+			}
+			if err != nil {
+				return question, fmt.Sprintf("Invalid numeric answer %q", answer)
+			}
+			question.NumericAnswers = append(question.NumericAnswers, numericAnswer)
+		}
+	}
+
+	return question, ""
+}
+
+func writeCSV(questions []m.QuizQuestion) ([]byte, error) {
+	answerCount := 0
+	records := [][]string{}
+
+	for _, question := range questions {
+		questionType := ""
+		for name, value := range csvQuestionTypes {
+			if value == question.QuestionType {
+				questionType = name
+			}
+		}
+
+		correct := []string{}
+		answers := []string{}
+		switch question.QuestionType {
+		case cf.QuestionTypeMultipleChoice:
+			for i, answer := range question.Answers {
+				if answer.IsCorrect {
+					correct = append(correct, strconv.Itoa(i+1))
+				}
+				answers = append(answers, answer.AnswerText)
+			}
+		case cf.QuestionTypeTrueFalse:
+			correct = append(correct, strconv.FormatBool(trueFalseAnswer(question)))
+		case cf.QuestionTypeShortAnswer:
+			for i, acceptedAnswer := range question.AcceptedAnswers {
+				if i == 0 && acceptedAnswer.CaseSensitive {
+					correct = append(correct, "case_sensitive")
+				}
+				if i == 0 && acceptedAnswer.IgnoreWhitespace {
+					correct = append(correct, "ignore_whitespace")
+				}
+				answers = append(answers, acceptedAnswer.AnswerText)
+			}
+		case cf.QuestionTypeMatching:
+			for _, matchPair := range question.MatchPairs {
+				answers = append(answers, matchPair.PromptText+" "+csvMatchSeparator+" "+matchPair.MatchText)
+			}
+		case cf.QuestionTypeOrdering:
+			for _, orderItem := range question.OrderItems {
+				answers = append(answers, orderItem.ItemText)
+			}
+		case cf.QuestionTypeNumeric:
+			for _, numericAnswer := range question.NumericAnswers {
+				answers = append(answers, formatNumber(numericAnswer.Value)+":"+formatNumber(numericAnswer.Tolerance))
+			}
+		}
+
+		if len(answers) > answerCount {
+			answerCount = len(answers)
+		}
+		records = append(records, append([]string{
+			questionType,
+			question.QuestionText,
+			formatNumber(question.Weight),
+			strconv.FormatBool(question.IsMultipleCorrect),
+			question.ScoringStrategy,
+			question.Explanation,
+			strings.Join(correct, "|"),
+		}, answers...))
+	}
+
+	header := append([]string{}, csvColumns...)
+	for i := 1; i <= answerCount; i++ {
+		header = append(header, fmt.Sprintf("answer_%d", i))
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		for len(record) < len(header) {
+			record = append(record, "")
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+
+	return buf.Bytes(), writer.Error()
+}
+
+func csvError(err error, line int) LineError {
+	if parseErr, ok := err.(*csv.ParseError); ok {
+		return LineError{Line: parseErr.Line, Message: parseErr.Err.Error()}
+	}
+	return LineError{Line: line, Message: err.Error()}
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
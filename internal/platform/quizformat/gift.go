@@ -0,0 +1,480 @@
+package quizformat
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cf "orientation-training-api/configs"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+)
+
+// GIFT questions are separated by blank lines, the comment directives "// weight: 0.25" and
+// "// scoring_strategy: proportional" apply to the next question. Short answers are compared ignoring case,
+// ordering questions have no GIFT syntax.
+
+const giftEscapedChars = `~=#{}:\`
+
+type giftBlock struct {
+	line       int
+	text       string
+	directives map[string]string
+}
+
+type giftEntry struct {
+	correct  bool
+	percent  float64
+	weighted bool
+	text     string
+}
+
+func parseGIFT(data []byte) ([]Question, []LineError) {
+	blocks := []giftBlock{}
+	directives := map[string]string{}
+	current := giftBlock{}
+	lines := []string{}
+
+	closeBlock := func() {
+		if len(lines) > 0 {
+			current.text = strings.Join(lines, "\n")
+			blocks = append(blocks, current)
+		}
+		current = giftBlock{}
+		lines = []string{}
+	}
+
+	content := strings.TrimPrefix(string(data), "\ufeff")
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			closeBlock()
+		case strings.HasPrefix(trimmed, "//"):
+			if key, value, found := strings.Cut(strings.TrimSpace(strings.TrimPrefix(trimmed, "//")), ":"); found {
+				directives[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			continue
+		default:
+			if len(lines) == 0 {
+				current.line = i + 1
+				current.directives = directives
+				directives = map[string]string{}
+			}
+			lines = append(lines, line)
+		}
+	}
+	closeBlock()
+
+	questions := []Question{}
+	lineErrors := []LineError{}
+	for _, block := range blocks {
+		question, message := parseGIFTQuestion(block)
+		if message != "" {
+			lineErrors = append(lineErrors, LineError{Line: block.line, Message: message})
+			continue
+		}
+		questions = append(questions, question)
+	}
+
+	return questions, lineErrors
+}
+
+func parseGIFTQuestion(block giftBlock) (Question, string) {
+	question := Question{Line: block.line}
+
+	text := strings.TrimSpace(block.text)
+	if strings.HasPrefix(text, "::") {
+		if end := strings.Index(text[2:], "::"); end >= 0 {
+			text = strings.TrimSpace(text[end+4:])
+		}
+	}
+	for _, textFormat := range []string{"[html]", "[moodle]", "[plain]", "[markdown]"} {
+		text = strings.TrimPrefix(text, textFormat)
+	}
+
+	open := indexUnescaped(text, "{")
+	if open < 0 {
+		return question, "The answer block { } of the question is missing"
+	}
+	closing := indexUnescaped(text[open:], "}")
+	if closing < 0 {
+		return question, "The answer block of the question is not closed by }"
+	}
+	closing += open
+
+	question.QuestionText = unescapeGIFT(strings.TrimSpace(text[:open]))
+	if after := strings.TrimSpace(text[closing+1:]); after != "" {
+		question.QuestionText += " _____ " + unescapeGIFT(after)
+	}
+	if question.QuestionText == "" {
+		return question, "The question text is empty"
+	}
+
+	answerBlock := text[open+1 : closing]
+	if feedback := indexUnescaped(answerBlock, "####"); feedback >= 0 {
+		question.Explanation = unescapeGIFT(strings.TrimSpace(answerBlock[feedback+4:]))
+		answerBlock = answerBlock[:feedback]
+	}
+	answerBlock = strings.TrimSpace(answerBlock)
+
+	var message string
+	switch {
+	case answerBlock == "":
+		question.QuestionType = cf.QuestionTypeEssay
+	case strings.HasPrefix(answerBlock, "#"):
+		message = parseGIFTNumeric(&question, answerBlock[1:])
+	default:
+		message = parseGIFTAnswers(&question, answerBlock)
+	}
+	if message != "" {
+		return question, message
+	}
+
+	if weight, ok := block.directives["weight"]; ok {
+		value, err := strconv.ParseFloat(weight, 64)
+		if err != nil || value <= 0 {
+			return question, "weight must be a positive number"
+		}
+		question.Weight = value
+	}
+	if scoringStrategy, ok := block.directives["scoring_strategy"]; ok {
+		question.ScoringStrategy = scoringStrategy
+	}
+
+	return question, ""
+}
+
+func parseGIFTNumeric(question *Question, answerBlock string) string {
+	question.QuestionType = cf.QuestionTypeNumeric
+
+	entries := splitUnescaped(answerBlock, "=")
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if feedback := indexUnescaped(entry, "#"); feedback >= 0 {
+			entry = strings.TrimSpace(entry[:feedback])
+		}
+		if entry == "" {
+			continue
+		}
+		if strings.HasPrefix(entry, "%") {
+			if end := strings.Index(entry[1:], "%"); end >= 0 {
+				entry = strings.TrimSpace(entry[end+2:])
+			}
+		}
+
+		numericAnswer := param.NumericAnswerParam{}
+		var err error
+		if minValue, maxValue, isRange := strings.Cut(entry, ".."); isRange {
+			var low, high float64
+			if low, err = strconv.ParseFloat(strings.TrimSpace(minValue), 64); err == nil {
+				high, err = strconv.ParseFloat(strings.TrimSpace(maxValue), 64)
+			}
+			numericAnswer.Value = (low + high) / 2
+			numericAnswer.Tolerance = (high - low) / 2
+		} else {
+			value, tolerance, _ := strings.Cut(entry, ":")
+			if numericAnswer.Value, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && tolerance != "" {
+				numericAnswer.Tolerance, err = strconv.ParseFloat(strings.TrimSpace(tolerance), 64)
+			}
+		}
+		if err != nil {
+			return fmt.Sprintf("Invalid numeric answer %q", entry)
+		}
+
+		question.NumericAnswers = append(question.NumericAnswers, numericAnswer)
+	}
+
+	if len(question.NumericAnswers) == 0 {
+		return "The numeric question has no answer"
+	}
+
+	return ""
+}
+
+func parseGIFTAnswers(question *Question, answerBlock string) string {
+	switch strings.ToUpper(strings.TrimSpace(strings.SplitN(answerBlock, "#", 2)[0])) {
+	case "T", "TRUE":
+		correct := true
+		question.QuestionType = cf.QuestionTypeTrueFalse
+		question.CorrectBoolean = &correct
+		return ""
+	case "F", "FALSE":
+		correct := false
+		question.QuestionType = cf.QuestionTypeTrueFalse
+		question.CorrectBoolean = &correct
+		return ""
+	}
+
+	entries, message := splitGIFTEntries(answerBlock)
+	if message != "" {
+		return message
+	}
+
+	allCorrect := true
+	isMatching := false
+	for _, entry := range entries {
+		allCorrect = allCorrect && entry.correct && !entry.weighted
+		isMatching = isMatching || indexUnescaped(entry.text, "->") >= 0
+	}
+
+	switch {
+	case isMatching:
+		question.QuestionType = cf.QuestionTypeMatching
+		for _, entry := range entries {
+			separator := indexUnescaped(entry.text, "->")
+			if !entry.correct || separator < 0 {
+				return "Every answer of a matching question must be written =prompt -> match"
+			}
+			question.MatchPairs = append(question.MatchPairs, param.MatchPairParam{
+				PromptText: unescapeGIFT(strings.TrimSpace(entry.text[:separator])),
+				MatchText:  unescapeGIFT(strings.TrimSpace(entry.text[separator+2:])),
+			})
+		}
+	case allCorrect:
+		question.QuestionType = cf.QuestionTypeShortAnswer
+		for _, entry := range entries {
+			question.AcceptedAnswers = append(question.AcceptedAnswers, param.AcceptedAnswerParam{
+				AnswerText: unescapeGIFT(entry.text),
+			})
+		}
+	default:
+		question.QuestionType = cf.QuestionTypeMultipleChoice
+		hasPenalty := false
+		for _, entry := range entries {
+			isCorrect := entry.correct || entry.percent > 0
+			question.AllowMultiple = question.AllowMultiple || (entry.weighted && entry.percent > 0)
+			hasPenalty = hasPenalty || entry.percent < 0
+			question.Options = append(question.Options, param.QuizOption{
+				AnswersText: unescapeGIFT(entry.text),
+				IsCorrect:   isCorrect,
+			})
+		}
+
+		if question.AllowMultiple {
+			question.ScoringStrategy = cf.ScoringProportional
+			if hasPenalty {
+				question.ScoringStrategy = cf.ScoringRightMinusWrong
+			}
+		}
+	}
+
+	return ""
+}
+
+// splitGIFTEntries splits an answer block into its =correct and ~wrong answers, with their optional %percent%
+func splitGIFTEntries(answerBlock string) ([]giftEntry, string) {
+	entries := []giftEntry{}
+	start := -1
+
+	addEntry := func(end int) string {
+		if start < 0 {
+			if strings.TrimSpace(answerBlock[:end]) != "" {
+				return "Answers must start with = or ~"
+			}
+			return ""
+		}
+
+		entry := giftEntry{correct: answerBlock[start] == '='}
+		text := strings.TrimSpace(answerBlock[start+1 : end])
+		if feedback := indexUnescaped(text, "#"); feedback >= 0 {
+			text = strings.TrimSpace(text[:feedback])
+		}
+		if strings.HasPrefix(text, "%") {
+			closing := strings.Index(text[1:], "%")
+			if closing < 0 {
+				return "The answer percentage is not closed by %"
+			}
+			percent, err := strconv.ParseFloat(text[1:closing+1], 64)
+			if err != nil {
+				return fmt.Sprintf("Invalid answer percentage %q", text[1:closing+1])
+			}
+			entry.percent = percent
+			entry.weighted = true
+			text = strings.TrimSpace(text[closing+2:])
+		}
+		if text == "" {
+			return "Answers can not be empty"
+		}
+
+		entry.text = text
+		entries = append(entries, entry)
+		return ""
+	}
+
+	for i := 0; i < len(answerBlock); i++ {
+		switch answerBlock[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			if message := addEntry(i); message != "" {
+				return nil, message
+			}
+			start = i
+		}
+	}
+	if message := addEntry(len(answerBlock)); message != "" {
+		return nil, message
+	}
+
+	if len(entries) == 0 {
+		return nil, "The question has no answer"
+	}
+
+	return entries, ""
+}
+
+func writeGIFT(questions []m.QuizQuestion) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for i, question := range questions {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(&buf, "// weight: %s\n", formatNumber(question.Weight))
+		if question.QuestionType == cf.QuestionTypeMultipleChoice && question.IsMultipleCorrect {
+			fmt.Fprintf(&buf, "// scoring_strategy: %s\n", question.ScoringStrategy)
+		}
+		buf.WriteString(escapeGIFT(question.QuestionText) + " {")
+
+		switch question.QuestionType {
+		case cf.QuestionTypeEssay:
+		case cf.QuestionTypeTrueFalse:
+			if trueFalseAnswer(question) {
+				buf.WriteString("TRUE")
+			} else {
+				buf.WriteString("FALSE")
+			}
+		case cf.QuestionTypeMultipleChoice:
+			correctCount := 0
+			for _, answer := range question.Answers {
+				if answer.IsCorrect {
+					correctCount++
+				}
+			}
+
+			for _, answer := range question.Answers {
+				switch {
+				case !question.IsMultipleCorrect && answer.IsCorrect:
+					buf.WriteString("\n=" + escapeGIFT(answer.AnswerText))
+				case !question.IsMultipleCorrect:
+					buf.WriteString("\n~" + escapeGIFT(answer.AnswerText))
+				default:
+					percent := 100 / float64(correctCount)
+					if !answer.IsCorrect {
+						percent = 0
+						if question.ScoringStrategy == cf.ScoringRightMinusWrong {
+							percent = -100 / float64(correctCount)
+						}
+					}
+					fmt.Fprintf(&buf, "\n~%%%s%%%s", strconv.FormatFloat(percent, 'f', 5, 64), escapeGIFT(answer.AnswerText))
+				}
+			}
+			buf.WriteString("\n")
+		case cf.QuestionTypeShortAnswer:
+			// GIFT answers are matched ignoring case only
+			for _, acceptedAnswer := range question.AcceptedAnswers {
+				if acceptedAnswer.CaseSensitive {
+					return nil, unsupportedSettingError(i+1, "case sensitive answers", "GIFT")
+				}
+				if acceptedAnswer.IgnoreWhitespace {
+					return nil, unsupportedSettingError(i+1, "answers ignoring whitespace", "GIFT")
+				}
+				buf.WriteString("\n=" + escapeGIFT(acceptedAnswer.AnswerText))
+			}
+			buf.WriteString("\n")
+		case cf.QuestionTypeMatching:
+			for _, matchPair := range question.MatchPairs {
+				buf.WriteString("\n=" + escapeGIFT(matchPair.PromptText) + " -> " + escapeGIFT(matchPair.MatchText))
+			}
+			buf.WriteString("\n")
+		case cf.QuestionTypeNumeric:
+			buf.WriteString("#")
+			for _, numericAnswer := range question.NumericAnswers {
+				fmt.Fprintf(&buf, "=%s:%s ", formatNumber(numericAnswer.Value), formatNumber(numericAnswer.Tolerance))
+			}
+		default:
+			return nil, unsupportedError(i+1, question, "GIFT")
+		}
+
+		if question.Explanation != "" {
+			buf.WriteString("####" + escapeGIFT(question.Explanation))
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// trueFalseAnswer returns the correct answer of a true/false question from its generated True and False answers
+func trueFalseAnswer(question m.QuizQuestion) bool {
+	for _, answer := range question.Answers {
+		if answer.IsCorrect {
+			return strings.EqualFold(answer.AnswerText, "True")
+		}
+	}
+	return false
+}
+
+func escapeGIFT(text string) string {
+	var builder strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			builder.WriteString(`\n`)
+		case r == '\r':
+		case strings.ContainsRune(giftEscapedChars, r):
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+func unescapeGIFT(text string) string {
+	var builder strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+			if text[i] == 'n' {
+				builder.WriteByte('\n')
+				continue
+			}
+		}
+		builder.WriteByte(text[i])
+	}
+	return builder.String()
+}
+
+// indexUnescaped returns the index of the first occurrence of substr in text that is not escaped by a backslash
+func indexUnescaped(text string, substr string) int {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(text[i:], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnescaped splits text around the occurrences of separator that are not escaped by a backslash
+func splitUnescaped(text string, separator string) []string {
+	parts := []string{}
+	for {
+		index := indexUnescaped(text, separator)
+		if index < 0 {
+			return append(parts, text)
+		}
+		parts = append(parts, text[:index])
+		text = text[index+len(separator):]
+	}
+}
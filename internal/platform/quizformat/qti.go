@@ -0,0 +1,856 @@
+package quizformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	cf "orientation-training-api/configs"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+)
+
+// A QTI 2.1 import is a content package zip listing its items in imsmanifest.xml, the assessment test of the package
+// gives the order and the weight of the items, or a single assessmentItem XML file. Partial credit of a multiple choice
+// question is a response mapping, negative for wrong options under right minus wrong. Short answers are compared
+// following the caseSensitive attribute of their map entry, the ignore whitespace flag has no QTI equivalent.
+
+const (
+	qtiNamespace         = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	qtiManifestNamespace = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	qtiManifestFileName  = "imsmanifest.xml"
+	qtiTestFileName      = "assessment.xml"
+	qtiItemResourceType  = "imsqti_item_xmlv2p1"
+	qtiTestResourceType  = "imsqti_test_xmlv2p1"
+	qtiResponse          = "RESPONSE"
+	qtiTemplateMatch     = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"
+	qtiTemplateMap       = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"
+)
+
+type qtiManifest struct {
+	XMLName    xml.Name `xml:"manifest"`
+	Xmlns      string   `xml:"xmlns,attr,omitempty"`
+	Identifier string   `xml:"identifier,attr"`
+	Resources  struct {
+		Resource []qtiResource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+type qtiResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Type       string `xml:"type,attr"`
+	Href       string `xml:"href,attr"`
+	File       struct {
+		Href string `xml:"href,attr"`
+	} `xml:"file"`
+}
+
+type qtiTest struct {
+	XMLName    xml.Name      `xml:"assessmentTest"`
+	Xmlns      string        `xml:"xmlns,attr,omitempty"`
+	Identifier string        `xml:"identifier,attr"`
+	Title      string        `xml:"title,attr"`
+	TestParts  []qtiTestPart `xml:"testPart"`
+}
+
+type qtiTestPart struct {
+	Identifier     string       `xml:"identifier,attr"`
+	NavigationMode string       `xml:"navigationMode,attr"`
+	SubmissionMode string       `xml:"submissionMode,attr"`
+	Sections       []qtiSection `xml:"assessmentSection"`
+}
+
+type qtiSection struct {
+	Identifier string                 `xml:"identifier,attr"`
+	Title      string                 `xml:"title,attr"`
+	Visible    bool                   `xml:"visible,attr"`
+	Sections   []qtiSection           `xml:"assessmentSection"`
+	ItemRefs   []qtiAssessmentItemRef `xml:"assessmentItemRef"`
+}
+
+type qtiAssessmentItemRef struct {
+	Identifier string      `xml:"identifier,attr"`
+	Href       string      `xml:"href,attr"`
+	Weights    []qtiWeight `xml:"weight"`
+}
+
+type qtiWeight struct {
+	Identifier string  `xml:"identifier,attr"`
+	Value      float64 `xml:"value,attr"`
+}
+
+type qtiItem struct {
+	XMLName              xml.Name                 `xml:"assessmentItem"`
+	Xmlns                string                   `xml:"xmlns,attr,omitempty"`
+	Identifier           string                   `xml:"identifier,attr"`
+	Title                string                   `xml:"title,attr"`
+	Adaptive             bool                     `xml:"adaptive,attr"`
+	TimeDependent        bool                     `xml:"timeDependent,attr"`
+	ResponseDeclarations []qtiResponseDeclaration `xml:"responseDeclaration"`
+	OutcomeDeclarations  []qtiOutcomeDeclaration  `xml:"outcomeDeclaration"`
+	ItemBody             qtiItemBody              `xml:"itemBody"`
+	ResponseProcessing   *qtiResponseProcessing   `xml:"responseProcessing"`
+	ModalFeedbacks       []qtiModalFeedback       `xml:"modalFeedback"`
+}
+
+type qtiResponseDeclaration struct {
+	Identifier      string      `xml:"identifier,attr"`
+	Cardinality     string      `xml:"cardinality,attr"`
+	BaseType        string      `xml:"baseType,attr,omitempty"`
+	CorrectResponse *qtiValues  `xml:"correctResponse"`
+	Mapping         *qtiMapping `xml:"mapping"`
+}
+
+type qtiValues struct {
+	Values []string `xml:"value"`
+}
+
+type qtiMapping struct {
+	LowerBound   string        `xml:"lowerBound,attr,omitempty"`
+	DefaultValue float64       `xml:"defaultValue,attr"`
+	MapEntries   []qtiMapEntry `xml:"mapEntry"`
+}
+
+type qtiMapEntry struct {
+	MapKey        string  `xml:"mapKey,attr"`
+	MappedValue   float64 `xml:"mappedValue,attr"`
+	CaseSensitive string  `xml:"caseSensitive,attr,omitempty"`
+}
+
+type qtiOutcomeDeclaration struct {
+	Identifier  string `xml:"identifier,attr"`
+	Cardinality string `xml:"cardinality,attr"`
+	BaseType    string `xml:"baseType,attr"`
+}
+
+type qtiItemBody struct {
+	Paragraphs              []qtiParagraph              `xml:"p"`
+	ChoiceInteraction       *qtiChoiceInteraction       `xml:"choiceInteraction"`
+	ExtendedTextInteraction *qtiExtendedTextInteraction `xml:"extendedTextInteraction"`
+	MatchInteraction        *qtiMatchInteraction        `xml:"matchInteraction"`
+	OrderInteraction        *qtiOrderInteraction        `xml:"orderInteraction"`
+}
+
+type qtiParagraph struct {
+	Text                 string                   `xml:",chardata"`
+	TextEntryInteraction *qtiTextEntryInteraction `xml:"textEntryInteraction"`
+}
+
+type qtiChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type qtiChoiceInteraction struct {
+	ResponseIdentifier string      `xml:"responseIdentifier,attr"`
+	Shuffle            bool        `xml:"shuffle,attr"`
+	MaxChoices         int         `xml:"maxChoices,attr"`
+	Prompt             string      `xml:"prompt,omitempty"`
+	Choices            []qtiChoice `xml:"simpleChoice"`
+}
+
+type qtiTextEntryInteraction struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+}
+
+type qtiExtendedTextInteraction struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+	Prompt             string `xml:"prompt,omitempty"`
+}
+
+type qtiMatchInteraction struct {
+	ResponseIdentifier string        `xml:"responseIdentifier,attr"`
+	Shuffle            bool          `xml:"shuffle,attr"`
+	MaxAssociations    int           `xml:"maxAssociations,attr"`
+	Prompt             string        `xml:"prompt,omitempty"`
+	MatchSets          []qtiMatchSet `xml:"simpleMatchSet"`
+}
+
+type qtiMatchSet struct {
+	Choices []qtiChoice `xml:"simpleAssociableChoice"`
+}
+
+type qtiOrderInteraction struct {
+	ResponseIdentifier string      `xml:"responseIdentifier,attr"`
+	Shuffle            bool        `xml:"shuffle,attr"`
+	Prompt             string      `xml:"prompt,omitempty"`
+	Choices            []qtiChoice `xml:"simpleChoice"`
+}
+
+type qtiResponseProcessing struct {
+	Template string `xml:"template,attr,omitempty"`
+	Inner    []byte `xml:",innerxml"`
+}
+
+type qtiModalFeedback struct {
+	OutcomeIdentifier string `xml:"outcomeIdentifier,attr"`
+	Identifier        string `xml:"identifier,attr"`
+	ShowHide          string `xml:"showHide,attr"`
+	Text              string `xml:",chardata"`
+}
+
+// qtiItemLines are the lines where the parts of an item start
+type qtiItemLines struct {
+	item     int
+	response int
+	body     int
+}
+
+type qtiItemRef struct {
+	href   string
+	weight float64
+}
+
+func parseQTI(data []byte) ([]Question, []LineError) {
+	if !bytes.HasPrefix(data, []byte("PK")) {
+		question, lineErr := parseQTIItem("", data)
+		if lineErr != nil {
+			return nil, []LineError{*lineErr}
+		}
+		return []Question{question}, nil
+	}
+
+	files, err := readQTIPackage(data)
+	if err != nil {
+		return nil, []LineError{{Message: err.Error()}}
+	}
+
+	itemRefs, lineErr := qtiPackageItems(files)
+	if lineErr != nil {
+		return nil, []LineError{*lineErr}
+	}
+
+	questions := []Question{}
+	lineErrors := []LineError{}
+	for _, itemRef := range itemRefs {
+		itemData, ok := files[itemRef.href]
+		if !ok {
+			lineErrors = append(lineErrors, LineError{Source: qtiManifestFileName, Message: "Item " + itemRef.href + " is missing from the package"})
+			continue
+		}
+
+		question, lineErr := parseQTIItem(itemRef.href, itemData)
+		if lineErr != nil {
+			lineErrors = append(lineErrors, *lineErr)
+			continue
+		}
+		question.Weight = itemRef.weight
+		questions = append(questions, question)
+	}
+
+	return questions, lineErrors
+}
+
+func readQTIPackage(data []byte) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("QTI package is not a valid zip archive")
+	}
+
+	totalSize := uint64(0)
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		totalSize += f.UncompressedSize64
+		if totalSize > cf.QuizPackageMaxSize {
+			return nil, errors.New("QTI package is too large")
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files[path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))] = content
+	}
+
+	return files, nil
+}
+
+// qtiPackageItems returns the items of a package in the order of its assessment test, or of its manifest without a test
+func qtiPackageItems(files map[string][]byte) ([]qtiItemRef, *LineError) {
+	manifestData, ok := files[qtiManifestFileName]
+	if !ok {
+		return nil, &LineError{Message: "imsmanifest.xml not found at the root of the QTI package"}
+	}
+
+	manifest := qtiManifest{}
+	if err := xml.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, xmlLineError(qtiManifestFileName, err)
+	}
+
+	itemRefs := []qtiItemRef{}
+	for _, resource := range manifest.Resources.Resource {
+		if resource.Type != qtiTestResourceType {
+			continue
+		}
+
+		testHref := resourceHref(resource)
+		test := qtiTest{}
+		if err := xml.Unmarshal(files[testHref], &test); err != nil {
+			return nil, xmlLineError(testHref, err)
+		}
+
+		var addSection func(section qtiSection)
+		addSection = func(section qtiSection) {
+			for _, itemRef := range section.ItemRefs {
+				ref := qtiItemRef{href: path.Join(path.Dir(testHref), itemRef.Href)}
+				for _, weight := range itemRef.Weights {
+					ref.weight = weight.Value
+				}
+				itemRefs = append(itemRefs, ref)
+			}
+			for _, subsection := range section.Sections {
+				addSection(subsection)
+			}
+		}
+		for _, testPart := range test.TestParts {
+			for _, section := range testPart.Sections {
+				addSection(section)
+			}
+		}
+
+		return itemRefs, nil
+	}
+
+	for _, resource := range manifest.Resources.Resource {
+		if resource.Type == qtiItemResourceType {
+			itemRefs = append(itemRefs, qtiItemRef{href: resourceHref(resource)})
+		}
+	}
+
+	return itemRefs, nil
+}
+
+func resourceHref(resource qtiResource) string {
+	if resource.Href != "" {
+		return path.Clean(resource.Href)
+	}
+	return path.Clean(resource.File.Href)
+}
+
+// parseQTIItem reads an assessment item, its parts are decoded one by one to report the line of an invalid part
+func parseQTIItem(source string, data []byte) (Question, *LineError) {
+	item := qtiItem{}
+	lines := qtiItemLines{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Question{}, xmlLineError(source, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		line, _ := decoder.InputPos()
+
+		switch start.Name.Local {
+		case "assessmentItem":
+			lines.item = line
+			for _, attr := range start.Attr {
+				switch attr.Name.Local {
+				case "identifier":
+					item.Identifier = attr.Value
+				case "title":
+					item.Title = attr.Value
+				}
+			}
+			continue
+		case "responseDeclaration":
+			declaration := qtiResponseDeclaration{}
+			err = decoder.DecodeElement(&declaration, &start)
+			item.ResponseDeclarations = append(item.ResponseDeclarations, declaration)
+			if lines.response == 0 {
+				lines.response = line
+			}
+		case "itemBody":
+			err = decoder.DecodeElement(&item.ItemBody, &start)
+			lines.body = line
+		case "responseProcessing":
+			item.ResponseProcessing = &qtiResponseProcessing{}
+			err = decoder.DecodeElement(item.ResponseProcessing, &start)
+		case "modalFeedback":
+			feedback := qtiModalFeedback{}
+			err = decoder.DecodeElement(&feedback, &start)
+			item.ModalFeedbacks = append(item.ModalFeedbacks, feedback)
+		default:
+			err = decoder.Skip()
+		}
+		if err != nil {
+			return Question{}, xmlLineError(source, err)
+		}
+	}
+
+	if lines.item == 0 {
+		return Question{}, &LineError{Source: source, Line: 1, Message: "The file is not a QTI assessment item"}
+	}
+	if lines.response == 0 {
+		lines.response = lines.item
+	}
+	if lines.body == 0 {
+		lines.body = lines.item
+	}
+
+	question := Question{Source: source, Line: lines.item}
+	message, line := qtiQuestion(&question, item, lines)
+	if message != "" {
+		return Question{}, &LineError{Source: source, Line: line, Message: message}
+	}
+
+	return question, nil
+}
+
+// qtiQuestion fills a question from an item, the line tells where the item is invalid
+func qtiQuestion(question *Question, item qtiItem, lines qtiItemLines) (string, int) {
+	body := item.ItemBody
+
+	bodyText := []string{}
+	var textEntry *qtiTextEntryInteraction
+	for _, paragraph := range body.Paragraphs {
+		if text := strings.TrimSpace(paragraph.Text); text != "" {
+			bodyText = append(bodyText, text)
+		}
+		if paragraph.TextEntryInteraction != nil {
+			textEntry = paragraph.TextEntryInteraction
+		}
+	}
+
+	prompt := ""
+	responseIdentifier := ""
+	switch {
+	case body.ChoiceInteraction != nil:
+		prompt, responseIdentifier = body.ChoiceInteraction.Prompt, body.ChoiceInteraction.ResponseIdentifier
+	case body.ExtendedTextInteraction != nil:
+		prompt, responseIdentifier = body.ExtendedTextInteraction.Prompt, body.ExtendedTextInteraction.ResponseIdentifier
+	case body.MatchInteraction != nil:
+		prompt, responseIdentifier = body.MatchInteraction.Prompt, body.MatchInteraction.ResponseIdentifier
+	case body.OrderInteraction != nil:
+		prompt, responseIdentifier = body.OrderInteraction.Prompt, body.OrderInteraction.ResponseIdentifier
+	case textEntry != nil:
+		responseIdentifier = textEntry.ResponseIdentifier
+	default:
+		return "The item has no supported interaction: choice, text entry, extended text, match or order", lines.body
+	}
+
+	question.QuestionText = strings.TrimSpace(prompt)
+	if question.QuestionText == "" {
+		question.QuestionText = strings.Join(bodyText, " ")
+	}
+	if question.QuestionText == "" {
+		question.QuestionText = item.Title
+	}
+	for _, feedback := range item.ModalFeedbacks {
+		if text := strings.TrimSpace(feedback.Text); text != "" {
+			question.Explanation = text
+			break
+		}
+	}
+
+	declaration := qtiResponseDeclaration{}
+	for _, responseDeclaration := range item.ResponseDeclarations {
+		if responseDeclaration.Identifier == responseIdentifier {
+			declaration = responseDeclaration
+		}
+	}
+	correctValues := []string{}
+	if declaration.CorrectResponse != nil {
+		correctValues = declaration.CorrectResponse.Values
+	}
+
+	switch {
+	case body.ExtendedTextInteraction != nil:
+		question.QuestionType = cf.QuestionTypeEssay
+	case body.ChoiceInteraction != nil:
+		return qtiChoiceQuestion(question, *body.ChoiceInteraction, declaration, correctValues), lines.response
+	case body.MatchInteraction != nil:
+		question.QuestionType = cf.QuestionTypeMatching
+		if len(body.MatchInteraction.MatchSets) != 2 {
+			return "A match interaction needs two simpleMatchSet", lines.body
+		}
+
+		prompts := choiceTexts(body.MatchInteraction.MatchSets[0].Choices)
+		matches := choiceTexts(body.MatchInteraction.MatchSets[1].Choices)
+		for _, value := range correctValues {
+			fields := strings.Fields(value)
+			if len(fields) != 2 || prompts[fields[0]] == "" || matches[fields[1]] == "" {
+				return fmt.Sprintf("Invalid directed pair %q in the correct response", value), lines.response
+			}
+			question.MatchPairs = append(question.MatchPairs, param.MatchPairParam{
+				PromptText: prompts[fields[0]],
+				MatchText:  matches[fields[1]],
+			})
+		}
+	case body.OrderInteraction != nil:
+		question.QuestionType = cf.QuestionTypeOrdering
+		items := choiceTexts(body.OrderInteraction.Choices)
+		for _, value := range correctValues {
+			if items[value] == "" {
+				return fmt.Sprintf("Unknown choice %q in the correct response", value), lines.response
+			}
+			question.OrderItems = append(question.OrderItems, items[value])
+		}
+	case declaration.BaseType == "float" || declaration.BaseType == "integer":
+		question.QuestionType = cf.QuestionTypeNumeric
+		if item.ResponseProcessing != nil {
+			question.NumericAnswers = qtiNumericAnswers(item.ResponseProcessing.Inner)
+		}
+		if len(question.NumericAnswers) == 0 {
+			for _, value := range correctValues {
+				number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					return fmt.Sprintf("Invalid numeric value %q in the correct response", value), lines.response
+				}
+				question.NumericAnswers = append(question.NumericAnswers, param.NumericAnswerParam{Value: number})
+			}
+		}
+	default:
+		question.QuestionType = cf.QuestionTypeShortAnswer
+		if declaration.Mapping != nil && len(declaration.Mapping.MapEntries) > 0 {
+			for _, mapEntry := range declaration.Mapping.MapEntries {
+				if mapEntry.MappedValue <= 0 {
+					continue
+				}
+				question.AcceptedAnswers = append(question.AcceptedAnswers, param.AcceptedAnswerParam{
+					AnswerText:    mapEntry.MapKey,
+					CaseSensitive: mapEntry.CaseSensitive != "false",
+				})
+			}
+		} else {
+			for _, value := range correctValues {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, param.AcceptedAnswerParam{
+					AnswerText:    value,
+					CaseSensitive: true,
+				})
+			}
+		}
+	}
+
+	return "", 0
+}
+
+func qtiChoiceQuestion(question *Question, interaction qtiChoiceInteraction, declaration qtiResponseDeclaration, correctValues []string) string {
+	correct := map[string]bool{}
+	for _, value := range correctValues {
+		correct[strings.TrimSpace(value)] = true
+	}
+
+	if len(interaction.Choices) == 2 &&
+		strings.EqualFold(interaction.Choices[0].Identifier, "true") &&
+		strings.EqualFold(interaction.Choices[1].Identifier, "false") {
+		correctBoolean := correct[interaction.Choices[0].Identifier]
+		if !correctBoolean && !correct[interaction.Choices[1].Identifier] {
+			return "The correct response of the true/false item is missing"
+		}
+		question.QuestionType = cf.QuestionTypeTrueFalse
+		question.CorrectBoolean = &correctBoolean
+		return ""
+	}
+
+	question.QuestionType = cf.QuestionTypeMultipleChoice
+	question.AllowMultiple = declaration.Cardinality == "multiple"
+	for _, choice := range interaction.Choices {
+		question.Options = append(question.Options, param.QuizOption{
+			AnswersText: strings.TrimSpace(choice.Text),
+			IsCorrect:   correct[choice.Identifier],
+		})
+	}
+
+	if question.AllowMultiple && declaration.Mapping != nil {
+		question.ScoringStrategy = cf.ScoringProportional
+		for _, mapEntry := range declaration.Mapping.MapEntries {
+			if mapEntry.MappedValue < 0 {
+				question.ScoringStrategy = cf.ScoringRightMinusWrong
+			}
+		}
+	}
+
+	return ""
+}
+
+// qtiNumericAnswers reads the values and tolerances compared to the response in a response processing
+func qtiNumericAnswers(inner []byte) []param.NumericAnswerParam {
+	numericAnswers := []param.NumericAnswerParam{}
+	decoder := xml.NewDecoder(bytes.NewReader(inner))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return numericAnswers
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "equal" {
+			continue
+		}
+
+		equal := struct {
+			Tolerance  string   `xml:"tolerance,attr"`
+			BaseValues []string `xml:"baseValue"`
+		}{}
+		if err := decoder.DecodeElement(&equal, &start); err != nil || len(equal.BaseValues) == 0 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(equal.BaseValues[0]), 64)
+		if err != nil {
+			continue
+		}
+		numericAnswer := param.NumericAnswerParam{Value: value}
+		if tolerances := strings.Fields(equal.Tolerance); len(tolerances) > 0 {
+			numericAnswer.Tolerance, _ = strconv.ParseFloat(tolerances[0], 64)
+		}
+		numericAnswers = append(numericAnswers, numericAnswer)
+	}
+}
+
+func choiceTexts(choices []qtiChoice) map[string]string {
+	texts := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		texts[choice.Identifier] = strings.TrimSpace(choice.Text)
+	}
+	return texts
+}
+
+func xmlLineError(source string, err error) *LineError {
+	if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+		return &LineError{Source: source, Line: syntaxErr.Line, Message: syntaxErr.Msg}
+	}
+	return &LineError{Source: source, Message: err.Error()}
+}
+
+func writeQTI(title string, questions []m.QuizQuestion) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+
+	manifest := qtiManifest{Xmlns: qtiManifestNamespace, Identifier: "MANIFEST-QUIZ"}
+	testResource := qtiResource{Identifier: "TEST", Type: qtiTestResourceType, Href: qtiTestFileName}
+	testResource.File.Href = qtiTestFileName
+	manifest.Resources.Resource = append(manifest.Resources.Resource, testResource)
+
+	section := qtiSection{Identifier: "SECTION-1", Title: title, Visible: true}
+	for i, question := range questions {
+		identifier := fmt.Sprintf("ITEM-%d", i+1)
+		href := fmt.Sprintf("item-%d.xml", i+1)
+
+		item, supported := qtiItemFromQuestion(identifier, question)
+		if !supported {
+			return nil, unsupportedError(i+1, question, "QTI")
+		}
+		if err := writeXMLFile(writer, href, item); err != nil {
+			return nil, err
+		}
+
+		resource := qtiResource{Identifier: identifier, Type: qtiItemResourceType, Href: href}
+		resource.File.Href = href
+		manifest.Resources.Resource = append(manifest.Resources.Resource, resource)
+
+		section.ItemRefs = append(section.ItemRefs, qtiAssessmentItemRef{
+			Identifier: identifier,
+			Href:       href,
+			Weights:    []qtiWeight{{Identifier: "WEIGHT", Value: question.Weight}},
+		})
+	}
+
+	test := qtiTest{Xmlns: qtiNamespace, Identifier: "TEST", Title: title}
+	test.TestParts = []qtiTestPart{{
+		Identifier:     "PART-1",
+		NavigationMode: "linear",
+		SubmissionMode: "simultaneous",
+		Sections:       []qtiSection{section},
+	}}
+
+	if err := writeXMLFile(writer, qtiTestFileName, test); err != nil {
+		return nil, err
+	}
+	if err := writeXMLFile(writer, qtiManifestFileName, manifest); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// qtiItemFromQuestion builds the assessment item of a question, supported is false for the question types QTI can not hold
+func qtiItemFromQuestion(identifier string, question m.QuizQuestion) (item qtiItem, supported bool) {
+	item = qtiItem{
+		Xmlns:      qtiNamespace,
+		Identifier: identifier,
+		Title:      qtiTitle(question.QuestionText),
+		OutcomeDeclarations: []qtiOutcomeDeclaration{
+			{Identifier: "SCORE", Cardinality: "single", BaseType: "float"},
+		},
+		ResponseProcessing: &qtiResponseProcessing{Template: qtiTemplateMatch},
+	}
+	declaration := qtiResponseDeclaration{Identifier: qtiResponse, Cardinality: "single", CorrectResponse: &qtiValues{}}
+
+	switch question.QuestionType {
+	case cf.QuestionTypeMultipleChoice, cf.QuestionTypeTrueFalse:
+		declaration.BaseType = "identifier"
+		interaction := &qtiChoiceInteraction{ResponseIdentifier: qtiResponse, MaxChoices: 1, Prompt: question.QuestionText}
+
+		if question.QuestionType == cf.QuestionTypeTrueFalse {
+			correctBoolean := trueFalseAnswer(question)
+			interaction.Choices = []qtiChoice{{Identifier: "true", Text: "True"}, {Identifier: "false", Text: "False"}}
+			declaration.CorrectResponse.Values = []string{strconv.FormatBool(correctBoolean)}
+		} else {
+			correctCount := 0
+			for i, answer := range question.Answers {
+				choiceIdentifier := fmt.Sprintf("CHOICE-%d", i+1)
+				interaction.Choices = append(interaction.Choices, qtiChoice{Identifier: choiceIdentifier, Text: answer.AnswerText})
+				if answer.IsCorrect {
+					correctCount++
+					declaration.CorrectResponse.Values = append(declaration.CorrectResponse.Values, choiceIdentifier)
+				}
+			}
+
+			if question.IsMultipleCorrect {
+				declaration.Cardinality = "multiple"
+				interaction.MaxChoices = 0
+				if question.ScoringStrategy != cf.ScoringAllOrNothing && question.ScoringStrategy != "" {
+					declaration.Mapping = &qtiMapping{LowerBound: "0"}
+					for i, answer := range question.Answers {
+						mappedValue := 1 / float64(correctCount)
+						if !answer.IsCorrect {
+							mappedValue = 0
+							if question.ScoringStrategy == cf.ScoringRightMinusWrong {
+								mappedValue = -1 / float64(correctCount)
+							}
+						}
+						declaration.Mapping.MapEntries = append(declaration.Mapping.MapEntries, qtiMapEntry{
+							MapKey:      fmt.Sprintf("CHOICE-%d", i+1),
+							MappedValue: mappedValue,
+						})
+					}
+					item.ResponseProcessing.Template = qtiTemplateMap
+				}
+			}
+		}
+		item.ItemBody.ChoiceInteraction = interaction
+	case cf.QuestionTypeEssay:
+		declaration.BaseType = "string"
+		declaration.CorrectResponse = nil
+		item.ItemBody.ExtendedTextInteraction = &qtiExtendedTextInteraction{ResponseIdentifier: qtiResponse, Prompt: question.QuestionText}
+		item.ResponseProcessing = nil
+	case cf.QuestionTypeShortAnswer:
+		declaration.BaseType = "string"
+		declaration.Mapping = &qtiMapping{}
+		for _, acceptedAnswer := range question.AcceptedAnswers {
+			declaration.CorrectResponse.Values = append(declaration.CorrectResponse.Values, acceptedAnswer.AnswerText)
+			declaration.Mapping.MapEntries = append(declaration.Mapping.MapEntries, qtiMapEntry{
+				MapKey:        acceptedAnswer.AnswerText,
+				MappedValue:   1,
+				CaseSensitive: strconv.FormatBool(acceptedAnswer.CaseSensitive),
+			})
+		}
+		declaration.CorrectResponse.Values = declaration.CorrectResponse.Values[:1]
+		item.ItemBody.Paragraphs = []qtiParagraph{
+			{Text: question.QuestionText},
+			{TextEntryInteraction: &qtiTextEntryInteraction{ResponseIdentifier: qtiResponse}},
+		}
+		item.ResponseProcessing.Template = qtiTemplateMap
+	case cf.QuestionTypeNumeric:
+		declaration.BaseType = "float"
+		var conditions bytes.Buffer
+		for _, numericAnswer := range question.NumericAnswers {
+			declaration.CorrectResponse.Values = append(declaration.CorrectResponse.Values, formatNumber(numericAnswer.Value))
+			fmt.Fprintf(&conditions, `<equal toleranceMode="absolute" tolerance="%s %s"><variable identifier="%s"/><baseValue baseType="float">%s</baseValue></equal>`,
+				formatNumber(numericAnswer.Tolerance), formatNumber(numericAnswer.Tolerance), qtiResponse, formatNumber(numericAnswer.Value))
+		}
+		declaration.CorrectResponse.Values = declaration.CorrectResponse.Values[:1]
+		item.ItemBody.Paragraphs = []qtiParagraph{
+			{Text: question.QuestionText},
+			{TextEntryInteraction: &qtiTextEntryInteraction{ResponseIdentifier: qtiResponse}},
+		}
+		item.ResponseProcessing = &qtiResponseProcessing{Inner: []byte(fmt.Sprintf(
+			`<responseCondition><responseIf><or>%s</or><setOutcomeValue identifier="SCORE"><baseValue baseType="float">1</baseValue></setOutcomeValue></responseIf></responseCondition>`,
+			conditions.String(),
+		))}
+	case cf.QuestionTypeMatching:
+		declaration.BaseType = "directedPair"
+		declaration.Cardinality = "multiple"
+		interaction := &qtiMatchInteraction{ResponseIdentifier: qtiResponse, MaxAssociations: len(question.MatchPairs), Prompt: question.QuestionText}
+		interaction.MatchSets = make([]qtiMatchSet, 2)
+		for i, matchPair := range question.MatchPairs {
+			promptIdentifier := fmt.Sprintf("PROMPT-%d", i+1)
+			matchIdentifier := fmt.Sprintf("MATCH-%d", i+1)
+			interaction.MatchSets[0].Choices = append(interaction.MatchSets[0].Choices, qtiChoice{Identifier: promptIdentifier, Text: matchPair.PromptText})
+			interaction.MatchSets[1].Choices = append(interaction.MatchSets[1].Choices, qtiChoice{Identifier: matchIdentifier, Text: matchPair.MatchText})
+			declaration.CorrectResponse.Values = append(declaration.CorrectResponse.Values, promptIdentifier+" "+matchIdentifier)
+		}
+		item.ItemBody.MatchInteraction = interaction
+	case cf.QuestionTypeOrdering:
+		declaration.BaseType = "identifier"
+		declaration.Cardinality = "ordered"
+		interaction := &qtiOrderInteraction{ResponseIdentifier: qtiResponse, Shuffle: true, Prompt: question.QuestionText}
+		for i, orderItem := range question.OrderItems {
+			choiceIdentifier := fmt.Sprintf("ITEM-%d", i+1)
+			interaction.Choices = append(interaction.Choices, qtiChoice{Identifier: choiceIdentifier, Text: orderItem.ItemText})
+			declaration.CorrectResponse.Values = append(declaration.CorrectResponse.Values, choiceIdentifier)
+		}
+		item.ItemBody.OrderInteraction = interaction
+	default:
+		return item, false
+	}
+
+	item.ResponseDeclarations = []qtiResponseDeclaration{declaration}
+	if question.Explanation != "" {
+		item.ModalFeedbacks = []qtiModalFeedback{{
+			OutcomeIdentifier: "FEEDBACK",
+			Identifier:        "EXPLANATION",
+			ShowHide:          "show",
+			Text:              question.Explanation,
+		}}
+	}
+
+	return item, true
+}
+
+func writeXMLFile(writer *zip.Writer, name string, document interface{}) error {
+	file, err := writer.Create(name)
+	if err != nil {
+		return err
+	}
+
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	return err
+}
+
+// qtiTitle shortens the question text to the title of its item
+func qtiTitle(questionText string) string {
+	title := strings.Join(strings.Fields(questionText), " ")
+	if runes := []rune(title); len(runes) > 60 {
+		return string(runes[:60]) + "..."
+	}
+	return title
+}
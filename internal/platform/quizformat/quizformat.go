@@ -0,0 +1,157 @@
+package quizformat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cf "orientation-training-api/configs"
+	param "orientation-training-api/internal/interfaces/requestparams"
+	m "orientation-training-api/internal/models"
+)
+
+// Question is a question read from an import file, Source and Line tell where it is defined
+type Question struct {
+	param.QuizQuestion
+	Source string
+	Line   int
+}
+
+// LineError is an error found at a line of an import file, Source is the file of a QTI package
+type LineError struct {
+	Source  string `json:"source,omitempty"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e LineError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("%s line %d: %s", e.Source, e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Parse reads the questions of an import file in a quiz format. Questions without a weight share the quiz equally,
+// the weights are given to every question or to none.
+func Parse(format string, data []byte) ([]Question, []LineError) {
+	var questions []Question
+	var lineErrors []LineError
+
+	switch format {
+	case cf.QuizFormatGIFT:
+		questions, lineErrors = parseGIFT(data)
+	case cf.QuizFormatQTI:
+		questions, lineErrors = parseQTI(data)
+	case cf.QuizFormatCSV:
+		questions, lineErrors = parseCSV(data)
+	default:
+		return nil, []LineError{{Message: "Unsupported quiz format " + format}}
+	}
+
+	if len(lineErrors) == 0 && len(questions) == 0 {
+		lineErrors = append(lineErrors, LineError{Line: 1, Message: "The file does not contain any question"})
+	}
+	if len(lineErrors) > 0 {
+		return nil, lineErrors
+	}
+
+	return questions, resolveWeights(questions)
+}
+
+// Write writes the questions of a quiz in a quiz format, questions the format can not hold are reported by their position
+func Write(format string, title string, questions []m.QuizQuestion) ([]byte, error) {
+	switch format {
+	case cf.QuizFormatGIFT:
+		return writeGIFT(questions)
+	case cf.QuizFormatQTI:
+		return writeQTI(title, questions)
+	case cf.QuizFormatCSV:
+		return writeCSV(questions)
+	}
+
+	return nil, errors.New("Unsupported quiz format " + format)
+}
+
+// FormatFromFileName returns the quiz format of a file from its extension
+func FormatFromFileName(fileName string) string {
+	lowerName := strings.ToLower(fileName)
+
+	switch {
+	case strings.HasSuffix(lowerName, ".gift"), strings.HasSuffix(lowerName, ".txt"):
+		return cf.QuizFormatGIFT
+	case strings.HasSuffix(lowerName, ".xml"), strings.HasSuffix(lowerName, ".zip"):
+		return cf.QuizFormatQTI
+	case strings.HasSuffix(lowerName, ".csv"):
+		return cf.QuizFormatCSV
+	}
+
+	return ""
+}
+
+// FileExtension returns the extension of the export files of a quiz format
+func FileExtension(format string) string {
+	switch format {
+	case cf.QuizFormatGIFT:
+		return ".gift"
+	case cf.QuizFormatQTI:
+		return ".zip"
+	}
+	return "." + format
+}
+
+// ContentType returns the MIME type of the export files of a quiz format
+func ContentType(format string) string {
+	switch format {
+	case cf.QuizFormatGIFT:
+		return "text/plain"
+	case cf.QuizFormatQTI:
+		return "application/zip"
+	}
+	return "text/csv"
+}
+
+func resolveWeights(questions []Question) []LineError {
+	weighted := 0
+	for _, question := range questions {
+		if question.Weight > 0 {
+			weighted++
+		}
+	}
+
+	if weighted == 0 {
+		for i := range questions {
+			questions[i].Weight = 1 / float64(len(questions))
+		}
+		return nil
+	}
+
+	lineErrors := []LineError{}
+	if weighted < len(questions) {
+		for _, question := range questions {
+			if question.Weight <= 0 {
+				lineErrors = append(lineErrors, LineError{
+					Source:  question.Source,
+					Line:    question.Line,
+					Message: "weight is missing, give a weight to every question or to none",
+				})
+			}
+		}
+	}
+
+	return lineErrors
+}
+
+// unsupportedError reports a question a format can not hold by its position in the quiz
+func unsupportedError(position int, question m.QuizQuestion, format string) error {
+	return fmt.Errorf("Question %d: %s questions can not be exported to %s", position, cf.QuestionTypeLabels[question.QuestionType], format)
+}
+
+// unsupportedSettingError reports a setting of a question a format can not hold by its position in the quiz
+func unsupportedSettingError(position int, setting string, format string) error {
+	return fmt.Errorf("Question %d: %s can not be exported to %s", position, setting, format)
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg/v9"
+	"github.com/labstack/echo/v4"
+)
+
+// Job is a task run periodically by the scheduler
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler runs background jobs on a fixed interval until it is stopped. A run takes a database advisory lock
+// named after its job, so that a job runs on one instance at a time when several instances share the database.
+type Scheduler struct {
+	logger echo.Logger
+	db     *pg.DB
+	jobs   []Job
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(logger echo.Logger, db *pg.DB) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		db:     db,
+		quit:   make(chan struct{}),
+	}
+}
+
+// AddJob registers a job, it must be called before Start
+func (s *Scheduler) AddJob(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every job once and then on its interval
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+}
+
+// Stop signals the jobs to exit and waits for the running ones to finish
+func (s *Scheduler) Stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(job)
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Errorf("Scheduled job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	// Session advisory locks belong to a connection, the lock is taken and released on the same one
+	conn := s.db.Conn()
+	defer conn.Close()
+
+	lockName := "scheduler:" + job.Name
+	var locked bool
+	if _, err := conn.QueryOne(pg.Scan(&locked), "SELECT pg_try_advisory_lock(hashtext(?))", lockName); err != nil {
+		s.logger.Errorf("Scheduled job %s could not take its lock: %v", job.Name, err)
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if _, err := conn.Exec("SELECT pg_advisory_unlock(hashtext(?))", lockName); err != nil {
+			s.logger.Errorf("Scheduled job %s could not release its lock: %v", job.Name, err)
+		}
+	}()
+
+	if err := job.Run(); err != nil {
+		s.logger.Errorf("Scheduled job %s failed: %v", job.Name, err)
+	}
+}
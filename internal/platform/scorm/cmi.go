@@ -0,0 +1,243 @@
+package scorm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	cf "orientation-training-api/configs"
+)
+
+// CMI data model element names per SCORM version
+type cmiKeys struct {
+	LessonStatus     string
+	CompletionStatus string
+	SuccessStatus    string
+	ScoreRaw         string
+	ScoreMin         string
+	ScoreMax         string
+	ScoreScaled      string
+	SuspendData      string
+	Location         string
+	SessionTime      string
+	TotalTime        string
+}
+
+var cmiKeys12 = cmiKeys{
+	LessonStatus: "cmi.core.lesson_status",
+	ScoreRaw:     "cmi.core.score.raw",
+	ScoreMin:     "cmi.core.score.min",
+	ScoreMax:     "cmi.core.score.max",
+	SuspendData:  "cmi.suspend_data",
+	Location:     "cmi.core.lesson_location",
+	SessionTime:  "cmi.core.session_time",
+	TotalTime:    "cmi.core.total_time",
+}
+
+var cmiKeys2004 = cmiKeys{
+	CompletionStatus: "cmi.completion_status",
+	SuccessStatus:    "cmi.success_status",
+	ScoreRaw:         "cmi.score.raw",
+	ScoreMin:         "cmi.score.min",
+	ScoreMax:         "cmi.score.max",
+	ScoreScaled:      "cmi.score.scaled",
+	SuspendData:      "cmi.suspend_data",
+	Location:         "cmi.location",
+	SessionTime:      "cmi.session_time",
+	TotalTime:        "cmi.total_time",
+}
+
+func keysFor(version string) cmiKeys {
+	if version == cf.ScormVersion2004 {
+		return cmiKeys2004
+	}
+	return cmiKeys12
+}
+
+// RuntimeState is the tracked subset of the CMI data model for one learner and SCO
+type RuntimeState struct {
+	LessonStatus     string
+	CompletionStatus string
+	SuccessStatus    string
+	ScoreRaw         float64
+	ScoreMin         float64
+	ScoreMax         float64
+	ScoreScaled      float64
+	SuspendData      string
+	LessonLocation   string
+	TotalTime        int
+	// SessionTime is the session time of the current session already counted in TotalTime
+	SessionTime int
+}
+
+// ApplyCMI merges values committed by the SCO into the state and returns the session time in seconds.
+// The session time grows during a session, only what it adds to the session time already counted goes to the total time.
+func ApplyCMI(version string, state *RuntimeState, values map[string]string) int {
+	keys := keysFor(version)
+
+	if v, ok := values[keys.LessonStatus]; ok && keys.LessonStatus != "" {
+		state.LessonStatus = v
+	}
+	if v, ok := values[keys.CompletionStatus]; ok && keys.CompletionStatus != "" {
+		state.CompletionStatus = v
+	}
+	if v, ok := values[keys.SuccessStatus]; ok && keys.SuccessStatus != "" {
+		state.SuccessStatus = v
+	}
+	if v, ok := values[keys.ScoreRaw]; ok {
+		state.ScoreRaw = parseFloat(v)
+	}
+	if v, ok := values[keys.ScoreMin]; ok {
+		state.ScoreMin = parseFloat(v)
+	}
+	if v, ok := values[keys.ScoreMax]; ok {
+		state.ScoreMax = parseFloat(v)
+	}
+	if v, ok := values[keys.ScoreScaled]; ok && keys.ScoreScaled != "" {
+		state.ScoreScaled = parseFloat(v)
+	}
+	if v, ok := values[keys.SuspendData]; ok {
+		state.SuspendData = v
+	}
+	if v, ok := values[keys.Location]; ok {
+		state.LessonLocation = v
+	}
+
+	sessionTime := 0
+	if v, ok := values[keys.SessionTime]; ok {
+		sessionTime = ParseSessionTime(v)
+		// A session time below the one counted comes from a session that was not initialized by the LMS
+		if sessionTime >= state.SessionTime {
+			state.TotalTime += sessionTime - state.SessionTime
+		} else {
+			state.TotalTime += sessionTime
+		}
+		state.SessionTime = sessionTime
+	}
+
+	return sessionTime
+}
+
+// InitialCMI builds the data model values handed to the SCO on LMSInitialize / Initialize
+func InitialCMI(version string, state RuntimeState, learnerID int, learnerName string) map[string]string {
+	keys := keysFor(version)
+	values := make(map[string]string)
+
+	entry := "resume"
+	if state.SuspendData == "" && state.LessonLocation == "" {
+		entry = "ab-initio"
+	}
+
+	if version == cf.ScormVersion2004 {
+		values["cmi.learner_id"] = strconv.Itoa(learnerID)
+		values["cmi.learner_name"] = learnerName
+		values["cmi.entry"] = entry
+		values["cmi.mode"] = "normal"
+		values["cmi.credit"] = "credit"
+		values[keys.CompletionStatus] = defaultString(state.CompletionStatus, cf.ScormStatusUnknown)
+		values[keys.SuccessStatus] = defaultString(state.SuccessStatus, cf.ScormStatusUnknown)
+		values[keys.TotalTime] = formatDuration2004(state.TotalTime)
+	} else {
+		values["cmi.core.student_id"] = strconv.Itoa(learnerID)
+		values["cmi.core.student_name"] = learnerName
+		values["cmi.core.entry"] = entry
+		values["cmi.core.lesson_mode"] = "normal"
+		values["cmi.core.credit"] = "credit"
+		values[keys.LessonStatus] = defaultString(state.LessonStatus, cf.ScormStatusNotAttempted)
+		values[keys.TotalTime] = formatDuration12(state.TotalTime)
+	}
+
+	values[keys.SuspendData] = state.SuspendData
+	values[keys.Location] = state.LessonLocation
+	if state.ScoreRaw != 0 || state.ScoreMax != 0 {
+		values[keys.ScoreRaw] = formatFloat(state.ScoreRaw)
+		values[keys.ScoreMin] = formatFloat(state.ScoreMin)
+		values[keys.ScoreMax] = formatFloat(state.ScoreMax)
+	}
+
+	return values
+}
+
+// IsCompleted reports whether the runtime state counts as finished for course progress
+func IsCompleted(version string, state RuntimeState) bool {
+	if version == cf.ScormVersion2004 {
+		return state.CompletionStatus == cf.ScormStatusCompleted || state.SuccessStatus == cf.ScormSuccessStatusPassed
+	}
+
+	return state.LessonStatus == cf.ScormStatusCompleted || state.LessonStatus == cf.ScormStatusPassed
+}
+
+// ParseSessionTime converts a SCORM 1.2 (HHHH:MM:SS.SS) or 2004 (ISO 8601 duration) time span to seconds
+func ParseSessionTime(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if strings.HasPrefix(value, "P") {
+		return parseISODuration(value)
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	hours, _ := strconv.Atoi(parts[0])
+	minutes, _ := strconv.Atoi(parts[1])
+	seconds := parseFloat(parts[2])
+
+	return hours*3600 + minutes*60 + int(seconds)
+}
+
+var isoDurationRegex = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+func parseISODuration(value string) int {
+	match := isoDurationRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0
+	}
+
+	years, _ := strconv.Atoi(match[1])
+	months, _ := strconv.Atoi(match[2])
+	days, _ := strconv.Atoi(match[3])
+	hours, _ := strconv.Atoi(match[4])
+	minutes, _ := strconv.Atoi(match[5])
+	seconds := parseFloat(match[6])
+
+	total := time.Duration(years*365+months*30+days)*24*time.Hour +
+		time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+
+	return int(total.Seconds())
+}
+
+func formatDuration12(seconds int) string {
+	return fmt.Sprintf("%04d:%02d:%02d", seconds/3600, (seconds%3600)/60, seconds%60)
+}
+
+func formatDuration2004(seconds int) string {
+	return fmt.Sprintf("PT%dH%dM%dS", seconds/3600, (seconds%3600)/60, seconds%60)
+}
+
+func parseFloat(value string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func defaultString(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,49 @@
+package scorm
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewContentToken signs the access of a user to the files of a SCORM module item until expiresAt.
+// The token is a path segment of the launch URL so that the relative URLs of the package keep it.
+func NewContentToken(secret string, userID int, moduleItemID int, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	return fmt.Sprintf("%d-%d-%s", userID, expires, contentSignature(secret, userID, moduleItemID, expires))
+}
+
+// ParseContentToken returns the user a content token was signed for, it reports false when the token
+// is invalid, was signed for another module item or has expired
+func ParseContentToken(secret string, token string, moduleItemID int, now time.Time) (int, bool) {
+	parts := strings.Split(token, "-")
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, false
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return 0, false
+	}
+
+	expected := contentSignature(secret, userID, moduleItemID, expires)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func contentSignature(secret string, userID int, moduleItemID int, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d:%d:%d", userID, moduleItemID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,235 @@
+package scorm
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	cf "orientation-training-api/configs"
+)
+
+const manifestFileName = "imsmanifest.xml"
+
+// PackageFile is a single file extracted from a SCORM package
+type PackageFile struct {
+	Path string
+	Data []byte
+}
+
+// Package is the result of reading a SCORM zip
+type Package struct {
+	Version    string
+	Identifier string
+	Title      string
+	LaunchPath string
+	Files      []PackageFile
+}
+
+type manifest struct {
+	XMLName    xml.Name `xml:"manifest"`
+	Identifier string   `xml:"identifier,attr"`
+	Metadata   struct {
+		Schema        string `xml:"schema"`
+		SchemaVersion string `xml:"schemaversion"`
+	} `xml:"metadata"`
+	Organizations struct {
+		Default      string         `xml:"default,attr"`
+		Organization []organization `xml:"organization"`
+	} `xml:"organizations"`
+	Resources struct {
+		Base     string     `xml:"base,attr"`
+		Resource []resource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+type organization struct {
+	Identifier string `xml:"identifier,attr"`
+	Title      string `xml:"title"`
+	Items      []item `xml:"item"`
+}
+
+type item struct {
+	Identifier    string `xml:"identifier,attr"`
+	IdentifierRef string `xml:"identifierref,attr"`
+	Parameters    string `xml:"parameters,attr"`
+	Title         string `xml:"title"`
+	Items         []item `xml:"item"`
+}
+
+type resource struct {
+	Identifier    string `xml:"identifier,attr"`
+	Href          string `xml:"href,attr"`
+	Base          string `xml:"base,attr"`
+	ScormType12   string `xml:"scormtype,attr"`
+	ScormType2004 string `xml:"scormType,attr"`
+}
+
+func (r resource) isSco() bool {
+	return strings.EqualFold(r.ScormType12, "sco") || strings.EqualFold(r.ScormType2004, "sco")
+}
+
+// ReadPackage unzips a SCORM package, validates its imsmanifest.xml and resolves the launch file
+func ReadPackage(data []byte) (*Package, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("SCORM package is not a valid zip archive")
+	}
+
+	totalSize := uint64(0)
+	files := []PackageFile{}
+	fileSet := make(map[string]bool)
+	var manifestData []byte
+
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		name := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		if strings.HasPrefix(name, "/") || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.New("SCORM package contains an invalid file path: " + f.Name)
+		}
+
+		totalSize += f.UncompressedSize64
+		if totalSize > cf.ScormMaxPackageSize {
+			return nil, errors.New("SCORM package is too large")
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if name == manifestFileName {
+			manifestData = content
+		}
+
+		files = append(files, PackageFile{Path: name, Data: content})
+		fileSet[name] = true
+	}
+
+	if manifestData == nil {
+		return nil, errors.New("imsmanifest.xml not found at the root of the SCORM package")
+	}
+
+	mf := manifest{}
+	if err := xml.Unmarshal(manifestData, &mf); err != nil {
+		return nil, errors.New("imsmanifest.xml is not valid XML: " + err.Error())
+	}
+
+	version := detectVersion(mf, string(manifestData))
+	if version == "" {
+		return nil, errors.New("Unsupported SCORM version. Allowed versions: 1.2, 2004")
+	}
+
+	org, err := defaultOrganization(mf)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string]resource)
+	for _, res := range mf.Resources.Resource {
+		resources[res.Identifier] = res
+	}
+
+	launchItem, launchResource := findLaunchItem(org.Items, resources)
+	if launchResource == nil {
+		return nil, errors.New("imsmanifest.xml does not reference a launchable resource")
+	}
+
+	launchFile := path.Clean(mf.Resources.Base + launchResource.Base + launchResource.Href)
+	if queryIndex := strings.IndexAny(launchFile, "?#"); queryIndex >= 0 {
+		launchFile = launchFile[:queryIndex]
+	}
+	if !fileSet[launchFile] {
+		return nil, errors.New("Launch file " + launchFile + " is missing from the SCORM package")
+	}
+
+	launchPath := mf.Resources.Base + launchResource.Base + launchResource.Href + launchItem.Parameters
+
+	title := org.Title
+	if title == "" {
+		title = launchItem.Title
+	}
+
+	return &Package{
+		Version:    version,
+		Identifier: mf.Identifier,
+		Title:      title,
+		LaunchPath: launchPath,
+		Files:      files,
+	}, nil
+}
+
+func detectVersion(mf manifest, raw string) string {
+	schemaVersion := strings.TrimSpace(mf.Metadata.SchemaVersion)
+
+	switch {
+	case schemaVersion == "1.2":
+		return cf.ScormVersion12
+	case strings.Contains(schemaVersion, "2004"), schemaVersion == "CAM 1.3":
+		return cf.ScormVersion2004
+	case strings.Contains(raw, "adlcp_rootv1p2"):
+		return cf.ScormVersion12
+	case strings.Contains(raw, "adlcp_v1p3"):
+		return cf.ScormVersion2004
+	}
+
+	return ""
+}
+
+func defaultOrganization(mf manifest) (organization, error) {
+	if len(mf.Organizations.Organization) == 0 {
+		return organization{}, errors.New("imsmanifest.xml does not define any organization")
+	}
+
+	for _, org := range mf.Organizations.Organization {
+		if org.Identifier == mf.Organizations.Default {
+			return org, nil
+		}
+	}
+
+	return mf.Organizations.Organization[0], nil
+}
+
+// findLaunchItem walks the organization tree depth-first and returns the first item
+// pointing to a resource with an href, preferring SCOs over plain assets
+func findLaunchItem(items []item, resources map[string]resource) (item, *resource) {
+	var assetItem item
+	var assetResource *resource
+
+	var walk func(items []item) (item, *resource)
+	walk = func(items []item) (item, *resource) {
+		for _, it := range items {
+			if res, ok := resources[it.IdentifierRef]; ok && res.Href != "" {
+				if res.isSco() {
+					return it, &res
+				}
+				if assetResource == nil {
+					assetItem = it
+					assetResource = &res
+				}
+			}
+
+			if found, res := walk(it.Items); res != nil {
+				return found, res
+			}
+		}
+		return item{}, nil
+	}
+
+	if found, res := walk(items); res != nil {
+		return found, res
+	}
+
+	return assetItem, assetResource
+}
@@ -110,3 +110,53 @@ func ParseDurationToSeconds(duration string) int {
 // 	}
 // 	return parsedTime.Format("2006/01/02"), nil
 // }
+
+// CreatesCycle : check whether adding the edges from -> each of targets to a directed graph creates a cycle
+// Params    : graph (node -> nodes it points to), from, targets
+// Returns   : bool
+func CreatesCycle(graph map[int][]int, from int, targets []int) bool {
+	visited := make(map[int]bool)
+
+	var reaches func(node int) bool
+	reaches = func(node int) bool {
+		if node == from {
+			return true
+		}
+		if visited[node] {
+			return false
+		}
+		visited[node] = true
+
+		for _, next := range graph[node] {
+			if reaches(next) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, target := range targets {
+		if reaches(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FormatDueDate keeps the YYYY-MM-DD part of a due date read from the database
+func FormatDueDate(dueDate string) string {
+	if len(dueDate) > 10 {
+		return dueDate[:10]
+	}
+	return dueDate
+}
+
+// IsOverdue checks whether an uncompleted enrollment is past its due date
+func IsOverdue(dueDate string, completed bool) bool {
+	if completed || dueDate == "" {
+		return false
+	}
+
+	return FormatDueDate(dueDate) < TimeNowUTC().Format("2006-01-02")
+}
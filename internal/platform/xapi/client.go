@@ -0,0 +1,66 @@
+package xapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// LRSClient forwards statements to an external Learning Record Store
+type LRSClient struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewLRSClient returns a client configured from XAPI_LRS_ENDPOINT, XAPI_LRS_USERNAME
+// and XAPI_LRS_PASSWORD, or nil when no external LRS is configured
+func NewLRSClient() *LRSClient {
+	endpoint := os.Getenv("XAPI_LRS_ENDPOINT")
+	if endpoint == "" {
+		return nil
+	}
+
+	return &LRSClient{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		username:   os.Getenv("XAPI_LRS_USERNAME"),
+		password:   os.Getenv("XAPI_LRS_PASSWORD"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendStatement posts a single statement to the external LRS
+func (client *LRSClient) SendStatement(statement *Statement) error {
+	body, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, client.endpoint+"/statements", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Experience-API-Version", Version)
+	if client.username != "" {
+		req.SetBasicAuth(client.username, client.password)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("external LRS rejected statement %s, status code: %d", statement.ID, resp.StatusCode)
+	}
+
+	return nil
+}
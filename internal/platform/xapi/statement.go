@@ -0,0 +1,215 @@
+package xapi
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the xAPI specification version used by the statements
+const Version = "1.0.3"
+
+// Verb IRIs from the ADL vocabulary
+const (
+	VerbLaunched   = "http://adlnet.gov/expapi/verbs/launched"
+	VerbProgressed = "http://adlnet.gov/expapi/verbs/progressed"
+	VerbCompleted  = "http://adlnet.gov/expapi/verbs/completed"
+	VerbAttempted  = "http://adlnet.gov/expapi/verbs/attempted"
+	VerbPassed     = "http://adlnet.gov/expapi/verbs/passed"
+	VerbFailed     = "http://adlnet.gov/expapi/verbs/failed"
+	VerbScored     = "http://adlnet.gov/expapi/verbs/scored"
+)
+
+// Activity type IRIs
+const (
+	ActivityTypeCourse     = "http://adlnet.gov/expapi/activities/course"
+	ActivityTypeModule     = "http://adlnet.gov/expapi/activities/module"
+	ActivityTypeAssessment = "http://adlnet.gov/expapi/activities/assessment"
+	ActivityTypeQuestion   = "http://adlnet.gov/expapi/activities/question"
+	ActivityTypeLesson     = "http://adlnet.gov/expapi/activities/lesson"
+)
+
+// Context extension IRIs
+const (
+	ExtensionModulePosition     = "http://orientation-training/xapi/extensions/module_position"
+	ExtensionModuleItemPosition = "http://orientation-training/xapi/extensions/module_item_position"
+	ExtensionAttempt            = "http://orientation-training/xapi/extensions/attempt"
+)
+
+var verbDisplays = map[string]string{
+	VerbLaunched:   "launched",
+	VerbProgressed: "progressed",
+	VerbCompleted:  "completed",
+	VerbAttempted:  "attempted",
+	VerbPassed:     "passed",
+	VerbFailed:     "failed",
+	VerbScored:     "scored",
+}
+
+// Statement is an xAPI statement
+type Statement struct {
+	ID        string   `json:"id"`
+	Actor     Agent    `json:"actor"`
+	Verb      Verb     `json:"verb"`
+	Object    Activity `json:"object"`
+	Result    *Result  `json:"result,omitempty"`
+	Context   *Context `json:"context,omitempty"`
+	Timestamp string   `json:"timestamp"`
+	Stored    string   `json:"stored,omitempty"`
+	Version   string   `json:"version,omitempty"`
+}
+
+// Agent identifies a learner or an instructor
+type Agent struct {
+	ObjectType string `json:"objectType"`
+	Name       string `json:"name,omitempty"`
+	Mbox       string `json:"mbox"`
+}
+
+// Verb is the action of a statement
+type Verb struct {
+	ID      string            `json:"id"`
+	Display map[string]string `json:"display"`
+}
+
+// Activity is the object of a statement
+type Activity struct {
+	ObjectType string              `json:"objectType"`
+	ID         string              `json:"id"`
+	Definition *ActivityDefinition `json:"definition,omitempty"`
+}
+
+// ActivityDefinition describes an activity
+type ActivityDefinition struct {
+	Name map[string]string `json:"name,omitempty"`
+	Type string            `json:"type,omitempty"`
+}
+
+// Result is the outcome of a statement
+type Result struct {
+	Score      *Score `json:"score,omitempty"`
+	Success    *bool  `json:"success,omitempty"`
+	Completion *bool  `json:"completion,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+}
+
+// Score is the score of a result
+type Score struct {
+	Scaled float64 `json:"scaled"`
+	Raw    float64 `json:"raw"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// Context gives additional information about a statement
+type Context struct {
+	Instructor        *Agent                 `json:"instructor,omitempty"`
+	Registration      string                 `json:"registration,omitempty"`
+	ContextActivities map[string]interface{} `json:"contextActivities,omitempty"`
+	Extensions        map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// NewAgent builds an agent identified by email
+func NewAgent(email string, name string) Agent {
+	return Agent{
+		ObjectType: "Agent",
+		Name:       strings.TrimSpace(name),
+		Mbox:       "mailto:" + email,
+	}
+}
+
+// NewVerb builds a verb with its English display
+func NewVerb(verbID string) Verb {
+	return Verb{
+		ID:      verbID,
+		Display: map[string]string{"en-US": verbDisplays[verbID]},
+	}
+}
+
+// NewActivity builds an activity whose IRI is derived from XAPI_ACTIVITY_BASE_URL
+func NewActivity(activityType string, id int, name string) Activity {
+	return Activity{
+		ObjectType: "Activity",
+		ID:         activityIRI(activityType, id),
+		Definition: &ActivityDefinition{
+			Name: activityName(name),
+			Type: activityType,
+		},
+	}
+}
+
+func activityName(name string) map[string]string {
+	if name == "" {
+		return nil
+	}
+	return map[string]string{"en-US": name}
+}
+
+// NewStatement builds a statement with a generated id and the current timestamp
+func NewStatement(actor Agent, verbID string, object Activity) *Statement {
+	return &Statement{
+		ID:        NewUUID(),
+		Actor:     actor,
+		Verb:      NewVerb(verbID),
+		Object:    object,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Version:   Version,
+	}
+}
+
+// WithParent adds a parent activity to the statement context
+func (statement *Statement) WithParent(parent Activity) *Statement {
+	if statement.Context == nil {
+		statement.Context = &Context{}
+	}
+	statement.Context.ContextActivities = map[string]interface{}{
+		"parent": []Activity{parent},
+	}
+	return statement
+}
+
+// NewScoreResult builds a result with a raw score scaled against maxScore
+func NewScoreResult(raw float64, maxScore float64, success *bool, completion bool) *Result {
+	scaled := 0.0
+	if maxScore > 0 {
+		scaled = raw / maxScore
+	}
+
+	return &Result{
+		Score: &Score{
+			Scaled: scaled,
+			Raw:    raw,
+			Min:    0,
+			Max:    maxScore,
+		},
+		Success:    success,
+		Completion: &completion,
+	}
+}
+
+// NewUUID generates a random (version 4) UUID
+func NewUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func activityIRI(activityType string, id int) string {
+	baseURL := os.Getenv("XAPI_ACTIVITY_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://orientation-training/xapi/activities"
+	}
+
+	typeName := activityType[strings.LastIndex(activityType, "/")+1:]
+
+	return strings.TrimRight(baseURL, "/") + "/" + typeName + "/" + strconv.Itoa(id)
+}